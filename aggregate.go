@@ -0,0 +1,88 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Aggregate summarizes the errors from many validations, such as those of
+// the records in a bulk import, by counting the failures of each rule and
+// keeping only the first few examples of each. It is safe for concurrent use.
+type Aggregate struct {
+	max int
+
+	mu    sync.Mutex
+	rules map[string]*RuleSummary
+	total int
+}
+
+// RuleSummary counts the failures of a single rule.
+type RuleSummary struct {
+	Rule     string
+	Count    int
+	Examples []error // at most the Aggregate's limit, in the order added
+}
+
+func (r RuleSummary) String() string {
+	return fmt.Sprintf("rule %q failed %d times, first %d examples: %v", r.Rule, r.Count, len(r.Examples), r.Examples)
+}
+
+// NewAggregate returns an Aggregate that keeps at most max examples per rule.
+func NewAggregate(max int) *Aggregate {
+	return &Aggregate{max: max, rules: make(map[string]*RuleSummary)}
+}
+
+// Add counts the errors returned from a validation. Errors other than
+// BadField, and BadFields without a Rule, are counted under the rule "".
+func (a *Aggregate) Add(errs []error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, err := range errs {
+		rule := ""
+		if bf, ok := err.(BadField); ok {
+			rule = bf.Rule
+		}
+		s := a.rules[rule]
+		if s == nil {
+			s = &RuleSummary{Rule: rule}
+			a.rules[rule] = s
+		}
+		s.Count++
+		if len(s.Examples) < a.max {
+			s.Examples = append(s.Examples, err)
+		}
+		a.total++
+	}
+}
+
+// Total returns the number of errors added.
+func (a *Aggregate) Total() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.total
+}
+
+// Summaries returns a summary of each rule that failed,
+// with the most frequent failures first.
+func (a *Aggregate) Summaries() []RuleSummary {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ss := make([]RuleSummary, 0, len(a.rules))
+	for _, s := range a.rules {
+		c := *s
+		c.Examples = append([]error(nil), s.Examples...)
+		ss = append(ss, c)
+	}
+	sort.Slice(ss, func(i, j int) bool {
+		if ss[i].Count != ss[j].Count {
+			return ss[i].Count > ss[j].Count
+		}
+		return ss[i].Rule < ss[j].Rule
+	})
+	return ss
+}
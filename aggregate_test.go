@@ -0,0 +1,55 @@
+package validate
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAggregate(t *testing.T) {
+	type X struct {
+		A int `validate:"odd"`
+		B int `validate:"odd,positive"`
+	}
+
+	vd := make(V)
+	vd["odd"] = func(i interface{}) error {
+		n := i.(int)
+		if n&1 == 0 {
+			return fmt.Errorf("%d is not odd", n)
+		}
+		return nil
+	}
+	vd["positive"] = func(i interface{}) error {
+		if i.(int) <= 0 {
+			return fmt.Errorf("not positive")
+		}
+		return nil
+	}
+
+	agg := NewAggregate(2)
+	for i := 0; i < 10; i++ {
+		agg.Add(vd.Validate(X{A: i, B: -1}))
+	}
+	agg.Add([]error{fmt.Errorf("not a BadField")})
+
+	if agg.Total() != 16 {
+		t.Fatalf("wrong total: %d", agg.Total())
+	}
+
+	ss := agg.Summaries()
+	if len(ss) != 3 {
+		t.Fatalf("wrong number of summaries: %v", ss)
+	}
+	if ss[0].Rule != "positive" || ss[0].Count != 10 || len(ss[0].Examples) != 2 {
+		t.Fatalf("wrong first summary: %v", ss[0])
+	}
+	if ss[1].Rule != "odd" || ss[1].Count != 5 {
+		t.Fatalf("wrong second summary: %v", ss[1])
+	}
+	if ss[1].Examples[0].Error() != "field A is invalid: 0 is not odd" {
+		t.Fatalf("wrong first example: %v", ss[1].Examples[0])
+	}
+	if ss[2].Rule != "" || ss[2].Count != 1 {
+		t.Fatalf("wrong summary for other errors: %v", ss[2])
+	}
+}
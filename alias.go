@@ -0,0 +1,112 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Alias adds an alias to v, so that name can be used in tags in place of
+// the rules in tag, which is written as a validate tag:
+//
+//	vd.Alias("iso_date", "nonempty,dateformat=2006-01-02")
+//
+//	type Event struct {
+//		Day string `validate:"iso_date"`
+//	}
+//
+// The rules are put in place of the alias wherever it is named, so an
+// alias for "each,long" makes the rules following the alias apply to
+// elements too. Aliases may name other aliases, but not themselves,
+// directly or indirectly; such cycles, and aliases given parameters,
+// are reported as errors for the fields naming them. An alias used as an
+// alternative must stand for a single rule.
+//
+// The alias is kept alongside the validators in v, not among them.
+// Alias returns an error if tag cannot be parsed, or if name is that of
+// a reserved rule.
+func (v V) Alias(name, tag string) error {
+	if Reserved(name) {
+		return fmt.Errorf("alias %q: name of a reserved rule", name)
+	}
+	if _, err := ParseTag(tag); err != nil {
+		return fmt.Errorf("alias %q: %v", name, err)
+	}
+	m := v.editMeta()
+	if m.aliases == nil {
+		m.aliases = make(map[string]string)
+	}
+	m.aliases[name] = tag
+	return nil
+}
+
+// alias returns the tag the alias name stands for, if it is one.
+func (w *walker) alias(name string) (string, bool) {
+	ls, _ := w.layers()
+	for _, l := range ls {
+		if l.m != nil {
+			if tag, ok := l.m.aliases[name]; ok {
+				return tag, true
+			}
+		}
+		if l.v[name] != nil || l.v[name+"="] != nil {
+			break
+		}
+	}
+	return "", false
+}
+
+// expand replaces the aliases in rules with the rules they stand for.
+// The aliases being expanded are in outer.
+func (w *walker) expand(rules []Rule, outer []string) ([]Rule, error) {
+	ls, _ := w.layers()
+	aliases := false
+	for _, l := range ls {
+		aliases = aliases || l.m != nil && len(l.m.aliases) > 0
+	}
+	if !aliases {
+		return rules, nil
+	}
+
+	var out []Rule
+	for i, r := range rules {
+		tag, ok := w.alias(r.Name)
+		if !ok {
+			if out != nil {
+				out = append(out, r)
+			}
+			continue
+		}
+		if out == nil {
+			out = append([]Rule(nil), rules[:i]...)
+		}
+
+		for _, o := range outer {
+			if o == r.Name {
+				return nil, fmt.Errorf("alias cycle: %s -> %s", strings.Join(outer, " -> "), r.Name)
+			}
+		}
+		if r.Param != "" {
+			return nil, fmt.Errorf("alias %q does not take a parameter", r.Name)
+		}
+		ar, err := ParseTag(tag)
+		if err == nil {
+			ar, err = w.expand(ar, append(outer[:len(outer):len(outer)], r.Name))
+		}
+		if err != nil {
+			return nil, err
+		}
+		if r.Or || i+1 < len(rules) && rules[i+1].Or {
+			if len(ar) != 1 {
+				return nil, fmt.Errorf("alias %q stands for several rules and cannot be an alternative", r.Name)
+			}
+			ar[0].Or = r.Or
+		}
+		out = append(out, ar...)
+	}
+	if out == nil {
+		return rules, nil
+	}
+	return out, nil
+}
@@ -0,0 +1,98 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestV_Alias(t *testing.T) {
+	type X struct {
+		A string   `validate:"name"`
+		B []string `validate:"names"`
+		C string   `validate:"loop"`
+		D string   `validate:"name=x"`
+		E string   `validate:"short|name"`
+		F string   `validate:"short|proper"`
+	}
+
+	vd := V{
+		"long": long,
+		"short": func(i interface{}) error {
+			if len(i.(string)) > 3 {
+				return fmt.Errorf("too long")
+			}
+			return nil
+		},
+	}
+	for name, tag := range map[string]string{
+		"name":   "required,long",
+		"names":  "each,name",
+		"loop":   "loop2",
+		"loop2":  "loop",
+		"proper": "long",
+	} {
+		if err := vd.Alias(name, tag); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := vd.Alias("required", "long"); err == nil {
+		t.Fatal("no error for an alias named after a reserved rule")
+	}
+	if err := vd.Alias("bad", "a,,b"); err == nil {
+		t.Fatal("no error for a malformed alias")
+	}
+
+	errs := vd.Validate(X{A: "", B: []string{"abc", "x"}, E: "abcd", F: "abcd"})
+	want := []string{
+		"field A is invalid: is required",
+		"field A is invalid: too short",
+		"field B[1] is invalid: too short",
+		"field C is invalid: alias cycle: loop -> loop2 -> loop",
+		`field D is invalid: alias "name" does not take a parameter`,
+		`field E is invalid: alias "name" stands for several rules and cannot be an alternative`,
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("wrong number of errors: %v", errs)
+	}
+	for i, w := range want {
+		if errs[i].Error() != w {
+			t.Fatalf("wrong error %d: %v, wanted %s", i, errs[i], w)
+		}
+	}
+
+	if s := vd.Snapshot(X{}); !strings.Contains(s, "\nB: each, required, long\n") {
+		t.Fatalf("snapshot does not expand aliases:\n%s", s)
+	}
+}
+
+func TestV_Alias_params(t *testing.T) {
+	type X struct {
+		A []string          `validate:"each=word"`
+		B map[string]string `validate:"keys=word,values=word"`
+	}
+
+	vd := V{"long": long}
+	if err := vd.Alias("word", "required,long"); err != nil {
+		t.Fatal(err)
+	}
+	if errs := vd.Check(X{}); len(errs) != 0 {
+		t.Fatalf("errors checking aliases in parameters: %v", errs)
+	}
+
+	errs := vd.Validate(X{A: []string{"abcd", "a"}, B: map[string]string{"k": "abcd", "long": ""}})
+	want := []string{
+		"field A[1] is invalid: too short",
+		"field B[k] is invalid: too short",
+		"field B[long] is invalid: is required",
+		"field B[long] is invalid: too short",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+	for i, w := range want {
+		if errs[i].Error() != w {
+			t.Fatalf("wrong error %d: %v, wanted %s", i, errs[i], w)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"reflect"
+	"time"
+)
+
+// AuditRecord describes a field that failed validation.
+type AuditRecord struct {
+	Type  reflect.Type // the struct type declaring the field
+	Field string       // the field's name, as it appears in the BadField
+	Rule  string       // the rule that failed, or "" for a malformed tag
+	Value interface{}  // the field's value, or nil if it is sensitive
+	Time  time.Time    // when the failure was found
+}
+
+// AuditSink receives a record of every failure found in validations done
+// with the Audit option, so that rejected inputs can be kept apart from
+// application logs. Audit may be called concurrently by concurrent
+// validations.
+type AuditSink interface {
+	Audit(AuditRecord)
+}
+
+// AuditFunc is an AuditSink that calls itself.
+type AuditFunc func(AuditRecord)
+
+// Audit calls f(r).
+func (f AuditFunc) Audit(r AuditRecord) {
+	f(r)
+}
+
+// Audit sends a record of every failure to sink,
+// in the order the failures are found.
+func Audit(sink AuditSink) Option {
+	return func(w *walker) {
+		w.audit = sink
+	}
+}
@@ -0,0 +1,37 @@
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAudit(t *testing.T) {
+	type X struct {
+		A        int    `validate:"odd"`
+		Password string `validate:"sensitive,odd"`
+	}
+
+	vd := make(V)
+	vd["odd"] = func(i interface{}) error {
+		return fmt.Errorf("not odd")
+	}
+
+	var records []AuditRecord
+	before := time.Now()
+	errs := vd.ValidateOpts(X{2, "secret"}, Audit(AuditFunc(func(r AuditRecord) {
+		records = append(records, r)
+	})))
+
+	if len(records) != len(errs) || len(records) != 2 {
+		t.Fatalf("wrong number of audit records: %v", records)
+	}
+	r := records[0]
+	if r.Type != reflect.TypeOf(X{}) || r.Field != "A" || r.Rule != "odd" || r.Value != 2 || r.Time.Before(before) {
+		t.Fatalf("wrong audit record: %+v", r)
+	}
+	if records[1].Value != nil {
+		t.Fatalf("sensitive value was audited: %+v", records[1])
+	}
+}
@@ -0,0 +1,43 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import "reflect"
+
+// ValidateAll validates each element of items, a slice or array of structs
+// or pointers to them, as ValidateOpts would, and returns the errors of the
+// invalid elements by their indexes, so that bulk imports can report which
+// record each error belongs to:
+//
+//	for i, errs := range v.ValidateAll(records) {
+//		log.Printf("record %d: %v", i+1, errs)
+//	}
+//
+// The paths in the errors are relative to the elements. ValidateAll returns
+// nil if every element is valid, or if items is not a slice or array.
+func (v V) ValidateAll(items interface{}, opts ...Option) map[int][]error {
+	val := reflect.ValueOf(items)
+	if val.Kind() == reflect.Ptr && val.Type().Elem().Kind() == reflect.Array {
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return nil
+	}
+
+	var bad map[int][]error
+	for i := 0; i < val.Len(); i++ {
+		w := walker{v: v}
+		for _, o := range opts {
+			o(&w)
+		}
+		errs := w.validate(val.Index(i), nil)
+		if len(errs) == 0 {
+			continue
+		}
+		if bad == nil {
+			bad = make(map[int][]error)
+		}
+		bad[i] = errs
+	}
+	return bad
+}
@@ -0,0 +1,45 @@
+package validate
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestV_ValidateAll(t *testing.T) {
+	type X struct {
+		A int `validate:"nonzero"`
+	}
+
+	vd := Builtin()
+	bad := vd.ValidateAll([]interface{}{X{1}, &X{}, nil, X{2}, X{}}, Root("record"))
+	if len(bad) != 2 || len(bad[1]) != 1 || len(bad[4]) != 1 {
+		t.Fatalf("wrong errors: %v", bad)
+	}
+	if bad[4][0].Error() != "field record.A is invalid: should be nonzero" {
+		t.Fatal("wrong error:", bad[4][0])
+	}
+
+	if bad := vd.ValidateAll(&[2]X{{1}, {3}}); bad != nil {
+		t.Fatalf("valid array has errors: %v", bad)
+	}
+	if bad := vd.ValidateAll(X{}); bad != nil {
+		t.Fatalf("struct has errors: %v", bad)
+	}
+}
+
+func ExampleV_ValidateAll() {
+	type Record struct {
+		Name string `validate:"nonzero"`
+	}
+
+	records := []Record{{"a"}, {""}, {"c"}, {""}}
+	bad := Builtin().ValidateAll(records)
+	for i := range records {
+		if errs := bad[i]; errs != nil {
+			fmt.Printf("record %d: %v\n", i, errs)
+		}
+	}
+
+	// Output: record 1: [field Name is invalid: should be nonzero]
+	// record 3: [field Name is invalid: should be nonzero]
+}
@@ -0,0 +1,250 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"unicode"
+)
+
+// Builtin returns a new V holding the validators provided by this package,
+// under these names:
+//
+//	nonzero       the value is not the zero value of its type
+//	nonempty      the string, slice, array, map, or channel is not empty
+//	email         the string is a bare email address, as in "a@example.com"
+//	url           the string is an absolute URL with a host
+//	alpha         the string is not empty and holds only letters
+//	numeric       the string is a decimal number, as in "-1.5"
+//	unique        as described for SetComparers, with the default Comparers
+//	eq=x          as described for SetComparers
+//	oneof=a b …   as described for SetComparers
+//	cron          as described for Cron
+//	interval      as described for Interval
+//	rate          as described for Rate
+//	backoff       as described for Backoff
+//	dnslabel      as described for DNSLabel
+//	dnssubdomain  as described for DNSSubdomain
+//	resourcename  as described for ResourceName
+//	labelkey      as described for LabelKey
+//	labelvalue    as described for LabelValue
+//	quantity      as described for Quantity
+//	luhn          as described for Luhn
+//	creditcard    as described for CreditCard
+//	iban          as described for IBAN
+//	currency      as described for Currency
+//	country       as described for Country
+//	languagetag   as described for LanguageTag
+//	timezone      as described for TimeZone
+//	password      as described for RegisterPasswordPolicy, with a policy
+//	              requiring at least 8 characters
+//	gt=n          the number is greater than n
+//	gte=n         the number is at least n
+//	lt=n          the number is less than n
+//	lte=n         the number is at most n
+//	between=m n   the number is at least m and at most n
+//	len=n         the string, slice, array, map, or channel has length n
+//	minlen=n      the length is at least n
+//	maxlen=n      the length is at most n
+//	regexp=re     the string matches the regular expression re
+//	dateformat=l  the string is a time in the layout l, as for time.Parse
+//	before=t      the time is before t
+//	after=t       the time is after t
+//	uuid=v …      the string is a UUID in its canonical hyphenated form,
+//	              of one of the versions v, if any are given
+//	ulid          the string is a ULID, as in "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+//	objectid      the string is a MongoDB ObjectID in hex
+//	base64=e      the string is base64 in the encoding e: the standard
+//	              encoding if it is empty, or "url", "raw", or "rawurl"
+//	hex           the string is an even number of hexadecimal digits
+//	json          the string is valid JSON
+//	jwt           the string is a JSON Web Token in the compact form, whose
+//	              signature is not verified
+//
+// Numbers of any kind may be compared, and integers are compared exactly
+// with integers; time.Durations may also be compared with durations, as
+// in "gte=1s". Strings are measured in runes, the characters users see,
+// unless the length is followed by "bytes", as in "maxlen=255 bytes".
+// Regular expressions are compiled once, by Regexps, and match anywhere in
+// the string unless they are anchored. Those containing commas or "|" are
+// written after a colon, which takes the rest of the tag, as in
+// "nonzero,regexp:^[a-z]{2,8}(-[0-9]+)?$".
+//
+// The times compared by "before" and "after" may be time.Times or strings
+// holding RFC 3339 times or dates, as in "2006-01-02", which are at
+// midnight UTC. Their parameters may be such times too, or "now",
+// optionally followed by a signed duration, as in "after=now-24h".
+// Durations are compared by "gte", "lte", and the like.
+//
+// Validators of strings accept values of any type whose kind is string,
+// and report other values with an error wrapping ErrWrongType.
+// The V may be changed freely, or used with the Defaults option
+// to supplement another.
+func Builtin() V {
+	v := V{
+		"nonzero":      nonzero,
+		"nonempty":     nonempty,
+		"email":        email,
+		"url":          absURL,
+		"alpha":        alpha,
+		"numeric":      numeric,
+		"cron":         Cron,
+		"interval":     Interval,
+		"rate":         Rate,
+		"backoff":      Backoff,
+		"dnslabel":     DNSLabel,
+		"dnssubdomain": DNSSubdomain,
+		"resourcename": ResourceName,
+		"labelkey":     LabelKey,
+		"labelvalue":   LabelValue,
+		"quantity":     Quantity,
+		"luhn":         Luhn,
+		"creditcard":   CreditCard,
+		"iban":         IBAN,
+		"currency":     Currency,
+		"country":      Country,
+		"languagetag":  LanguageTag,
+		"timezone":     TimeZone,
+		"hex":          hexEncoded,
+		"json":         validJSON,
+		"jwt":          jwt,
+		"ulid":         ulid,
+		"objectid":     objectID,
+	}
+	v.SetComparers(nil)
+	v.RegisterPasswordPolicy(PasswordPolicy{MinLength: 8})
+	v.RegisterParam("gt", greater)
+	v.RegisterParam("gte", atLeast)
+	v.RegisterParam("lt", less)
+	v.RegisterParam("lte", atMost)
+	v.RegisterParam("between", between)
+	v.RegisterParam("len", exactLen)
+	v.RegisterParam("minlen", minLen)
+	v.RegisterParam("maxlen", maxLen)
+	v.RegisterParam("regexp", matchRegexp)
+	v.RegisterParam("dateformat", dateFormat)
+	v.RegisterParam("before", before)
+	v.RegisterParam("after", after)
+	v.RegisterParam("uuid", uuid)
+	v.RegisterParam("base64", base64Encoded)
+	for _, name := range []string{"gt", "gte", "lt", "lte"} {
+		v.RegisterParamCheck(name, numberParam)
+	}
+	v.RegisterParamCheck("between", betweenParam)
+	for _, name := range []string{"len", "minlen", "maxlen"} {
+		v.RegisterParamCheck(name, lengthParam)
+	}
+	v.RegisterParamCheck("regexp", regexpParam)
+	v.RegisterParamCheck("dateformat", dateFormatParam)
+	v.RegisterParamCheck("before", timeParam)
+	v.RegisterParamCheck("after", timeParam)
+	v.RegisterParamCheck("uuid", uuidParam)
+	v.RegisterParamCheck("base64", base64Param)
+	return v
+}
+
+// asString returns the value of i, if its kind is string.
+func asString(i interface{}) (string, bool) {
+	if s, ok := i.(string); ok {
+		return s, true
+	}
+	val := reflect.ValueOf(i)
+	if val.Kind() != reflect.String {
+		return "", false
+	}
+	return val.String(), true
+}
+
+// zeroOf returns the zero value of t, or def if t is nil, for the
+// parameter checks of builtins that reuse the builtins' own parsing.
+func zeroOf(t reflect.Type, def interface{}) interface{} {
+	if t == nil {
+		return def
+	}
+	return reflect.Zero(t).Interface()
+}
+
+// stringType returns an error wrapping ErrWrongType unless t is nil or
+// its kind is string, for the parameter checks of builtins validating
+// strings.
+func stringType(t reflect.Type) error {
+	if t != nil && t.Kind() != reflect.String {
+		return errorf(ErrWrongType, "%v is not a string", t)
+	}
+	return nil
+}
+
+func nonzero(i interface{}) error {
+	if i == nil || reflect.ValueOf(i).IsZero() {
+		return errorf(ErrRequired, "should be nonzero")
+	}
+	return nil
+}
+
+func nonempty(i interface{}) error {
+	val := reflect.ValueOf(i)
+	switch val.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		if val.Len() == 0 {
+			return errorf(ErrRequired, "should not be empty")
+		}
+		return nil
+	}
+	return errorf(ErrWrongType, "cannot check emptiness of %T", i)
+}
+
+func email(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not an email address", i)
+	}
+	a, err := mail.ParseAddress(s)
+	if err != nil || a.Name != "" || a.Address != s {
+		return errorf(ErrBadFormat, "%q is not a valid email address", s)
+	}
+	return nil
+}
+
+func absURL(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a URL", i)
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return errorf(ErrBadFormat, "%q is not a valid absolute URL", s)
+	}
+	return nil
+}
+
+var numericRE = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)$`)
+
+func alpha(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a string", i)
+	}
+	if s == "" {
+		return errorf(ErrBadFormat, "empty string is not alphabetic")
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			return errorf(ErrBadFormat, "%q is not alphabetic", s)
+		}
+	}
+	return nil
+}
+
+func numeric(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a string", i)
+	}
+	if !numericRE.MatchString(s) {
+		return errorf(ErrBadFormat, "%q is not numeric", s)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package validate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testName string
+
+func TestBuiltin(t *testing.T) {
+	vd := Builtin()
+	tests := map[string]map[interface{}]error{
+		"nonzero": {
+			1:          nil,
+			"a":        nil,
+			0:          ErrRequired,
+			"":         ErrRequired,
+			nil:        ErrRequired,
+			struct{}{}: ErrRequired,
+		},
+		"nonempty": {
+			"a":          nil,
+			"":           ErrRequired,
+			[0]int{}:     ErrRequired,
+			testName(""): ErrRequired,
+			5:            ErrWrongType,
+		},
+		"email": {
+			"a@example.com":       nil,
+			"A <a@example.com>":   ErrBadFormat,
+			"example.com":         ErrBadFormat,
+			"a@example.com extra": ErrBadFormat,
+			1:                     ErrWrongType,
+		},
+		"url": {
+			"https://example.com/a?b": nil,
+			"/relative":               ErrBadFormat,
+			"mailto:a@example.com":    ErrBadFormat,
+			"http://%zz":              ErrBadFormat,
+		},
+		"alpha": {
+			"abc":         nil,
+			"Grüße":       nil,
+			testName("x"): nil,
+			"a1":          ErrBadFormat,
+			"":            ErrBadFormat,
+			'a':           ErrWrongType,
+		},
+		"numeric": {
+			"12":    nil,
+			"-1.5":  nil,
+			".5":    nil,
+			"1e3":   ErrBadFormat,
+			"":      ErrBadFormat,
+			"1.2.3": ErrBadFormat,
+		},
+		"unique": {
+			[2]int{1, 2}: nil,
+			[2]int{1, 1}: ErrNotUnique,
+		},
+		"cron":         {"@hourly": nil, "60 * * * *": ErrOutOfRange},
+		"interval":     {"P1D/P2D": ErrBadFormat},
+		"rate":         {"1/s": nil},
+		"backoff":      {"initial=1s": nil},
+		"dnslabel":     {testName("a"): nil},
+		"dnssubdomain": {"a.b": nil},
+		"resourcename": {"a.b": nil},
+		"labelkey":     {"a/b": nil},
+		"labelvalue":   {"": nil},
+		"quantity":     {"1Gi": nil},
+		"luhn":         {"79927398713": nil, "79927398710": ErrBadFormat},
+		"creditcard":   {"4111 1111 1111 1111": nil},
+		"iban":         {"GB82WEST12345698765432": nil},
+		"currency":     {"EUR": nil, "eur": ErrNotAllowed},
+		"country":      {"NZ": nil, "ZZ": ErrNotAllowed},
+		"languagetag":  {"en-US": nil, "en_US": ErrBadFormat},
+		"timezone":     {"UTC": nil, "Local": ErrNotAllowed},
+		"hex":          {"00ff": nil, "0ff": ErrBadFormat},
+		"json":         {"{}": nil, "{": ErrBadFormat},
+		"jwt":          {"e30.e30.": nil, "e30.e30": ErrBadFormat},
+		"password":     {"12345678": nil, "1234567": ErrTooShort},
+		"ulid": {
+			"01ARZ3NDEKTSV4RRFFQ69G5FAV": nil,
+			"01arz3ndektsv4rrffq69g5fav": nil,
+			"81ARZ3NDEKTSV4RRFFQ69G5FAV": ErrBadFormat,
+			"01ARZ3NDEKTSV4RRFFQ69G5FAU": ErrBadFormat,
+			"01ARZ3NDEKTSV4RRFFQ69G5FA":  ErrBadFormat,
+			1:                            ErrWrongType,
+		},
+		"objectid": {
+			"507f1f77bcf86cd799439011":  nil,
+			"507F1F77BCF86CD799439011":  nil,
+			"507f1f77bcf86cd79943901":   ErrBadFormat,
+			"507f1f77bcf86cd79943901z":  ErrBadFormat,
+			"507f1f77bcf86cd7994390111": ErrBadFormat,
+		},
+	}
+
+	// The validators taking parameters are tested with the Field they are
+	// passed, by their rules.
+	params := map[string]map[interface{}]error{
+		"gt=0":                  {1: nil, 0: ErrOutOfRange},
+		"gte=0":                 {0: nil, -1: ErrOutOfRange},
+		"lt=0":                  {-1: nil, 0: ErrOutOfRange},
+		"lte=0":                 {0: nil, 1: ErrOutOfRange},
+		"between=1 3":           {2: nil, 4: ErrOutOfRange},
+		"len=2":                 {"ab": nil, "a": ErrTooShort},
+		"minlen=2":              {"ab": nil, "a": ErrTooShort},
+		"maxlen=2":              {"ab": nil, "abc": ErrTooLong},
+		"regexp=^a+$":           {"aa": nil, "ab": ErrBadFormat, 1: ErrWrongType},
+		"oneof=a 1":             {"a": nil, 1: nil, "b": ErrNotAllowed, struct{}{}: ErrWrongType},
+		"eq=1":                  {1: nil, 1.0: nil, uint8(1): nil, 2: ErrNotAllowed, "1": nil, true: nil, struct{}{}: ErrWrongType},
+		"dateformat=2006-01-02": {"2024-02-29": nil, "2023-02-29": ErrBadFormat},
+		"base64":                {"aGk=": nil, "aGk": ErrBadFormat},
+		"uuid": {
+			"123e4567-e89b-12d3-a456-426614174000": nil,
+			"123e4567e89b12d3a456426614174000":     ErrBadFormat,
+			"123e4567-e89b-12d3-a456-42661417400g": ErrBadFormat,
+			1:                                      ErrWrongType,
+		},
+		"uuid=4 7": {
+			"f47ac10b-58cc-4372-a567-0e02b2c3d479": nil,
+			"017F22E2-79B0-7CC3-98C4-DC0C0C07398F": nil,
+			"123e4567-e89b-12d3-a456-426614174000": ErrNotAllowed,
+		},
+		"before=now": {"2000-01-01": nil, "3000-01-01": ErrOutOfRange},
+		"after=now":  {"3000-01-01": nil, "2000-01-01": ErrOutOfRange},
+	}
+
+	for name, cases := range tests {
+		if vd[name] == nil {
+			t.Errorf("no builtin %s", name)
+			continue
+		}
+		testFormat(t, name, vd[name], cases)
+	}
+	tested := make(map[string]bool)
+	for tag, cases := range params {
+		rules, _ := ParseTag(tag)
+		r := rules[0]
+		tested[r.Name] = true
+		fn := vd[r.Name+"="]
+		if fn == nil {
+			t.Errorf("no builtin %s", r.Name)
+			continue
+		}
+		testFormat(t, tag, func(i interface{}) error {
+			return fn(Field{Value: reflect.ValueOf(i), Rule: r})
+		}, cases)
+	}
+	for name := range vd {
+		if name == metaKey {
+			continue
+		}
+		if n, ok := strings.CutSuffix(name, "="); ok && !tested[n] || !ok && tests[name] == nil {
+			t.Errorf("builtin %s is not tested", name)
+		}
+	}
+}
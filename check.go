@@ -0,0 +1,224 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Check inspects the tags of the struct types of types, which are values
+// of them or pointers to them, and of the structs they reach through
+// "struct" rules, without validating any values, so that mistakes can be
+// caught when a program starts rather than when a value is first
+// validated. It reports:
+//
+//   - tags that cannot be parsed, and aliases that cannot be expanded;
+//   - rules naming no validator in v, with errors wrapping
+//     ErrUndefinedValidator;
+//   - rules naming validators added by Register or RegisterTyped for
+//     fields whose values they do not accept, with errors wrapping
+//     ErrWrongType;
+//   - parameters given to validators that take none, and reserved rules
+//     missing theirs, such as a "method" naming no method of the field;
+//   - parameters rejected by the validators' parameter checks, added with
+//     RegisterParamCheck, such as "gt=abc", or "minlen=3" for an int;
+//   - "struct" rules on fields that are not structs, "each", "keys",
+//     or "values" rules on fields that are not collections, and "present"
+//     rules on fields that are never absent;
+//   - unexported fields with validate tags, which are never validated;
+//   - validators in v named after reserved rules, which are never called.
+//
+// Each error names the type and field it concerns.
+func (v V) Check(types ...interface{}) []error {
+	w := walker{v: v}
+	seen := make(map[reflect.Type]bool)
+	var errs []error
+	errs = w.checkReserved(errs)
+	for _, x := range types {
+		t := reflect.TypeOf(x)
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			errs = append(errs, fmt.Errorf("cannot check %T, which is not a struct", x))
+			continue
+		}
+		errs = w.check(t, seen, errs)
+	}
+	return errs
+}
+
+// checkReserved appends an error to errs for each validator in the
+// walker's validators, or the Vs they overlay, that a reserved rule of
+// the same name hides.
+func (w *walker) checkReserved(errs []error) []error {
+	ls, own := w.layers()
+	var names []string
+	for _, l := range ls[:own] {
+		for key := range l.v {
+			if name := ruleName(key); Reserved(name) {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		if i == 0 || name != names[i-1] {
+			errs = append(errs, fmt.Errorf("validator %q is hidden by the reserved rule of that name", name))
+		}
+	}
+	return errs
+}
+
+// check appends the mistakes in the tags of the struct type t to errs.
+func (w *walker) check(t reflect.Type, seen map[reflect.Type]bool, errs []error) []error {
+	if seen[t] {
+		return errs
+	}
+	seen[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup("validate")
+		if !ok || tag == "-" {
+			continue
+		}
+		fail := func(err error) {
+			errs = append(errs, fmt.Errorf("%v.%s: %w", t, f.Name, err))
+		}
+		if !f.IsExported() {
+			fail(errors.New("unexported field is never validated"))
+			continue
+		}
+		rules, err := ParseTag(tag)
+		if err == nil {
+			rules, err = w.expand(rules, nil)
+		}
+		if err != nil {
+			fail(err)
+			continue
+		}
+		var more []reflect.Type
+		w.checkRules(t, f.Type, rules, fail, &more)
+		for _, st := range more {
+			errs = w.check(st, seen, errs)
+		}
+	}
+	return errs
+}
+
+// checkRules reports the mistakes in rules, which apply to values of type
+// ft within the struct type parent, to fail, and adds the struct types
+// they reach to more.
+func (w *walker) checkRules(parent, ft reflect.Type, rules []Rule, fail func(error), more *[]reflect.Type) {
+	// The types of the values held by Optionals are unknown.
+	opaque := ft.Implements(optionalType) || reflect.PointerTo(ft).Implements(optionalType)
+	ct := ft
+	for ct.Kind() == reflect.Ptr {
+		ct = ct.Elem()
+	}
+
+	for k, r := range rules {
+		if (r.Or || k+1 < len(rules) && rules[k+1].Or) && Reserved(r.Name) {
+			fail(fmt.Errorf("reserved rule %q cannot have alternatives", r.Name))
+			continue
+		}
+		switch r.Name {
+		case "sensitive", "omitempty", "on", "msg", "required":
+		case "present":
+			switch ft.Kind() {
+			case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+			default:
+				if !opaque {
+					fail(fmt.Errorf("rule \"present\" applied to %v, which is never absent", ft))
+				}
+			}
+		case "struct":
+			switch {
+			case opaque || ct.Kind() == reflect.Interface:
+			case ct.Kind() != reflect.Struct:
+				fail(fmt.Errorf("rule \"struct\" applied to %v, which is not a struct", ft))
+			default:
+				*more = append(*more, ct)
+			}
+		case "each", "keys", "values":
+			erules, err := rules[k+1:], error(nil)
+			if r.Param != "" {
+				erules, err = ParseTag(r.Param)
+			}
+			if err == nil {
+				erules, err = w.expand(erules, nil)
+			}
+			var et reflect.Type
+			kind := ct.Kind()
+			switch {
+			case err != nil:
+				fail(err)
+			case opaque || kind == reflect.Interface:
+			case r.Name == "each" && (kind == reflect.Slice || kind == reflect.Array):
+				et = ct.Elem()
+			case r.Name == "keys" && kind == reflect.Map:
+				et = ct.Key()
+			case r.Name == "values" && kind == reflect.Map:
+				et = ct.Elem()
+			default:
+				fail(fmt.Errorf("rule %q applied to %v", r.Name, ft))
+			}
+			if et != nil {
+				w.checkRules(parent, et, erules, fail, more)
+			}
+			if r.Param == "" {
+				return
+			}
+		case "method":
+			if opaque || ct.Kind() == reflect.Interface {
+				break
+			}
+			m, ok := reflect.PointerTo(ct).MethodByName(r.Param)
+			if !ok || m.Type.NumIn() != 1 || m.Type.NumOut() != 1 || m.Type.Out(0) != errorType {
+				fail(fmt.Errorf("%v has no method %s() error", ct, r.Param))
+			}
+		case "required_if", "required_with", "required_without":
+			ps := r.Params()
+			if len(ps) == 0 || r.Name == "required_if" && len(ps) < 2 {
+				fail(fmt.Errorf("%s needs the name of a field", r.Name))
+				break
+			}
+			if r.Name == "required_if" {
+				ps = ps[:1]
+			}
+			for _, name := range ps {
+				if sf, ok := parent.FieldByName(name); !ok || !sf.IsExported() {
+					fail(fmt.Errorf("%v has no exported field %s", parent, name))
+				}
+			}
+		default:
+			vf, extended := w.lookup(r.Name)
+			switch {
+			case vf == nil:
+				fail(fmt.Errorf("%w: %q", ErrUndefinedValidator, r.Name))
+			case !extended && r.Param != "":
+				fail(fmt.Errorf("validator %q does not take a parameter", r.Name))
+			case !extended && !opaque && ct.Kind() != reflect.Interface && !w.accepts(r.Name, ct):
+				fail(errorf(ErrWrongType, "validator %q does not accept %v", r.Name, ct))
+			case extended:
+				check := w.paramCheck(r.Name)
+				if check == nil {
+					break
+				}
+				t := ct
+				if opaque || ct.Kind() == reflect.Interface {
+					t = nil
+				}
+				if err := check(t, r.Param); err != nil {
+					fail(fmt.Errorf("rule %q: %w", r.Name, err))
+				}
+			}
+		}
+	}
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
@@ -0,0 +1,175 @@
+package validate
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type checkRange struct {
+	Min, Max int
+}
+
+func (r checkRange) Check() error {
+	return nil
+}
+
+func TestV_Check(t *testing.T) {
+	type Inner struct {
+		A string `validate:"nonempty,typo"`
+	}
+	type X struct {
+		Good    string            `validate:"nonempty,email|url"`
+		Inner   *Inner            `validate:"struct"`
+		Range   checkRange        `validate:"method=Check"`
+		NoRange checkRange        `validate:"method=Missing"`
+		Param   string            `validate:"alpha=3"`
+		Bad     string            `validate:"a,,b"`
+		NotS    int               `validate:"struct"`
+		NotE    string            `validate:"each,alpha"`
+		Elems   []int             `validate:"each,nonzero,missing"`
+		Keys    map[string]int    `validate:"keys=alpha=1"`
+		When    string            `validate:"required_if=Good"`
+		With    string            `validate:"required_with=Nope"`
+		Opt     testOptional[int] `validate:"struct"`
+		Alts    string            `validate:"omitempty|alpha"`
+		Cycle   string            `validate:"loop"`
+		Any     interface{}       `validate:"struct,each"`
+		Fine    map[string]string `validate:"values,alpha"`
+		hidden  string            `validate:"alpha"`
+		Skipped string            `validate:"-"`
+	}
+
+	vd := Builtin()
+	vd.Alias("loop", "loop")
+	var got []string
+	for _, err := range vd.Check(&X{}, 1) {
+		got = append(got, err.Error())
+	}
+	want := []string{
+		`validate.Inner.A: undefined validator: "typo"`,
+		`validate.X.NoRange: validate.checkRange has no method Missing() error`,
+		`validate.X.Param: validator "alpha" does not take a parameter`,
+		`validate.X.Bad: empty rule at position 2 in tag "a,,b"`,
+		`validate.X.NotS: rule "struct" applied to int, which is not a struct`,
+		`validate.X.NotE: rule "each" applied to string`,
+		`validate.X.Elems: undefined validator: "missing"`,
+		`validate.X.Keys: validator "alpha" does not take a parameter`,
+		`validate.X.When: required_if needs the name of a field`,
+		`validate.X.With: validate.X has no exported field Nope`,
+		`validate.X.Alts: reserved rule "omitempty" cannot have alternatives`,
+		`validate.X.Cycle: alias cycle: loop -> loop`,
+		`validate.X.hidden: unexported field is never validated`,
+		`cannot check int, which is not a struct`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("wrong errors:\n%s\nwanted:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if errs := vd.Check(X{}); !errors.Is(errs[0], ErrUndefinedValidator) {
+		t.Fatalf("undefined validator is not ErrUndefinedValidator: %v", errs[0])
+	}
+	if errs := vd.Check(Inner{}, testNode{}); len(errs) != 2 {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+}
+
+func TestV_Check_reserved(t *testing.T) {
+	type X struct {
+		A string `validate:"required"`
+	}
+
+	vd := V{"required": nonzero, "required_if=": nonzero, "long": long}
+	errs := vd.WithOverlay(V{"each": nonzero}).Check(X{})
+	var got []string
+	for _, err := range errs {
+		got = append(got, err.Error())
+	}
+	want := []string{
+		`validator "each" is hidden by the reserved rule of that name`,
+		`validator "required" is hidden by the reserved rule of that name`,
+		`validator "required_if" is hidden by the reserved rule of that name`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("wrong errors:\n%s\nwanted:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	for name, add := range map[string]func(){
+		"RegisterField":     func() { vd.RegisterField("each", func(Field) error { return nil }) },
+		"Register":          func() { Register(vd, "required", func(string) error { return nil }) },
+		"RegisterFor":       func() { RegisterFor(vd, "omitempty", func(string) error { return nil }) },
+		"RegisterTyped":     func() { vd.RegisterTyped("msg", reflect.String, nonzero) },
+		"Registry.Register": func() { NewRegistry(nil).Register("struct", nonzero) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s added a validator named after a reserved rule", name)
+				}
+			}()
+			add()
+		}()
+	}
+}
+
+func TestV_Check_params(t *testing.T) {
+	type X struct {
+		Good     string        `validate:"minlen=2,maxlen=10 bytes,regexp=^a,oneof=ab ac,base64=url,uuid=4 7"`
+		Numbers  []int         `validate:"each,gt=0,between=1 10,oneof=1 2"`
+		When     time.Time     `validate:"after=now-24h,before=2030-01-01"`
+		Wait     time.Duration `validate:"lte=1m30s"`
+		Any      interface{}   `validate:"minlen=3"`
+		Gt       int           `validate:"gt=abc"`
+		Regexp   string        `validate:"regexp=["`
+		MinLen   string        `validate:"minlen=x"`
+		IntLen   int           `validate:"minlen=3"`
+		Between  float64       `validate:"between=1"`
+		OneOf    int           `validate:"oneof=red green"`
+		OneOfMap map[int]int   `validate:"oneof=1"`
+		Format   string        `validate:"dateformat=day"`
+		Before   *time.Time    `validate:"before=yesterday"`
+		UUID     string        `validate:"uuid=v4"`
+		Base64   []byte        `validate:"base64"`
+	}
+
+	var got []string
+	for _, err := range Builtin().Check(X{}) {
+		got = append(got, err.Error())
+	}
+	want := []string{
+		`validate.X.Gt: rule "gt": parameter "abc" is not a number`,
+		`validate.X.Regexp: rule "regexp": parameter "[" is not a regular expression: error parsing regexp: missing closing ]: ` + "`[`",
+		`validate.X.MinLen: rule "minlen": parameter "x" is not a length, as in "10" or "10 bytes"`,
+		`validate.X.IntLen: rule "minlen": cannot check the length of int`,
+		`validate.X.Between: rule "between": parameter "1" is not two numbers, as in "between=1 10"`,
+		`validate.X.OneOf: rule "oneof": parameter "red" is not of type int`,
+		`validate.X.OneOfMap: rule "oneof": map[int]int is not a string, boolean, or number`,
+		`validate.X.Format: rule "dateformat": parameter "day" is not a time layout, as in "2006-01-02"`,
+		`validate.X.Before: rule "before": parameter "yesterday" is not a time, as in "2006-01-02"`,
+		`validate.X.UUID: rule "uuid": parameter "v4" is not a list of UUID versions, as in "4 7"`,
+		`validate.X.Base64: rule "base64": []uint8 is not a string`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("wrong errors:\n%s\nwanted:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	for _, err := range Builtin().Check(X{}) {
+		if strings.Contains(err.Error(), "IntLen") && !errors.Is(err, ErrWrongType) {
+			t.Errorf("field of the wrong type is not ErrWrongType: %v", err)
+		}
+	}
+
+	type Y struct {
+		N int `validate:"minlen=3"`
+	}
+	vd := Builtin()
+	RegisterFor(vd, "minlen", func(n int) error { return nil })
+	if errs := vd.Check(Y{}); errs != nil {
+		t.Errorf("RegisterFor left the parameter check rejecting its type: %v", errs)
+	}
+	vd.RegisterParam("minlen", func(interface{}, string) error { return nil })
+	if errs := vd.Check(X{}); len(errs) != len(want)-2 {
+		t.Errorf("replacing a validator kept its parameter check: %v", errs)
+	}
+}
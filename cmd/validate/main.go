@@ -0,0 +1,334 @@
+// © 2013 Steve McCoy under the MIT license.
+
+/*
+Command validate checks data files against a manifest of validation rules.
+
+Usage:
+
+	validate -rules manifest.json [-format json|yaml|csv] [-json | -sarif] file...
+
+The manifest is a JSON object mapping field paths to validate tags,
+in the same syntax used in struct tags:
+
+	{
+		"name": "nonzero,string",
+		"server.port": "nonzero,number"
+	}
+
+A manifest mirroring the tags of a struct type can be made with
+validate.V.RuleSet and RuleSet.Manifest.
+
+The rules are those of validate.Builtin, and "string", "number",
+and "bool", which check the types of values; "number" and "bool" accept
+numbers and booleans given as strings. The reserved rules, such as
+"required" and "omitempty", mean what they do in struct tags, and rules
+such as "minlen=3" take parameters as they do there. A field absent from
+a record, or null, is checked only by the reserved rules, so that one
+failing "required" is not reported by its other rules too.
+
+Nested fields are addressed by joining their keys with dots.
+A JSON document may be a single object or an array of objects,
+in which case each element is a separate record. So may each document
+of a YAML file, of which the subset used by configuration files is read:
+anchors, aliases, and tags are not supported.
+Each row of a CSV document is a record,
+keyed by the names in the document's header row. As CSV values are all
+text, those written as JSON numbers are read as numbers, and "true" and
+"false" as booleans, so that rules such as "gte=0" apply to them,
+unless their fields have the "string" rule.
+
+Errors are printed one per line, as a JSON array when -json is given,
+or as a SARIF 2.1.0 log when -sarif is given, for code scanning tools.
+The exit status is 0 when every record is valid, 1 when any record is invalid,
+and 2 when the manifest or a document cannot be read.
+*/
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"mccoy.space/g/validate"
+)
+
+// validators are the rules available to manifests: the package's builtins,
+// and these for checking the types of values in documents.
+var validators = func() validate.V {
+	v := validate.Builtin()
+	for name, fn := range types {
+		v[name] = fn
+	}
+	return v
+}()
+
+var types = validate.V{
+	"string": func(i interface{}) error {
+		if _, ok := i.(string); !ok && i != nil {
+			return fmt.Errorf("%v is not a string", i)
+		}
+		return nil
+	},
+	"number": func(i interface{}) error {
+		switch x := i.(type) {
+		case nil, float64:
+			return nil
+		case string:
+			if _, err := strconv.ParseFloat(x, 64); err == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%v is not a number", i)
+	},
+	"bool": func(i interface{}) error {
+		switch x := i.(type) {
+		case nil, bool:
+			return nil
+		case string:
+			if _, err := strconv.ParseBool(x); err == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%v is not a boolean", i)
+	},
+}
+
+// A finding is one invalid field in one record of a document.
+type finding struct {
+	File   string `json:"file"`
+	Record int    `json:"record"`
+	Field  string `json:"field"`
+	Rule   string `json:"rule"`
+	Error  string `json:"error"`
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	rulesPath := fs.String("rules", "", "path to the rule manifest")
+	format := fs.String("format", "", "document format, json, yaml, or csv (default: by file extension)")
+	asJSON := fs.Bool("json", false, "print errors as a JSON array")
+	asSARIF := fs.Bool("sarif", false, "print errors as a SARIF log")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *rulesPath == "" || fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "usage: validate -rules manifest.json [-format json|yaml|csv] [-json | -sarif] file...")
+		return 2
+	}
+
+	rules, err := loadManifest(*rulesPath)
+	if err != nil {
+		fmt.Fprintln(stderr, "validate:", err)
+		return 2
+	}
+
+	var found []finding
+	for _, path := range fs.Args() {
+		records, err := loadDocument(path, *format)
+		if err != nil {
+			fmt.Fprintln(stderr, "validate:", err)
+			return 2
+		}
+		for i, rec := range records {
+			for _, err := range check(rec, rules) {
+				bf := err.(validate.BadField)
+				found = append(found, finding{path, i + 1, bf.Field, bf.Rule, bf.Err.Error()})
+			}
+		}
+	}
+
+	switch {
+	case *asSARIF:
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "\t")
+		enc.Encode(sarifLog(found))
+	case *asJSON:
+		if found == nil {
+			found = []finding{}
+		}
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "\t")
+		enc.Encode(found)
+	default:
+		for _, f := range found {
+			fmt.Fprintf(stdout, "%s:%d: field %s is invalid: %s\n", f.File, f.Record, f.Field, f.Error)
+		}
+	}
+
+	if len(found) > 0 {
+		return 1
+	}
+	return 0
+}
+
+func loadManifest(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules map[string]string
+	if err := json.Unmarshal(b, &rules); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return rules, nil
+}
+
+func loadDocument(path, format string) ([]map[string]interface{}, error) {
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []map[string]interface{}
+	switch format {
+	case "json":
+		records, err = readJSON(f)
+	case "yaml", "yml":
+		records, err = readYAML(f)
+	case "csv":
+		records, err = readCSV(f)
+	default:
+		return nil, fmt.Errorf("%s: unknown document format %q", path, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return records, nil
+}
+
+func readJSON(r io.Reader) ([]map[string]interface{}, error) {
+	var doc interface{}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	switch d := doc.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{d}, nil
+	case []interface{}:
+		records := make([]map[string]interface{}, len(d))
+		for i, e := range d {
+			m, ok := e.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("record %d is not an object", i+1)
+			}
+			records[i] = m
+		}
+		return records, nil
+	}
+	return nil, errors.New("document is not an object or an array of objects")
+}
+
+func readCSV(r io.Reader) ([]map[string]interface{}, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	header := rows[0]
+	records := make([]map[string]interface{}, len(rows)-1)
+	for i, row := range rows[1:] {
+		m := make(map[string]interface{}, len(header))
+		for j, name := range header {
+			m[name] = cell(row[j])
+		}
+		records[i] = m
+	}
+	return records, nil
+}
+
+// A cell is a value read from a CSV document, which is text however
+// it is meant to be read.
+type cell string
+
+// scalar returns the value of c for a field with the given rules:
+// a number or boolean if c is written as one and the rules do not name
+// "string", and otherwise a string.
+func (c cell) scalar(rules string) interface{} {
+	rs, _ := validate.ParseTag(rules)
+	for _, r := range rs {
+		if r.Name == "string" {
+			return string(c)
+		}
+	}
+	switch s := string(c); {
+	case s == "true" || s == "false":
+		return s == "true"
+	case json.Valid([]byte(s)):
+		if n, err := strconv.ParseFloat(s, 64); err == nil {
+			return n
+		}
+	}
+	return string(c)
+}
+
+// check applies the manifest's rules to a record, through a struct
+// holding a field for each path in the manifest, so that the rules mean
+// what they would in a struct's tags. Fields absent from the record are
+// nil pointers, which only the reserved rules check.
+func check(rec map[string]interface{}, rules map[string]string) []error {
+	paths := make([]string, 0, len(rules))
+	for p := range rules {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	fields := make([]reflect.StructField, len(paths))
+	values := make([]reflect.Value, len(paths))
+	for i, p := range paths {
+		x := lookup(rec, p)
+		if c, ok := x.(cell); ok {
+			x = c.scalar(rules[p])
+		}
+		val := reflect.ValueOf(x)
+		if !val.IsValid() {
+			val = reflect.Zero(absentType)
+		}
+		fields[i] = reflect.StructField{
+			Name: "F" + strconv.Itoa(i),
+			Type: val.Type(),
+			Tag:  reflect.StructTag("path:" + strconv.Quote(p) + " validate:" + strconv.Quote(rules[p])),
+		}
+		values[i] = val
+	}
+	s := reflect.New(reflect.StructOf(fields)).Elem()
+	for i, val := range values {
+		s.Field(i).Set(val)
+	}
+	return validators.ValidateOpts(s.Addr().Interface(),
+		validate.NameTags("path"),
+		validate.TagNameFunc(func(p string) string { return p }))
+}
+
+var absentType = reflect.TypeOf((*interface{})(nil))
+
+func lookup(rec map[string]interface{}, path string) interface{} {
+	var cur interface{} = rec
+	for _, key := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		cur = m[key]
+	}
+	return cur
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mccoy.space/g/validate/internal/sarif"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestRun_json(t *testing.T) {
+	dir := t.TempDir()
+	rules := writeFile(t, dir, "rules.json", `{"name": "nonzero,string", "server.port": "number"}`)
+	doc := writeFile(t, dir, "doc.json", `[
+		{"name": "a", "server": {"port": 80}},
+		{"name": "", "server": {"port": "eighty"}}
+	]`)
+
+	var out, errOut bytes.Buffer
+	code := run([]string{"-rules", rules, doc}, &out, &errOut)
+	if code != 1 {
+		t.Fatalf("wrong exit code %d for an invalid document: %s", code, errOut.String())
+	}
+	want := doc + ":2: field name is invalid: should be nonzero\n" +
+		doc + ":2: field server.port is invalid: eighty is not a number\n"
+	if out.String() != want {
+		t.Fatalf("wrong output:\n%s\nwanted:\n%s", out.String(), want)
+	}
+}
+
+func TestRun_csv(t *testing.T) {
+	dir := t.TempDir()
+	rules := writeFile(t, dir, "rules.json", `{"name": "nonzero", "age": "number"}`)
+	doc := writeFile(t, dir, "doc.csv", "name,age\nann,31\nbob,old\n")
+
+	var out, errOut bytes.Buffer
+	code := run([]string{"-rules", rules, "-json", doc}, &out, &errOut)
+	if code != 1 {
+		t.Fatalf("wrong exit code %d for an invalid document: %s", code, errOut.String())
+	}
+
+	var found []finding
+	if err := json.Unmarshal(out.Bytes(), &found); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	if len(found) != 1 {
+		t.Fatalf("wrong number of findings: %v", found)
+	}
+	if found[0].Record != 2 || found[0].Field != "age" {
+		t.Fatalf("wrong finding: %+v", found[0])
+	}
+}
+
+func TestRun_valid(t *testing.T) {
+	dir := t.TempDir()
+	rules := writeFile(t, dir, "rules.json", `{"name": "nonzero"}`)
+	doc := writeFile(t, dir, "doc.json", `{"name": "ok"}`)
+
+	var out, errOut bytes.Buffer
+	if code := run([]string{"-rules", rules, doc}, &out, &errOut); code != 0 {
+		t.Fatalf("wrong exit code %d for a valid document: %s%s", code, out.String(), errOut.String())
+	}
+}
+
+func TestRun_unreadable(t *testing.T) {
+	dir := t.TempDir()
+	rules := writeFile(t, dir, "rules.json", `{"name": "nonzero"}`)
+
+	var out, errOut bytes.Buffer
+	code := run([]string{"-rules", rules, filepath.Join(dir, "missing.json")}, &out, &errOut)
+	if code != 2 {
+		t.Fatalf("wrong exit code %d for a missing document", code)
+	}
+}
+
+func TestCheck_sensitive(t *testing.T) {
+	rec := map[string]interface{}{"token": "s3cret"}
+	errs := check(rec, map[string]string{"token": "sensitive,number"})
+	if len(errs) != 1 {
+		t.Fatalf("wrong number of errors: %v", errs)
+	}
+	if s := fmt.Sprintf("%+v", errs[0]); strings.Contains(s, "s3cret") {
+		t.Fatalf("sensitive value appears in error: %s", s)
+	}
+}
+
+func TestRun_sarif(t *testing.T) {
+	dir := t.TempDir()
+	rules := writeFile(t, dir, "rules.json", `{"name": "nonzero"}`)
+	doc := writeFile(t, dir, "doc.json", `[{"name": "ok"}, {"name": ""}]`)
+
+	var out, errOut bytes.Buffer
+	if code := run([]string{"-rules", rules, "-sarif", doc}, &out, &errOut); code != 1 {
+		t.Fatalf("wrong exit code %d for an invalid document: %s", code, errOut.String())
+	}
+
+	var log sarif.Log
+	if err := json.Unmarshal(out.Bytes(), &log); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("wrong SARIF log: %+v", log)
+	}
+	results := log.Runs[0].Results
+	if len(results) != 1 || results[0].RuleID != "nonzero" {
+		t.Fatalf("wrong results: %+v", results)
+	}
+	if loc := results[0].Locations[0].LogicalLocations[0].FullyQualifiedName; loc != "2/name" {
+		t.Fatalf("wrong logical location: %s", loc)
+	}
+}
+
+func TestCheck_rules(t *testing.T) {
+	rec := map[string]interface{}{
+		"name":   "ab",
+		"server": map[string]interface{}{"port": 8080.0},
+		"role":   "admin",
+	}
+	errs := check(rec, map[string]string{
+		"name":        "required,minlen=3",
+		"server.port": "gte=1,lte=1000",
+		"role":        "oneof=admin user",
+		"email":       "required,email",
+		"nick":        "omitempty,minlen=3",
+		"typo":        "nonsense",
+	})
+	var got []string
+	for _, err := range errs {
+		got = append(got, err.Error())
+	}
+	want := []string{
+		`field email is invalid: is required`,
+		`field name is invalid: length 2 is less than 3`,
+		`field server.port is invalid: 8080 is greater than 1000`,
+		`field typo is invalid: undefined validator: "nonsense"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("wrong errors:\n%s\nwanted:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCheck_csv(t *testing.T) {
+	rec := map[string]interface{}{
+		"count": cell("5"),
+		"ratio": cell("-1.5e1"),
+		"on":    cell("true"),
+		"zip":   cell("02134"),
+		"name":  cell("NaN"),
+		"note":  cell(""),
+	}
+	errs := check(rec, map[string]string{
+		"count": "number,gte=0,lte=4",
+		"ratio": "gte=0",
+		"on":    "bool",
+		"zip":   "string,len=5",
+		"name":  "string,minlen=4",
+		"note":  "required,minlen=1",
+	})
+	var got []string
+	for _, err := range errs {
+		got = append(got, err.Error())
+	}
+	want := []string{
+		`field count is invalid: 5 is greater than 4`,
+		`field name is invalid: length 3 is less than 4`,
+		`field note is invalid: is required`,
+		`field note is invalid: length 0 is less than 1`,
+		`field ratio is invalid: -15 is less than 0`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("wrong errors:\n%s\nwanted:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,33 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"mccoy.space/g/validate/internal/sarif"
+)
+
+// sarifLog converts findings into a SARIF log with a single run.
+// Each finding's logical location is its record and field,
+// as in "3/server.port".
+func sarifLog(found []finding) sarif.Log {
+	results := make([]sarif.Result, len(found))
+	for i, f := range found {
+		results[i] = sarif.Result{
+			RuleID:  f.Rule,
+			Level:   "error",
+			Message: sarif.Message{Text: fmt.Sprintf("field %s is invalid: %s", f.Field, f.Error)},
+			Locations: []sarif.Location{{
+				PhysicalLocation: sarif.PhysicalLocation{
+					ArtifactLocation: sarif.ArtifactLocation{URI: filepath.ToSlash(f.File)},
+				},
+				LogicalLocations: []sarif.LogicalLocation{{
+					FullyQualifiedName: fmt.Sprintf("%d/%s", f.Record, f.Field),
+				}},
+			}},
+		}
+	}
+	return sarif.New("validate", results)
+}
@@ -0,0 +1,545 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// readYAML reads the records of a YAML stream, each of whose documents
+// is a mapping or a sequence of mappings, as a JSON document is for
+// readJSON.
+//
+// It reads the part of YAML that configuration files use: block mappings
+// and sequences, flow collections, quoted and plain scalars, literal and
+// folded block scalars, and comments. Anchors, aliases, tags, and flow
+// collections and scalars spanning lines are reported as errors. Scalars
+// are resolved as in YAML's core schema, except that every number is a
+// float64, as in JSON documents.
+func readYAML(r io.Reader) ([]map[string]interface{}, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := splitYAML(string(b))
+	if err != nil {
+		return nil, err
+	}
+
+	var records []map[string]interface{}
+	for _, doc := range docs {
+		p := yamlParser{lines: doc}
+		node, err := p.document()
+		if err != nil {
+			return nil, err
+		}
+		switch d := node.(type) {
+		case nil:
+		case map[string]interface{}:
+			records = append(records, d)
+		case []interface{}:
+			for _, e := range d {
+				m, ok := e.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("record %d is not a mapping", len(records)+1)
+				}
+				records = append(records, m)
+			}
+		default:
+			return nil, errors.New("document is not a mapping or a sequence of mappings")
+		}
+	}
+	return records, nil
+}
+
+// A yamlLine is a line of a YAML document.
+type yamlLine struct {
+	num    int    // the line's number in the stream, from 1
+	indent int    // the number of spaces indenting the line
+	text   string // the line without indentation or comment
+	raw    string // the line as written, for block scalars
+}
+
+func (l *yamlLine) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", l.num, fmt.Sprintf(format, args...))
+}
+
+// splitYAML splits a YAML stream into the lines of its documents.
+func splitYAML(s string) ([][]yamlLine, error) {
+	var docs [][]yamlLine
+	var cur []yamlLine
+	for i, raw := range strings.Split(s, "\n") {
+		raw = strings.TrimSuffix(raw, "\r")
+		text := strings.TrimLeft(raw, " ")
+		l := yamlLine{i + 1, len(raw) - len(text), strings.TrimRight(stripComment(text), " \t"), raw}
+		switch {
+		case l.indent == 0 && (l.text == "---" || l.text == "..."):
+			docs = append(docs, cur)
+			cur = nil
+			continue
+		case l.indent == 0 && strings.HasPrefix(l.text, "%"):
+			// A directive, such as %YAML 1.2.
+			continue
+		case strings.HasPrefix(l.text, "\t"):
+			return nil, l.errorf("tab in indentation")
+		}
+		cur = append(cur, l)
+	}
+	return append(docs, cur), nil
+}
+
+// stripComment returns s without its comment, if any.
+func stripComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			// A quote opens a scalar only where one may begin.
+			if i == 0 || strings.IndexByte(" \t:[{,-", s[i-1]) >= 0 {
+				quote = c
+			}
+		case c == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// A yamlParser parses the lines of a YAML document.
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// peek returns the next line with content, skipping blank lines.
+func (p *yamlParser) peek() (*yamlLine, bool) {
+	for p.pos < len(p.lines) && p.lines[p.pos].text == "" {
+		p.pos++
+	}
+	if p.pos == len(p.lines) {
+		return nil, false
+	}
+	return &p.lines[p.pos], true
+}
+
+// document parses the whole document.
+func (p *yamlParser) document() (interface{}, error) {
+	l, ok := p.peek()
+	if !ok {
+		return nil, nil
+	}
+	node, err := p.node(l.indent)
+	if err != nil {
+		return nil, err
+	}
+	if l, ok := p.peek(); ok {
+		return nil, l.errorf("unexpected indentation")
+	}
+	return node, nil
+}
+
+// node parses the block node beginning at the next line, which is
+// indented by indent.
+func (p *yamlParser) node(indent int) (interface{}, error) {
+	l, _ := p.peek()
+	if isSeqItem(l.text) {
+		return p.seq(indent)
+	}
+	if _, _, ok, _ := splitKey(l.text); ok {
+		return p.mapping(indent)
+	}
+	p.pos++
+	return p.inline(l, l.text, indent-1)
+}
+
+// nested parses the node following a key or sequence item with nothing
+// after it, which is nil unless the next line is indented further.
+func (p *yamlParser) nested(indent int) (interface{}, error) {
+	l, ok := p.peek()
+	if !ok || l.indent <= indent {
+		return nil, nil
+	}
+	return p.node(l.indent)
+}
+
+func isSeqItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// seq parses a block sequence whose items are indented by indent.
+func (p *yamlParser) seq(indent int) (interface{}, error) {
+	items := []interface{}{}
+	for {
+		l, ok := p.peek()
+		if !ok || l.indent < indent || l.indent == indent && !isSeqItem(l.text) {
+			return items, nil
+		}
+		if l.indent > indent {
+			return nil, l.errorf("unexpected indentation")
+		}
+
+		var item interface{}
+		var err error
+		rest := strings.TrimLeft(l.text[1:], " ")
+		_, _, isKey, _ := splitKey(rest)
+		switch {
+		case rest == "":
+			p.pos++
+			item, err = p.nested(indent)
+		case isSeqItem(rest) || isKey:
+			// The item is a collection beginning on the item's line,
+			// which is parsed as though it began on a line of its own.
+			l.indent += len(l.text) - len(rest)
+			l.text = rest
+			item, err = p.node(l.indent)
+		default:
+			p.pos++
+			item, err = p.inline(l, rest, indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+}
+
+// mapping parses a block mapping whose keys are indented by indent.
+func (p *yamlParser) mapping(indent int) (interface{}, error) {
+	m := make(map[string]interface{})
+	for {
+		l, ok := p.peek()
+		if !ok || l.indent < indent {
+			return m, nil
+		}
+		if l.indent > indent {
+			return nil, l.errorf("unexpected indentation")
+		}
+		key, rest, ok, err := splitKey(l.text)
+		if err != nil {
+			return nil, l.errorf("%v", err)
+		}
+		if !ok {
+			return nil, l.errorf("expected a key")
+		}
+		if _, dup := m[key]; dup {
+			return nil, l.errorf("duplicate key %q", key)
+		}
+		p.pos++
+
+		var val interface{}
+		if rest != "" {
+			val, err = p.inline(l, rest, indent)
+		} else if n, ok := p.peek(); ok && n.indent == indent && isSeqItem(n.text) {
+			// A sequence may be indented as much as its key.
+			val, err = p.seq(indent)
+		} else {
+			val, err = p.nested(indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		m[key] = val
+	}
+}
+
+// splitKey splits text into the key and the rest of a mapping entry,
+// and reports whether it is one.
+func splitKey(text string) (key, rest string, ok bool, err error) {
+	if text == "" || text[0] == '[' || text[0] == '{' || isSeqItem(text) {
+		return "", "", false, nil
+	}
+	if text[0] == '"' || text[0] == '\'' {
+		key, rest, err := scanQuoted(text)
+		if err != nil {
+			return "", "", false, err
+		}
+		rest = strings.TrimLeft(rest, " ")
+		if rest == ":" || strings.HasPrefix(rest, ": ") {
+			return key, strings.TrimSpace(rest[1:]), true, nil
+		}
+		return "", "", false, nil
+	}
+	i := strings.Index(text, ": ")
+	if i < 0 {
+		if !strings.HasSuffix(text, ":") {
+			return "", "", false, nil
+		}
+		i = len(text) - 1
+	}
+	return strings.TrimRight(text[:i], " "), strings.TrimSpace(text[i+1:]), true, nil
+}
+
+// inline parses the node written as text on the line l, following a key
+// or sequence item indented by indent.
+func (p *yamlParser) inline(l *yamlLine, text string, indent int) (interface{}, error) {
+	switch text[0] {
+	case '|', '>':
+		return p.block(l, text, indent)
+	case '&', '*', '!':
+		return nil, l.errorf("anchors, aliases, and tags are not supported")
+	}
+	var val interface{}
+	var rest string
+	var err error
+	switch text[0] {
+	case '[', '{':
+		val, rest, err = parseFlow(text)
+	case '"', '\'':
+		val, rest, err = scanQuoted(text)
+	default:
+		return resolve(text), nil
+	}
+	if err != nil {
+		return nil, l.errorf("%v", err)
+	}
+	if rest = strings.TrimSpace(rest); rest != "" {
+		return nil, l.errorf("unexpected %q", rest)
+	}
+	return val, nil
+}
+
+// block parses a block scalar, whose header is the text following its key
+// or sequence item, and whose lines follow l, indented further than indent.
+func (p *yamlParser) block(l *yamlLine, header string, indent int) (interface{}, error) {
+	chomp := header[1:]
+	if chomp != "" && chomp != "-" && chomp != "+" {
+		return nil, l.errorf("unsupported block scalar header %q", header)
+	}
+
+	var lines []string
+	content := -1
+	for ; p.pos < len(p.lines); p.pos++ {
+		raw := p.lines[p.pos].raw
+		if strings.TrimSpace(raw) == "" {
+			lines = append(lines, "")
+			continue
+		}
+		n := len(raw) - len(strings.TrimLeft(raw, " "))
+		if content < 0 {
+			content = n
+		}
+		if n <= indent || n < content {
+			break
+		}
+		lines = append(lines, raw[content:])
+	}
+	trailing := 0
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+		trailing++
+	}
+	if len(lines) == 0 {
+		return "", nil
+	}
+
+	var b strings.Builder
+	for i, s := range lines {
+		if i > 0 {
+			prev := lines[i-1]
+			switch {
+			case header[0] == '|' || s == "":
+				b.WriteByte('\n')
+			case prev == "":
+				// The empty lines have broken the line.
+			case strings.HasPrefix(s, " ") || strings.HasPrefix(prev, " "):
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(s)
+	}
+	switch chomp {
+	case "":
+		b.WriteByte('\n')
+	case "+":
+		b.WriteString(strings.Repeat("\n", trailing+1))
+	}
+	return b.String(), nil
+}
+
+// parseFlow parses the flow node at the start of s, and returns the rest.
+func parseFlow(s string) (interface{}, string, error) {
+	s = strings.TrimLeft(s, " ")
+	if s == "" {
+		return nil, "", errors.New("unterminated flow collection; flow collections must be on one line")
+	}
+	switch s[0] {
+	case '[':
+		items := []interface{}{}
+		s = strings.TrimLeft(s[1:], " ")
+		for {
+			if strings.HasPrefix(s, "]") {
+				return items, s[1:], nil
+			}
+			item, rest, err := parseFlow(s)
+			if err != nil {
+				return nil, "", err
+			}
+			items = append(items, item)
+			if s, err = flowNext(rest, ']'); err != nil {
+				return nil, "", err
+			}
+		}
+	case '{':
+		m := make(map[string]interface{})
+		s = strings.TrimLeft(s[1:], " ")
+		for {
+			if strings.HasPrefix(s, "}") {
+				return m, s[1:], nil
+			}
+			key, rest, err := flowKey(s)
+			if err != nil {
+				return nil, "", err
+			}
+			if _, dup := m[key]; dup {
+				return nil, "", fmt.Errorf("duplicate key %q", key)
+			}
+			val, rest, err := parseFlow(rest)
+			if err != nil {
+				return nil, "", err
+			}
+			m[key] = val
+			if s, err = flowNext(rest, '}'); err != nil {
+				return nil, "", err
+			}
+		}
+	case '"', '\'':
+		return scanQuoted(s)
+	case '&', '*', '!':
+		return nil, "", errors.New("anchors, aliases, and tags are not supported")
+	}
+	i := strings.IndexAny(s, ",]}")
+	if i < 0 {
+		i = len(s)
+	}
+	return resolve(strings.TrimRight(s[:i], " ")), s[i:], nil
+}
+
+// flowNext returns the rest of a flow collection ending in end after
+// one of its entries, without the comma separating them.
+func flowNext(rest string, end byte) (string, error) {
+	rest = strings.TrimLeft(rest, " ")
+	switch {
+	case rest == "":
+		return "", errors.New("unterminated flow collection; flow collections must be on one line")
+	case rest[0] == ',':
+		return strings.TrimLeft(rest[1:], " "), nil
+	case rest[0] == end:
+		return rest, nil
+	}
+	return "", fmt.Errorf("unexpected %q in flow collection", rest)
+}
+
+// flowKey parses the key at the start of an entry of a flow mapping,
+// and returns the rest of the entry, after the colon.
+func flowKey(s string) (string, string, error) {
+	var key, rest string
+	if s[0] == '"' || s[0] == '\'' {
+		k, r, err := scanQuoted(s)
+		if err != nil {
+			return "", "", err
+		}
+		key, rest = k, strings.TrimLeft(r, " ")
+	} else {
+		i := strings.IndexAny(s, ":,}")
+		if i < 0 {
+			i = len(s)
+		}
+		key, rest = strings.TrimRight(s[:i], " "), s[i:]
+	}
+	if !strings.HasPrefix(rest, ":") {
+		return "", "", fmt.Errorf("expected a colon after key %q", key)
+	}
+	return key, rest[1:], nil
+}
+
+// scanQuoted parses the quoted scalar at the start of s,
+// and returns the rest.
+func scanQuoted(s string) (string, string, error) {
+	q := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == q && q == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			b.WriteByte('\'')
+			i++
+		case c == q:
+			return b.String(), s[i+1:], nil
+		case c == '\\' && q == '"':
+			if i++; i == len(s) {
+				continue
+			}
+			if r, ok := yamlEscapes[s[i]]; ok {
+				b.WriteRune(r)
+				continue
+			}
+			n := map[byte]int{'x': 2, 'u': 4, 'U': 8}[s[i]]
+			if n == 0 || i+n >= len(s) {
+				return "", "", fmt.Errorf("bad escape in %s", s)
+			}
+			r, err := strconv.ParseUint(s[i+1:i+1+n], 16, 32)
+			if err != nil {
+				return "", "", fmt.Errorf("bad escape in %s", s)
+			}
+			b.WriteRune(rune(r))
+			i += n
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", "", fmt.Errorf("unterminated quoted scalar %s; quoted scalars must be on one line", s)
+}
+
+var yamlEscapes = map[byte]rune{
+	'0': 0, 'a': '\a', 'b': '\b', 't': '\t', '\t': '\t', 'n': '\n', 'v': '\v', 'f': '\f',
+	'r': '\r', 'e': 0x1b, ' ': ' ', '"': '"', '/': '/', '\\': '\\',
+	'N': 0x85, '_': 0xa0, 'L': 0x2028, 'P': 0x2029,
+}
+
+var yamlNumber = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+
+// resolve returns the value of a plain scalar.
+func resolve(s string) interface{} {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	case ".inf", ".Inf", ".INF", "+.inf", "+.Inf", "+.INF":
+		return math.Inf(1)
+	case "-.inf", "-.Inf", "-.INF":
+		return math.Inf(-1)
+	case ".nan", ".NaN", ".NAN":
+		return math.NaN()
+	}
+	if yamlNumber.MatchString(s) {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	for prefix, base := range map[string]int{"0o": 8, "0x": 16} {
+		if strings.HasPrefix(s, prefix) {
+			if n, err := strconv.ParseUint(s[2:], base, 64); err == nil {
+				return float64(n)
+			}
+		}
+	}
+	return s
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadYAML(t *testing.T) {
+	doc := `%YAML 1.2
+---
+# The first record.
+name: "ann # not a comment"
+age: 31
+tags: [a, 'b''s', {k: v}]
+server:
+  port: 0x50
+  hosts:
+  - one
+  - two
+note: |
+  line one
+  line two
+
+summary: >-
+  folded
+  text
+---
+- name: bob   # a comment
+  admin: true
+  nothing: ~
+- {name: cy, age: -1.5e1}
+...
+`
+	records, err := readYAML(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []map[string]interface{}{
+		{
+			"name": "ann # not a comment",
+			"age":  31.0,
+			"tags": []interface{}{"a", "b's", map[string]interface{}{"k": "v"}},
+			"server": map[string]interface{}{
+				"port":  80.0,
+				"hosts": []interface{}{"one", "two"},
+			},
+			"note":    "line one\nline two\n",
+			"summary": "folded text",
+		},
+		{"name": "bob", "admin": true, "nothing": nil},
+		{"name": "cy", "age": -15.0},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Fatalf("wrong records:\n%#v\nwanted:\n%#v", records, want)
+	}
+}
+
+func TestReadYAML_errors(t *testing.T) {
+	for doc, want := range map[string]string{
+		"a: 1\n  b: 2\n":      "line 2: unexpected indentation",
+		"a: 1\na: 2\n":        `line 2: duplicate key "a"`,
+		"a: &x 1\n":           "line 1: anchors, aliases, and tags are not supported",
+		"a: [1,\n  2]\n":      "line 1: unterminated flow collection",
+		"a: \"open\n":         "line 1: unterminated quoted scalar",
+		"- 1\n- 2\n":          "record 1 is not a mapping",
+		"just a scalar\n":     "document is not a mapping",
+		"a:\n\t- 1\n":         "line 2: tab in indentation",
+		"a: \"bad \\q\"\n":    "line 1: bad escape",
+		"a: 1\n- b\n":         "line 2: expected a key",
+		"a: |9\n  text\n":     "line 1: unsupported block scalar header",
+		"a: {b 1}\n":          "line 1: expected a colon after key",
+		"a: [1] trailing\n":   `line 1: unexpected "trailing"`,
+		"- a: 1\n b: 2\n":     "line 2: unexpected indentation",
+		"a:\n  - 1\n  b: 2\n": "line 3: unexpected indentation",
+	} {
+		_, err := readYAML(strings.NewReader(doc))
+		if err == nil || !strings.HasPrefix(err.Error(), want) {
+			t.Errorf("wrong error for %q: %v, wanted %s", doc, err, want)
+		}
+	}
+}
+
+func TestRun_yaml(t *testing.T) {
+	dir := t.TempDir()
+	rules := writeFile(t, dir, "rules.json", `{"name": "required,minlen=2", "server.port": "number,lte=65535"}`)
+	doc := writeFile(t, dir, "doc.yaml", "name: ok\nserver:\n  port: 80\n---\nname: x\nserver:\n  port: 99999\n")
+
+	var out, errOut bytes.Buffer
+	if code := run([]string{"-rules", rules, doc}, &out, &errOut); code != 1 {
+		t.Fatalf("wrong exit code %d for an invalid document: %s", code, errOut.String())
+	}
+	want := doc + ":2: field name is invalid: length 1 is less than 2\n" +
+		doc + ":2: field server.port is invalid: 99999 is greater than 65535\n"
+	if out.String() != want {
+		t.Fatalf("wrong output:\n%s\nwanted:\n%s", out.String(), want)
+	}
+}
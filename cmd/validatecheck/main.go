@@ -0,0 +1,280 @@
+// © 2013 Steve McCoy under the MIT license.
+
+/*
+Command validatecheck reports mistakes in the validate tags of Go code,
+which would otherwise be found only when values are validated.
+
+Usage:
+
+	validatecheck [-allow file] [-sarif] path...
+	go vet -vettool=$(which validatecheck) [-allow=file] [packages]
+
+It reports:
+
+  - tags that cannot be parsed;
+  - rules named more than once in a tag, with the same parameter;
+  - "struct" rules on fields that are not structs;
+  - given -allow, rules not listed in the file, which holds the names of
+    the allowed rules separated by white space. The reserved rules are
+    always allowed.
+
+Run directly, it checks the Go files it is given, and those in the
+directories it is given, along with their subdirectories for paths
+ending in "/...". The files are not type-checked, so "struct" rules are
+reported only on fields whose types are plainly not structs, such as int,
+[]T, or a type declared as one in the same file.
+
+Run by go vet, it type-checks each package, and reports every "struct"
+rule on a field whose type is not a struct, a pointer to one, or an
+interface.
+
+Findings are printed one per line, or, when run directly with -sarif,
+as a SARIF 2.1.0 log for code scanning tools. The exit status is 0 when
+nothing is found, 1 when anything is, and 2 when the code cannot be read.
+*/
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"mccoy.space/g/validate"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// A finding is one mistake in a tag.
+type finding struct {
+	Pos     token.Position
+	Check   string // the kind of mistake: syntax, duplicate, struct, or allow
+	Message string
+}
+
+func (f finding) String() string {
+	return fmt.Sprintf("%s: %s", f.Pos, f.Message)
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 1 && args[0] == "-V=full" {
+		return printVersion(stdout, stderr)
+	}
+	if len(args) == 1 && args[0] == "-flags" {
+		return printFlags(stdout)
+	}
+
+	fs := flag.NewFlagSet("validatecheck", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	allowPath := fs.String("allow", "", "path to a file listing the allowed rules")
+	asSARIF := fs.Bool("sarif", false, "print findings as a SARIF log")
+	asJSON := fs.Bool("json", false, "print findings as JSON, for go vet")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "usage: validatecheck [-allow file] [-sarif] path...")
+		return 2
+	}
+
+	c := &checker{fset: token.NewFileSet()}
+	if *allowPath != "" {
+		b, err := os.ReadFile(*allowPath)
+		if err != nil {
+			fmt.Fprintln(stderr, "validatecheck:", err)
+			return 2
+		}
+		c.allow = make(map[string]bool)
+		for _, name := range strings.Fields(string(b)) {
+			c.allow[name] = true
+		}
+	}
+
+	var cfg *vetConfig
+	var err error
+	if fs.NArg() == 1 && strings.HasSuffix(fs.Arg(0), ".cfg") {
+		cfg, err = c.vet(fs.Arg(0))
+	} else {
+		err = c.paths(fs.Args())
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "validatecheck:", err)
+		return 2
+	}
+
+	sort.SliceStable(c.found, func(i, j int) bool {
+		a, b := c.found[i].Pos, c.found[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+	switch {
+	case cfg != nil && *asJSON:
+		if err := cfg.writeJSON(c.found, stdout); err != nil {
+			fmt.Fprintln(stderr, "validatecheck:", err)
+			return 2
+		}
+		// go vet reports the findings, and fails, itself.
+		return 0
+	case *asSARIF:
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "\t")
+		enc.Encode(sarifLog(c.found))
+	default:
+		// Like other vet tools, report to stderr, where go vet expects it.
+		out := stdout
+		if cfg != nil {
+			out = stderr
+		}
+		for _, f := range c.found {
+			fmt.Fprintln(out, f)
+		}
+	}
+
+	if len(c.found) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// paths checks the Go files named by paths.
+func (c *checker) paths(paths []string) error {
+	for _, p := range paths {
+		dir, recursive := strings.CutSuffix(p, "/...")
+		info, err := os.Stat(dir)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			if err := c.parse(p); err != nil {
+				return err
+			}
+			continue
+		}
+		err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != dir && (!recursive || strings.HasPrefix(d.Name(), ".") || d.Name() == "testdata") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(path, ".go") {
+				return c.parse(path)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *checker) parse(path string) error {
+	f, err := parser.ParseFile(c.fset, path, nil, 0)
+	if err != nil {
+		return err
+	}
+	c.file(f)
+	return nil
+}
+
+// A checker finds mistakes in the validate tags of files.
+type checker struct {
+	fset *token.FileSet
+
+	// info holds the types of the files' expressions,
+	// if they were type-checked.
+	info *types.Info
+
+	// allow holds the allowed rules, if they are restricted.
+	allow map[string]bool
+
+	found []finding
+}
+
+func (c *checker) report(pos token.Pos, check, format string, args ...interface{}) {
+	c.found = append(c.found, finding{c.fset.Position(pos), check, fmt.Sprintf(format, args...)})
+}
+
+// file checks the tags of the fields of the structs in f.
+func (c *checker) file(f *ast.File) {
+	ast.Inspect(f, func(n ast.Node) bool {
+		st, ok := n.(*ast.StructType)
+		if !ok {
+			return true
+		}
+		for _, field := range st.Fields.List {
+			if field.Tag == nil {
+				continue
+			}
+			raw, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				continue
+			}
+			tag, ok := reflect.StructTag(raw).Lookup("validate")
+			if !ok || tag == "-" {
+				continue
+			}
+			rules, err := validate.ParseTag(tag)
+			if err != nil {
+				c.report(field.Tag.Pos(), "syntax", "%v", err)
+				continue
+			}
+			c.rules(field.Tag.Pos(), c.typeOf(field.Type), rules)
+		}
+		return true
+	})
+}
+
+// rules checks rules, which apply to values of type t, in a tag at pos.
+func (c *checker) rules(pos token.Pos, t fieldType, rules []validate.Rule) {
+	seen := make(map[validate.Rule]bool)
+	for k, r := range rules {
+		if seen[r] {
+			c.report(pos, "duplicate", "rule %q is named more than once", r.Name)
+		}
+		seen[r] = true
+
+		if c.allow != nil && !validate.Reserved(r.Name) && !c.allow[r.Name] {
+			c.report(pos, "allow", "rule %q is not allowed", r.Name)
+		}
+
+		switch r.Name {
+		case "struct":
+			if t.notStruct() {
+				c.report(pos, "struct", "rule \"struct\" on a field of type %s, which is not a struct", t)
+			}
+		case "each", "keys", "values":
+			erules := rules[k+1:]
+			if r.Param != "" {
+				var err error
+				if erules, err = validate.ParseTag(r.Param); err != nil {
+					c.report(pos, "syntax", "%v", err)
+					continue
+				}
+			}
+			c.rules(pos, t.elem(r.Name), erules)
+			if r.Param == "" {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mccoy.space/g/validate/internal/sarif"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+const testSource = `package x
+
+type Point struct{ X, Y int }
+
+type IDs []int
+
+type X struct {
+	A int            ` + "`validate:\"struct\"`" + `
+	B Point          ` + "`validate:\"struct,odd,odd\"`" + `
+	C *Point         ` + "`validate:\"struct\"`" + `
+	D []Point        ` + "`validate:\"each,struct\"`" + `
+	E map[string]int ` + "`validate:\"values,struct\"`" + `
+	F IDs            ` + "`validate:\"struct\"`" + `
+	G string         ` + "`validate:\"a,,b\"`" + `
+	H interface{}    ` + "`validate:\"struct,typo\"`" + `
+	I Other          ` + "`validate:\"struct\"`" + `
+	J int            ` + "`json:\"j\"`" + `
+}
+`
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "x.go", testSource)
+	writeFile(t, dir, "y.go", "package x\n\ntype Other int\n")
+	writeFile(t, dir, "sub/z.go", "package sub\n\ntype Z struct {\n\tA int `validate:\"struct\"`\n}\n")
+	allow := writeFile(t, dir, "allow", "odd\na b\n")
+
+	var out, errOut bytes.Buffer
+	code := run([]string{"-allow", allow, dir}, &out, &errOut)
+	if code != 1 {
+		t.Fatalf("wrong exit code %d: %s", code, errOut.String())
+	}
+	x := filepath.Join(dir, "x.go")
+	want := x + `:8:19: rule "struct" on a field of type int, which is not a struct
+` + x + `:9:19: rule "odd" is named more than once
+` + x + `:12:19: rule "struct" on a field of type int, which is not a struct
+` + x + `:13:19: rule "struct" on a field of type IDs, which is not a struct
+` + x + `:14:19: empty rule at position 2 in tag "a,,b"
+` + x + `:15:19: rule "typo" is not allowed
+`
+	if out.String() != want {
+		t.Fatalf("wrong output:\n%s\nwanted:\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	if code := run([]string{dir + "/..."}, &out, &errOut); code != 1 || strings.Count(out.String(), "\n") != 6 {
+		t.Fatalf("wrong findings in subdirectories (%d):\n%s", code, out.String())
+	}
+
+	out.Reset()
+	if code := run([]string{filepath.Join(dir, "y.go")}, &out, &errOut); code != 0 || out.Len() != 0 {
+		t.Fatalf("findings in a good file (%d): %s", code, out.String())
+	}
+}
+
+func TestRun_sarif(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "x.go", testSource)
+
+	var out, errOut bytes.Buffer
+	if code := run([]string{"-sarif", dir}, &out, &errOut); code != 1 {
+		t.Fatalf("wrong exit code %d: %s", code, errOut.String())
+	}
+	var log sarif.Log
+	if err := json.Unmarshal(out.Bytes(), &log); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	res := log.Runs[0].Results
+	if len(res) != 5 || res[1].RuleID != "duplicate" || *res[1].Locations[0].PhysicalLocation.Region != (sarif.Region{StartLine: 9, StartColumn: 19}) {
+		t.Fatalf("wrong SARIF log: %+v", log)
+	}
+	if rules := log.Runs[0].Tool.Driver.Rules; len(rules) != 3 || rules[0].ID != "duplicate" {
+		t.Fatalf("wrong SARIF rules: %+v", rules)
+	}
+}
+
+func TestRun_vet(t *testing.T) {
+	dir := t.TempDir()
+	src := writeFile(t, dir, "x.go", strings.Replace(testSource, "Other", "IDs", 1))
+	cfg := writeFile(t, dir, "vet.cfg", `{
+		"ID": "x",
+		"Compiler": "gc",
+		"ImportPath": "x",
+		"GoFiles": ["`+filepath.ToSlash(src)+`"],
+		"VetxOutput": "`+filepath.ToSlash(filepath.Join(dir, "vetx"))+`"
+	}`)
+
+	var out, errOut bytes.Buffer
+	if code := run([]string{"-json", cfg}, &out, &errOut); code != 0 {
+		t.Fatalf("wrong exit code %d: %s", code, errOut.String())
+	}
+	var tree map[string]map[string][]struct {
+		Category string
+		Posn     string
+		Message  string
+	}
+	if err := json.Unmarshal(out.Bytes(), &tree); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out.String())
+	}
+	diags := tree["x"]["validatecheck"]
+	if len(diags) != 6 || diags[5].Category != "struct" || diags[5].Message != `rule "struct" on a field of type x.IDs, which is not a struct` {
+		t.Fatalf("wrong findings: %+v", diags)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "vetx")); err != nil {
+		t.Fatalf("no facts written: %v", err)
+	}
+
+	out.Reset()
+	if code := run([]string{"-flags"}, &out, &errOut); code != 0 || !strings.Contains(out.String(), `"Name":"allow"`) {
+		t.Fatalf("wrong flags (%d): %s", code, out.String())
+	}
+	out.Reset()
+	if code := run([]string{"-V=full"}, &out, &errOut); code != 0 || !strings.HasPrefix(out.String(), "validatecheck version devel") {
+		t.Fatalf("wrong version (%d): %s", code, out.String())
+	}
+}
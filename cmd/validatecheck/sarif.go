@@ -0,0 +1,29 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package main
+
+import (
+	"path/filepath"
+
+	"mccoy.space/g/validate/internal/sarif"
+)
+
+// sarifLog converts findings into a SARIF log with a single run,
+// whose rules are the kinds of mistakes found.
+func sarifLog(found []finding) sarif.Log {
+	results := make([]sarif.Result, len(found))
+	for i, f := range found {
+		results[i] = sarif.Result{
+			RuleID:  f.Check,
+			Level:   "error",
+			Message: sarif.Message{Text: f.Message},
+			Locations: []sarif.Location{{
+				PhysicalLocation: sarif.PhysicalLocation{
+					ArtifactLocation: sarif.ArtifactLocation{URI: filepath.ToSlash(f.Pos.Filename)},
+					Region:           &sarif.Region{StartLine: f.Pos.Line, StartColumn: f.Pos.Column},
+				},
+			}},
+		}
+	}
+	return sarif.New("validatecheck", results)
+}
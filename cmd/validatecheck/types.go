@@ -0,0 +1,142 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package main
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// fieldType is the type of a field, or of its elements, as known from its
+// declaration and, if the field was type-checked, from its types.Type.
+// Either may be missing, if it is not known.
+type fieldType struct {
+	expr ast.Expr
+	typ  types.Type
+}
+
+func (c *checker) typeOf(e ast.Expr) fieldType {
+	t := fieldType{expr: e}
+	if c.info != nil {
+		t.typ = c.info.TypeOf(e)
+	}
+	return t
+}
+
+func (t fieldType) String() string {
+	switch {
+	case t.typ != nil:
+		return t.typ.String()
+	case t.expr != nil:
+		return types.ExprString(t.expr)
+	}
+	return "unknown"
+}
+
+// notStruct reports whether t is known to be neither a struct,
+// a pointer to one, nor an interface.
+func (t fieldType) notStruct() bool {
+	if t.typ != nil {
+		u := t.typ.Underlying()
+		for {
+			p, ok := u.(*types.Pointer)
+			if !ok {
+				break
+			}
+			u = p.Elem().Underlying()
+		}
+		switch u := u.(type) {
+		case *types.Struct, *types.Interface:
+			return false
+		case *types.Basic:
+			return u.Kind() != types.Invalid
+		}
+		return true
+	}
+
+	switch e := deref(t.expr).(type) {
+	case *ast.ArrayType, *ast.MapType, *ast.ChanType, *ast.FuncType:
+		return true
+	case *ast.Ident:
+		if ts := typeSpec(e); ts != nil {
+			return fieldType{expr: ts.Type}.notStruct()
+		}
+		return e.Obj == nil && basic[e.Name]
+	}
+	return false
+}
+
+// elem returns the type of the elements of t to which the reserved rule
+// named rule applies rules: the elements of a slice or array for "each",
+// or the keys or values of a map for "keys" or "values".
+func (t fieldType) elem(rule string) fieldType {
+	if t.typ != nil {
+		u := t.typ.Underlying()
+		if p, ok := u.(*types.Pointer); ok {
+			u = p.Elem().Underlying()
+		}
+		switch u := u.(type) {
+		case *types.Slice:
+			return fieldType{typ: u.Elem()}
+		case *types.Array:
+			return fieldType{typ: u.Elem()}
+		case *types.Map:
+			if rule == "keys" {
+				return fieldType{typ: u.Key()}
+			}
+			return fieldType{typ: u.Elem()}
+		}
+		return fieldType{}
+	}
+
+	switch e := deref(t.expr).(type) {
+	case *ast.ArrayType:
+		return fieldType{expr: e.Elt}
+	case *ast.MapType:
+		if rule == "keys" {
+			return fieldType{expr: e.Key}
+		}
+		return fieldType{expr: e.Value}
+	case *ast.Ident:
+		if ts := typeSpec(e); ts != nil {
+			return fieldType{expr: ts.Type}.elem(rule)
+		}
+	}
+	return fieldType{}
+}
+
+// deref returns the type expression e, without pointers or parentheses.
+func deref(e ast.Expr) ast.Expr {
+	for {
+		switch x := e.(type) {
+		case *ast.StarExpr:
+			e = x.X
+		case *ast.ParenExpr:
+			e = x.X
+		default:
+			return e
+		}
+	}
+}
+
+// typeSpec returns the declaration of the type named by id,
+// if it is declared in the same file.
+func typeSpec(id *ast.Ident) *ast.TypeSpec {
+	if id.Obj == nil {
+		return nil
+	}
+	ts, _ := id.Obj.Decl.(*ast.TypeSpec)
+	if ts != nil && ts.Type == ast.Expr(id) {
+		return nil
+	}
+	return ts
+}
+
+// basic holds the names of the predeclared types that are not
+// structs or interfaces.
+var basic = map[string]bool{
+	"bool": true, "string": true, "byte": true, "rune": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"float32": true, "float64": true, "complex64": true, "complex128": true,
+}
@@ -0,0 +1,163 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/types"
+	"io"
+	"os"
+)
+
+// vetConfig is the description of a package that go vet passes to
+// vet tools, in a file named by their only argument.
+type vetConfig struct {
+	ID          string
+	Compiler    string
+	ImportPath  string
+	GoFiles     []string
+	ImportMap   map[string]string
+	PackageFile map[string]string
+	VetxOnly    bool
+	VetxOutput  string
+
+	// Stdout, if not "", names the file to which findings are
+	// written in JSON, rather than standard output.
+	Stdout string
+}
+
+// vet checks the package described by the vet configuration at path.
+func (c *checker) vet(path string) (*vetConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := new(vetConfig)
+	if err := json.Unmarshal(b, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	// validatecheck records no facts about packages,
+	// but go vet expects it to write them.
+	if cfg.VetxOutput != "" {
+		if err := os.WriteFile(cfg.VetxOutput, nil, 0o666); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.VetxOnly {
+		return cfg, nil
+	}
+
+	var files []*ast.File
+	for _, name := range cfg.GoFiles {
+		f, err := parser.ParseFile(c.fset, name, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	compiled := importer.ForCompiler(c.fset, cfg.Compiler, func(path string) (io.ReadCloser, error) {
+		file, ok := cfg.PackageFile[path]
+		if !ok {
+			return nil, fmt.Errorf("no package file for %q", path)
+		}
+		return os.Open(file)
+	})
+	conf := types.Config{
+		Importer: importerFunc(func(path string) (*types.Package, error) {
+			if p, ok := cfg.ImportMap[path]; ok {
+				path = p
+			}
+			return compiled.Import(path)
+		}),
+		// Errors are the compiler's to report. The types of
+		// erroneous expressions are unknown, and go unchecked.
+		Error: func(error) {},
+	}
+	c.info = &types.Info{Types: make(map[ast.Expr]types.TypeAndValue)}
+	conf.Check(cfg.ImportPath, c.fset, files, c.info)
+
+	for _, f := range files {
+		c.file(f)
+	}
+	return cfg, nil
+}
+
+// writeJSON writes found in the JSON form go vet reads from its tools:
+// an object mapping the package's ID to one mapping the tool's name
+// to its findings.
+func (cfg *vetConfig) writeJSON(found []finding, stdout io.Writer) error {
+	type diagnostic struct {
+		Category string `json:"category,omitempty"`
+		Posn     string `json:"posn"`
+		End      string `json:"end"`
+		Message  string `json:"message"`
+	}
+	diags := make([]diagnostic, len(found))
+	for i, f := range found {
+		diags[i] = diagnostic{f.Check, f.Pos.String(), f.Pos.String(), f.Message}
+	}
+	tree := map[string]map[string][]diagnostic{}
+	if len(diags) > 0 {
+		tree[cfg.ID] = map[string][]diagnostic{"validatecheck": diags}
+	}
+
+	if cfg.Stdout != "" {
+		f, err := os.Create(cfg.Stdout)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		stdout = f
+	}
+	b, err := json.MarshalIndent(tree, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(stdout, "%s\n", b)
+	return err
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) {
+	return f(path)
+}
+
+// printVersion prints the version of the executable for go vet,
+// which uses it to cache results.
+func printVersion(stdout, stderr io.Writer) int {
+	exe, err := os.Executable()
+	if err == nil {
+		var f *os.File
+		if f, err = os.Open(exe); err == nil {
+			defer f.Close()
+			h := sha256.New()
+			if _, err = io.Copy(h, f); err == nil {
+				fmt.Fprintf(stdout, "validatecheck version devel comments-go-here buildID=%02x\n", h.Sum(nil))
+				return 0
+			}
+		}
+	}
+	fmt.Fprintln(stderr, "validatecheck:", err)
+	return 2
+}
+
+// printFlags describes the flags to go vet, which passes them on.
+func printFlags(stdout io.Writer) int {
+	type flagDesc struct {
+		Name  string
+		Bool  bool
+		Usage string
+	}
+	json.NewEncoder(stdout).Encode([]flagDesc{
+		{"allow", false, "path to a file listing the allowed rules"},
+	})
+	return 0
+}
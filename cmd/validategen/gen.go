@@ -0,0 +1,570 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"mccoy.space/g/validate"
+)
+
+// A generator writes the validators of a package's struct types.
+type generator struct {
+	pkg      string
+	v        string
+	nameTags []string
+
+	// types holds the package's type declarations, by name,
+	// and order holds the names in the order they are declared.
+	types map[string]*ast.TypeSpec
+	order []string
+
+	buf    bytes.Buffer
+	queued map[string]bool
+	queue  []string
+	vars   int
+}
+
+func newGenerator(pkg, v string, nameTags []string, files []*ast.File) *generator {
+	g := &generator{
+		pkg:      pkg,
+		v:        v,
+		nameTags: nameTags,
+		types:    make(map[string]*ast.TypeSpec),
+		queued:   make(map[string]bool),
+	}
+	for _, f := range files {
+		for _, d := range f.Decls {
+			gd, ok := d.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, s := range gd.Specs {
+				if ts, ok := s.(*ast.TypeSpec); ok {
+					g.types[ts.Name.Name] = ts
+					g.order = append(g.order, ts.Name.Name)
+				}
+			}
+		}
+	}
+	return g
+}
+
+// generate returns the source of the validators of the named types,
+// or of every struct type with validate tags if none are named.
+func (g *generator) generate(names []string) ([]byte, error) {
+	if len(names) == 0 {
+		for _, name := range g.order {
+			if st := g.structType(name); st != nil && tagged(st) {
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("package %s has no structs with validate tags", g.pkg)
+		}
+	}
+
+	fmt.Fprintf(&g.buf, "// Code generated by validategen; DO NOT EDIT.\n\npackage %s\n\n", g.pkg)
+	g.buf.WriteString(imports)
+	for _, name := range names {
+		if g.structType(name) == nil {
+			return nil, fmt.Errorf("package %s has no struct type %s", g.pkg, name)
+		}
+		fmt.Fprintf(&g.buf, "// Validate%s validates x as %s.Validate would, without reflection.\n", export(name), g.v)
+		fmt.Fprintf(&g.buf, "func Validate%s(x %s) []error {\n\treturn %s(&x, nil)\n}\n\n", export(name), name, g.need(name))
+	}
+	for len(g.queue) > 0 {
+		name := g.queue[0]
+		g.queue = g.queue[1:]
+		if err := g.structFunc(name); err != nil {
+			return nil, err
+		}
+	}
+	g.buf.WriteString(strings.ReplaceAll(helpers, "$V", g.v))
+
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v", err)
+	}
+	return src, nil
+}
+
+const imports = `import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"mccoy.space/g/validate"
+)
+
+`
+
+// structType returns the declaration of the named struct type,
+// or nil if the package declares no such type.
+func (g *generator) structType(name string) *ast.StructType {
+	ts := g.types[name]
+	if ts == nil || ts.TypeParams != nil {
+		return nil
+	}
+	st, _ := ts.Type.(*ast.StructType)
+	return st
+}
+
+// tagged reports whether any field of st has a validate tag.
+func tagged(st *ast.StructType) bool {
+	for _, f := range st.Fields.List {
+		if tag(f) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// namesRule reports whether rules include one named name.
+func namesRule(rules []validate.Rule, name string) bool {
+	for _, r := range rules {
+		if r.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// tag returns the value of f's validate tag.
+func tag(f *ast.Field) string {
+	if f.Tag == nil {
+		return ""
+	}
+	s, err := strconv.Unquote(f.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(s).Get("validate")
+}
+
+// need returns the name of the function validating the named struct type,
+// queueing it to be generated.
+func (g *generator) need(name string) string {
+	if !g.queued[name] {
+		g.queued[name] = true
+		g.queue = append(g.queue, name)
+	}
+	return "validate" + export(name)
+}
+
+// export returns name with its first letter in upper case.
+func export(name string) string {
+	r, n := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(r)) + name[n:]
+}
+
+// fresh returns a new variable name starting with prefix.
+func (g *generator) fresh(prefix string) string {
+	g.vars++
+	return prefix + strconv.Itoa(g.vars)
+}
+
+// A target is a field, or an element of one, to which rules apply.
+type target struct {
+	expr string   // the value, as a Go expression
+	ptr  string   // a pointer to the value, or "" if it is not addressable
+	typ  ast.Expr // the value's type
+	path string   // the path to the field's struct, or to the element
+	name string   // the field's name, quoted, or "" for an element
+
+	msg       string
+	sensitive bool
+}
+
+// fieldPath returns a Go expression for the path to t.
+func (t target) fieldPath() string {
+	if t.name == "" {
+		return t.path
+	}
+	return fmt.Sprintf("append(%s[:len(%[1]s):len(%[1]s)], %s)", t.path, t.name)
+}
+
+func (g *generator) structFunc(name string) error {
+	fmt.Fprintf(&g.buf, "func %s(x *%s, path validate.Path) []error {\n\tvar errs []error\n", g.need(name), name)
+	for _, f := range g.structType(name).Fields.List {
+		tag := tag(f)
+		if tag == "" || tag == "-" {
+			continue
+		}
+		names := f.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{embeddedName(f.Type)}
+		}
+		for _, id := range names {
+			if id == nil || !id.IsExported() {
+				continue
+			}
+			rules, err := validate.ParseTag(tag)
+			if err != nil {
+				return fmt.Errorf("%s.%s: %v", name, id.Name, err)
+			}
+			t := target{
+				expr: "x." + id.Name,
+				ptr:  "&x." + id.Name,
+				typ:  f.Type,
+				path: "path",
+				name: strconv.Quote(g.fieldName(f, id.Name)),
+			}
+			for _, r := range rules {
+				if r.Name == "msg" {
+					t.msg = r.Param
+				}
+				t.sensitive = t.sensitive || r.Name == "sensitive"
+			}
+			if err := g.rules(t, rules); err != nil {
+				return fmt.Errorf("%s.%s: %v", name, id.Name, err)
+			}
+		}
+	}
+	g.buf.WriteString("\terrs = validategenSelf(errs, validategenField{path: path}, x)\n")
+	g.buf.WriteString("\treturn validategenStruct(errs, path, *x)\n}\n\n")
+	return nil
+}
+
+// embeddedName returns the name of the field embedding the type t.
+func embeddedName(t ast.Expr) *ast.Ident {
+	switch e := deref(t).(type) {
+	case *ast.Ident:
+		return e
+	case *ast.SelectorExpr:
+		return e.Sel
+	}
+	return nil
+}
+
+// fieldName returns the name of a field in errors, as Validate finds it
+// for the generator's name tags.
+func (g *generator) fieldName(f *ast.Field, name string) string {
+	if f.Tag == nil {
+		return name
+	}
+	s, _ := strconv.Unquote(f.Tag.Value)
+	for _, tag := range g.nameTags {
+		if tag == "" {
+			continue
+		}
+		if n := validate.TagName(reflect.StructTag(s).Get(tag)); n != "" {
+			return n
+		}
+	}
+	return name
+}
+
+// rules writes the code applying rules to t, in order.
+func (g *generator) rules(t target, rules []validate.Rule) error {
+	star, isPtr := t.typ.(*ast.StarExpr)
+	if isPtr {
+		if _, ok := star.X.(*ast.StarExpr); ok {
+			return fmt.Errorf("cannot validate pointers to pointers")
+		}
+	}
+
+	// f describes the target to validategenApply, with the value passed
+	// to validators. It is declared when first needed, so that values
+	// are not boxed for nothing.
+	f := ""
+	desc := func() string {
+		if f == "" {
+			f = g.fresh("f")
+			g.descriptor(f, t)
+		}
+		return f
+	}
+
+	var elems []func() error
+	closers := 0
+	for k := 0; k < len(rules); k++ {
+		r := rules[k]
+		if r.Or {
+			continue
+		}
+		n := 1
+		for k+n < len(rules) && rules[k+n].Or {
+			n++
+		}
+		alts := rules[k : k+n]
+		if n > 1 {
+			for _, a := range alts {
+				if validate.Reserved(a.Name) {
+					return fmt.Errorf("reserved rule %q cannot have alternatives", a.Name)
+				}
+			}
+		}
+
+		switch r.Name {
+		case "sensitive", "msg":
+		case "on", "required_if", "required_with", "required_without", "present":
+			return fmt.Errorf("rule %q is not supported", r.Name)
+		case "omitempty":
+			set, err := g.zero(t.expr, t.typ, false)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(&g.buf, "\tif %s {\n", set)
+			closers++
+		case "required":
+			zero, err := g.zero(t.expr, t.typ, true)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(&g.buf, "\tif %s {\n", zero)
+			g.check(t, r, "validategenErrRequired")
+			g.buf.WriteString("\t}\n")
+		case "struct":
+			id, ok := deref(t.typ).(*ast.Ident)
+			if !ok || g.structType(id.Name) == nil {
+				return fmt.Errorf("cannot validate %s as a struct declared in package %s", types.ExprString(t.typ), g.pkg)
+			}
+			ptr := t.ptr
+			if isPtr {
+				ptr = t.expr
+				fmt.Fprintf(&g.buf, "\tif %s != nil {\n", t.expr)
+			} else if ptr == "" {
+				v := g.fresh("s")
+				fmt.Fprintf(&g.buf, "\t{\n\t%s := %s\n", v, t.expr)
+				ptr = "&" + v
+			}
+			fmt.Fprintf(&g.buf, "\terrs = append(errs, %s(%s, %s)...)\n", g.need(id.Name), ptr, t.fieldPath())
+			if isPtr || t.ptr == "" {
+				g.buf.WriteString("\t}\n")
+			}
+		case "method":
+			if isPtr {
+				fmt.Fprintf(&g.buf, "\tif %s != nil {\n", t.expr)
+			}
+			fmt.Fprintf(&g.buf, "\tif err := %s.%s(); err != nil {\n", t.expr, r.Param)
+			g.check(t, r, "err")
+			g.buf.WriteString("\t}\n")
+			if isPtr {
+				g.buf.WriteString("\t}\n")
+			}
+		case "each", "keys", "values":
+			erules := rules[k+1:]
+			if r.Param != "" {
+				var err error
+				if erules, err = validate.ParseTag(r.Param); err != nil {
+					return err
+				}
+			}
+			elem, err := g.elem(t, r.Name, erules)
+			if err != nil {
+				return err
+			}
+			elems = append(elems, elem)
+			if r.Param == "" {
+				k = len(rules)
+			}
+		default:
+			lits := make([]string, len(alts))
+			for j, a := range alts {
+				lits[j] = ruleLit(a)
+			}
+			fmt.Fprintf(&g.buf, "\terrs = validategenApply(errs, %s, %s)\n", desc(), strings.Join(lits, ", "))
+		}
+		k += n - 1
+	}
+	// The elements are checked after the field itself, as by Validate.
+	// A field skipped by "omitempty" has no elements to check.
+	for _, elem := range elems {
+		if err := elem(); err != nil {
+			return err
+		}
+	}
+	// A field not validated as a struct may validate itself.
+	if t.name != "" && !namesRule(rules, "struct") {
+		ptr := t.ptr
+		if isPtr {
+			ptr = t.expr
+		}
+		fields := "path: " + t.path + ", name: " + t.name
+		if t.sensitive {
+			fields += ", sensitive: true"
+		}
+		fmt.Fprintf(&g.buf, "\terrs = validategenSelf(errs, validategenField{%s}, %s)\n", fields, ptr)
+	}
+	for ; closers > 0; closers-- {
+		g.buf.WriteString("\t}\n")
+	}
+	return nil
+}
+
+// descriptor declares f, a validategenField describing t, holding the
+// value passed to validators: the pointer's target, for pointers.
+func (g *generator) descriptor(f string, t target) {
+	fields := "path: " + t.path
+	if t.name != "" {
+		fields += ", name: " + t.name
+	}
+	_, isPtr := t.typ.(*ast.StarExpr)
+	switch {
+	case isPtr:
+		fields += ", ptr: " + t.expr
+	case t.ptr == "":
+		fields += ", value: " + t.expr
+	default:
+		fields += ", value: " + t.expr + ", ptr: " + t.ptr
+	}
+	fields += ", parent: x"
+	if t.msg != "" {
+		fields += fmt.Sprintf(", msg: %q", t.msg)
+	}
+	if t.sensitive {
+		fields += ", sensitive: true"
+	}
+	fmt.Fprintf(&g.buf, "\t%s := validategenField{%s}\n", f, fields)
+	if isPtr {
+		fmt.Fprintf(&g.buf, "\tif %s != nil {\n\t\t%s.value = *%[1]s\n\t} else {\n\t\t%[2]s.value, %[2]s.isNil = %[1]s, true\n\t}\n", t.expr, f)
+	}
+}
+
+// check writes the code reporting err, the error of the reserved rule r
+// for t, which has failed.
+func (g *generator) check(t target, r validate.Rule, err string) {
+	f := g.fresh("f")
+	g.descriptor(f, t)
+	fmt.Fprintf(&g.buf, "\terrs = %s.check(errs, %s, %s)\n", f, ruleLit(r), err)
+}
+
+// elem returns a function writing the code applying rules to the elements
+// of t, as selected by the reserved rule named by rule.
+func (g *generator) elem(t target, rule string, rules []validate.Rule) (func() error, error) {
+	typ := g.underlying(deref(t.typ))
+	var etyp ast.Expr
+	switch e := typ.(type) {
+	case *ast.ArrayType:
+		if rule != "each" {
+			return nil, fmt.Errorf("cannot check the %s of %s", rule, types.ExprString(t.typ))
+		}
+		etyp = e.Elt
+	case *ast.MapType:
+		if rule == "each" {
+			return nil, fmt.Errorf("cannot check each element of %s", types.ExprString(t.typ))
+		}
+		etyp = e.Value
+		if rule == "keys" {
+			etyp = e.Key
+		}
+	default:
+		return nil, fmt.Errorf("cannot check the elements of %s", types.ExprString(t.typ))
+	}
+
+	return func() error {
+		coll := t.expr
+		if _, ok := t.typ.(*ast.StarExpr); ok {
+			fmt.Fprintf(&g.buf, "\tif %s != nil {\n", t.expr)
+			coll = "(*" + t.expr + ")"
+		}
+		// The element's path extends the field's, without changing it.
+		fpath := fmt.Sprintf("append(%s[:len(%[1]s):len(%[1]s)]", t.path)
+		if t.name != "" {
+			fpath += ", " + t.name
+		}
+		p := g.fresh("p")
+		e := target{typ: etyp, msg: t.msg, sensitive: t.sensitive, path: p}
+		if rule == "each" {
+			i := g.fresh("i")
+			fmt.Fprintf(&g.buf, "\tfor %s := range %s {\n", i, coll)
+			fmt.Fprintf(&g.buf, "\t%s := %s, validategenIndex(%s))\n", p, fpath, i)
+			e.expr = fmt.Sprintf("%s[%s]", coll, i)
+			e.ptr = "&" + e.expr
+		} else {
+			key := g.fresh("k")
+			fmt.Fprintf(&g.buf, "\tfor _, %s := range validategenKeys(%s) {\n", key, coll)
+			fmt.Fprintf(&g.buf, "\t%s := %s, validategenKey(%s))\n", p, fpath, key)
+			e.expr = key
+			if rule == "values" {
+				e.expr = fmt.Sprintf("%s[%s]", coll, key)
+			}
+		}
+		if err := g.rules(e, rules); err != nil {
+			return err
+		}
+		g.buf.WriteString("\t}\n")
+		if _, ok := t.typ.(*ast.StarExpr); ok {
+			g.buf.WriteString("\t}\n")
+		}
+		return nil
+	}, nil
+}
+
+// underlying returns the type expression t, following the names of types
+// declared in the package to their definitions.
+func (g *generator) underlying(t ast.Expr) ast.Expr {
+	for i := 0; i < 100; i++ {
+		id, ok := t.(*ast.Ident)
+		if !ok || g.types[id.Name] == nil {
+			break
+		}
+		t = g.types[id.Name].Type
+	}
+	return t
+}
+
+// zero returns a Go expression reporting whether expr, of type t,
+// holds the zero value of its type, or, if !is, whether it does not.
+func (g *generator) zero(expr string, t ast.Expr, is bool) (string, error) {
+	op := map[bool]string{true: " == ", false: " != "}[is]
+	switch u := g.underlying(t).(type) {
+	case *ast.StarExpr, *ast.MapType, *ast.FuncType, *ast.ChanType, *ast.InterfaceType:
+		return expr + op + "nil", nil
+	case *ast.ArrayType:
+		if u.Len == nil {
+			return expr + op + "nil", nil
+		}
+	case *ast.Ident:
+		switch u.Name {
+		case "string":
+			return expr + op + `""`, nil
+		case "bool":
+			if is {
+				return "!" + expr, nil
+			}
+			return expr, nil
+		case "byte", "rune", "int", "int8", "int16", "int32", "int64",
+			"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+			"float32", "float64", "complex64", "complex128":
+			return expr + op + "0", nil
+		}
+	}
+	return "", fmt.Errorf("cannot tell whether %s holds its zero value", types.ExprString(t))
+}
+
+// ruleLit returns r as a Go composite literal.
+func ruleLit(r validate.Rule) string {
+	if r.Param == "" {
+		return fmt.Sprintf("validate.Rule{Name: %q}", r.Name)
+	}
+	return fmt.Sprintf("validate.Rule{Name: %q, Param: %q}", r.Name, r.Param)
+}
+
+// deref returns the type expression e, without pointers or parentheses.
+func deref(e ast.Expr) ast.Expr {
+	for {
+		switch x := e.(type) {
+		case *ast.StarExpr:
+			e = x.X
+		case *ast.ParenExpr:
+			e = x.X
+		default:
+			return e
+		}
+	}
+}
@@ -0,0 +1,309 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package main
+
+// helpers is written at the end of each generated file, with $V replaced
+// by the name of the package's validate.V. The errors it makes behave as
+// those made by Validate: they have the same messages, and wrap the same
+// errors.
+const helpers = `
+// validategenField is a field, or an element of one, checked by
+// the generated functions.
+type validategenField struct {
+	path   validate.Path
+	name   string
+	value  interface{}
+	ptr    interface{}
+	parent interface{}
+	isNil  bool
+
+	msg       string
+	sensitive bool
+}
+
+// fullPath returns the path to f.
+func (f validategenField) fullPath() validate.Path {
+	if f.name == "" {
+		return f.path
+	}
+	return append(f.path[:len(f.path):len(f.path)], f.name)
+}
+
+// fail appends the error reported for f's rule to errs.
+func (f validategenField) fail(errs []error, rule string, params []string, err error) []error {
+	path := f.fullPath()
+	value := f.value
+	if f.sensitive {
+		value = nil
+	}
+	return append(errs, validate.BadField{
+		Field:  validate.DotPath(path),
+		Err:    err,
+		Rule:   rule,
+		Params: params,
+		Value:  value,
+		Path:   path,
+	})
+}
+
+// redact returns err, the error of the rule named rule, or the error
+// reported in its place if f is sensitive.
+func (f validategenField) redact(rule string, err error) error {
+	if !f.sensitive {
+		return err
+	}
+	for _, k := range validategenKinds {
+		if errors.Is(err, k) {
+			return validategenRedacted{rule, k}
+		}
+	}
+	return validategenRedacted{rule, nil}
+}
+
+// check appends err, returned for f by the reserved rule r, to errs.
+func (f validategenField) check(errs []error, r validate.Rule, err error) []error {
+	err = f.redact(r.Name, err)
+	if f.msg != "" {
+		err = validategenMessage{f.msg, err}
+	}
+	return f.fail(errs, r.Name, r.Params(), err)
+}
+
+// validategenApply applies rules, one of which must pass, to f,
+// and appends the errors to report to errs.
+func validategenApply(errs []error, f validategenField, rules ...validate.Rule) []error {
+	var failed []error
+	passed := false
+	for _, r := range rules {
+		var err error
+		fn, extended := validategenLookup(r.Name)
+		switch {
+		case fn == nil:
+			errs = f.fail(errs, r.Name, r.Params(), f.redact(r.Name, fmt.Errorf("%w: %q", validate.ErrUndefinedValidator, r.Name)))
+			continue
+		case f.isNil:
+			passed = true
+			continue
+		case extended:
+			val := reflect.ValueOf(f.value)
+			if f.ptr != nil {
+				val = reflect.ValueOf(f.ptr).Elem()
+			}
+			err = validategenCall(r, fn, validate.Field{
+				Name:    validate.DotPath(f.fullPath()),
+				Value:   val,
+				Parent:  reflect.ValueOf(f.parent).Elem(),
+				Rule:    r,
+				Context: context.Background(),
+			})
+		case r.Param != "":
+			errs = f.fail(errs, r.Name, r.Params(), f.redact(r.Name, fmt.Errorf("validator %q does not take a parameter", r.Name)))
+			continue
+		default:
+			err = validategenCall(r, fn, f.value)
+		}
+		if errors.Is(err, validate.ErrValidatorPanicked) {
+			errs = f.fail(errs, r.Name, r.Params(), f.redact(r.Name, err))
+			continue
+		}
+		if err == nil {
+			passed = true
+			break
+		}
+		failed = append(failed, f.redact(r.Name, err))
+	}
+	if passed || len(failed) == 0 {
+		return errs
+	}
+
+	err, rule, params := failed[0], rules[0].Name, rules[0].Params()
+	if len(rules) > 1 {
+		names := make([]string, len(rules))
+		for i, r := range rules {
+			names[i] = r.Name
+		}
+		err, rule, params = validategenAlternatives(failed), strings.Join(names, "|"), nil
+	}
+	if f.msg != "" {
+		err = validategenMessage{f.msg, err}
+	}
+	return f.fail(errs, rule, params, err)
+}
+
+// validategenCall calls fn, reporting a panic as an error
+// wrapping validate.ErrValidatorPanicked.
+func validategenCall(r validate.Rule, fn func(interface{}) error, arg interface{}) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("%w: %q: %v", validate.ErrValidatorPanicked, r.Name, p)
+		}
+	}()
+	return fn(arg)
+}
+
+// validategenSelf calls the ValidateFields or Validate method of the value
+// ptr points to, through any further pointers, if it has either, and appends
+// the errors they report for f to errs.
+func validategenSelf(errs []error, f validategenField, ptr interface{}) (result []error) {
+	v := reflect.ValueOf(ptr)
+	for (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		return errs
+	}
+	pt := reflect.PointerTo(v.Type())
+	method := ""
+	switch {
+	case pt.Implements(validategenFieldsValidatable):
+		method = "ValidateFields"
+	case pt.Implements(validategenValidatable):
+		method = "Validate"
+	default:
+		return errs
+	}
+	f.value = v.Interface()
+	if !v.CanAddr() {
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		v = c
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			result = f.fail(errs, "", nil, f.redact(method, fmt.Errorf("%w: %v: %v", validate.ErrValidatorPanicked, v.Type(), p)))
+		}
+	}()
+	switch x := v.Addr().Interface().(type) {
+	case validate.FieldsValidatable:
+		for _, err := range x.ValidateFields() {
+			bf, ok := err.(validate.BadField)
+			if !ok {
+				errs = f.fail(errs, "", nil, f.redact(method, err))
+				continue
+			}
+			rel := bf.Path
+			if len(rel) == 0 && bf.Field != "" {
+				rel = validate.Path{bf.Field}
+			}
+			if f.sensitive {
+				rule := bf.Rule
+				if rule == "" {
+					rule = method
+				}
+				bf.Err, bf.Value = f.redact(rule, bf.Err), nil
+			}
+			path := f.fullPath()
+			bf.Path = append(path[:len(path):len(path)], rel...)
+			bf.Field = validate.DotPath(bf.Path)
+			errs = append(errs, bf)
+		}
+	case validate.Validatable:
+		if err := x.Validate(); err != nil {
+			errs = f.fail(errs, "", nil, f.redact(method, err))
+		}
+	}
+	return errs
+}
+
+var (
+	validategenValidatable       = reflect.TypeOf((*validate.Validatable)(nil)).Elem()
+	validategenFieldsValidatable = reflect.TypeOf((*validate.FieldsValidatable)(nil)).Elem()
+)
+
+// validategenStruct passes x, a struct at path, to the validator
+// registered for its type with RegisterStruct, if there is one, and
+// appends the error it reports to errs.
+func validategenStruct(errs []error, path validate.Path, x interface{}) (result []error) {
+	t := reflect.TypeOf(x)
+	fn := $V.StructValidator(t)
+	if fn == nil {
+		return errs
+	}
+	f := validategenField{path: path, value: x}
+	defer func() {
+		if p := recover(); p != nil {
+			result = f.fail(errs, "", nil, fmt.Errorf("%w: %v: %v", validate.ErrValidatorPanicked, t, p))
+		}
+	}()
+	if err := fn(x); err != nil {
+		errs = f.fail(errs, "", nil, err)
+	}
+	return errs
+}
+
+// validategenLookup returns the validator for the rule name, and whether
+// it is an extended validator, which takes a validate.Field.
+func validategenLookup(name string) (func(interface{}) error, bool) {
+	return $V.Lookup(name)
+}
+
+func validategenIndex(i int) string {
+	return "[" + strconv.Itoa(i) + "]"
+}
+
+func validategenKey(k interface{}) string {
+	return "[" + fmt.Sprint(k) + "]"
+}
+
+// validategenKeys returns the keys of m in the order of their formatted values.
+func validategenKeys[K comparable, E any](m map[K]E) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		return fmt.Sprint(keys[a]) < fmt.Sprint(keys[b])
+	})
+	return keys
+}
+
+// validategenErrRequired is reported for fields that fail the "required" rule.
+var validategenErrRequired error = validategenKind{validate.ErrRequired, "is required"}
+
+type validategenKind struct {
+	kind error
+	msg  string
+}
+
+func (e validategenKind) Error() string { return e.msg }
+func (e validategenKind) Unwrap() error { return e.kind }
+
+type validategenRedacted struct {
+	rule string
+	kind error
+}
+
+func (r validategenRedacted) Error() string {
+	return fmt.Sprintf("failed %q (details of sensitive field redacted)", r.rule)
+}
+
+func (r validategenRedacted) Unwrap() error { return r.kind }
+
+// validategenKinds are the errors a validategenRedacted may wrap.
+var validategenKinds = []error{
+	validate.ErrWrongType, validate.ErrNotUnique, validate.ErrRequired,
+	validate.ErrTooShort, validate.ErrTooLong, validate.ErrOutOfRange,
+	validate.ErrNotAllowed, validate.ErrBadFormat, validate.ErrUndefinedValidator,
+	validate.ErrValidatorPanicked, validate.ErrValidatorTimeout, validate.ErrMaxDepth,
+}
+
+type validategenMessage struct {
+	text string
+	err  error
+}
+
+func (m validategenMessage) Error() string { return m.text }
+func (m validategenMessage) Unwrap() error { return m.err }
+
+type validategenAlternatives []error
+
+func (e validategenAlternatives) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, ", or ")
+}
+
+func (e validategenAlternatives) Unwrap() []error { return e }
+`
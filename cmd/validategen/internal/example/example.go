@@ -0,0 +1,109 @@
+// © 2013 Steve McCoy under the MIT license.
+
+// Package example holds structs validated by functions generated by
+// validategen, to test that they behave as Validate does.
+package example
+
+import (
+	"errors"
+	"strings"
+
+	"mccoy.space/g/validate"
+)
+
+//go:generate go run mccoy.space/g/validate/cmd/validategen -name json
+
+var validators = func() validate.V {
+	v := validate.Builtin()
+	v["long"] = func(i interface{}) error {
+		if len(i.(string)) < 3 {
+			return errors.New("should be longer")
+		}
+		return nil
+	}
+	v["upper"] = func(i interface{}) error {
+		if s := i.(string); s != strings.ToUpper(s) {
+			return errors.New("should be upper case")
+		}
+		return nil
+	}
+	v["even"] = func(i interface{}) error {
+		if i.(int)%2 != 0 {
+			return errors.New("should be even")
+		}
+		return nil
+	}
+	v.RegisterParam("prefix", func(i interface{}, param string) error {
+		if !strings.HasPrefix(i.(string), param) {
+			return errors.New("should start with " + param)
+		}
+		return nil
+	})
+	v.RegisterStruct(Server{}, func(i interface{}) error {
+		if s := i.(Server); (s.Host == "") != (s.Port == 0) {
+			return errors.New("host and port should be set together")
+		}
+		return nil
+	})
+	return v
+}()
+
+type Server struct {
+	Host string `validate:"nonzero" json:"host"`
+	Port int    `validate:"even" json:"port"`
+}
+
+type Range struct{ Start, End int }
+
+func (r Range) Check() error {
+	if r.End < r.Start {
+		return errors.New("ends before it starts")
+	}
+	return nil
+}
+
+type Email string
+
+func (e Email) Validate() error {
+	if !strings.Contains(string(e), "@") {
+		return errors.New("should contain @")
+	}
+	return nil
+}
+
+type Window struct{ Open, Close int }
+
+func (w *Window) ValidateFields() []error {
+	var errs []error
+	if w.Close < w.Open {
+		errs = append(errs, validate.BadField{Field: "close", Err: errors.New("should not be before open"), Value: w.Close})
+	}
+	if w.Close-w.Open > 12 {
+		errs = append(errs, errors.New("should be at most 12 hours"))
+	}
+	return errs
+}
+
+type Config struct {
+	Name     string            `validate:"nonzero,long,msg=name must be at least 3 characters" json:"name"`
+	Token    string            `validate:"sensitive,long"`
+	Color    string            `validate:"upper|numeric"`
+	Nick     *string           `validate:"omitempty,long"`
+	Owner    *string           `validate:"required,long"`
+	Count    int               `validate:"even,nonzero"`
+	ID       string            `validate:"prefix=id-"`
+	Main     Server            `validate:"struct" json:"main"`
+	Backup   *Server           `validate:"struct"`
+	Servers  []Server          `validate:"nonempty,each,struct"`
+	Tags     []string          `validate:"each=long,nonempty"`
+	Labels   map[string]string `validate:"keys=upper,values=long"`
+	Range    Range             `validate:"method=Check"`
+	Missing  int               `validate:"undefined"`
+	Kind     string            `validate:"upper=x"`
+	Size     int               `validate:"long"`
+	Email    *Email            `validate:"nonzero"`
+	Hours    *Window           `validate:"omitempty"`
+	Ignored  string            `validate:"-"`
+	internal string            `validate:"long"`
+	Plain    string
+}
@@ -0,0 +1,77 @@
+package example
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"mccoy.space/g/validate"
+)
+
+func TestValidateConfig(t *testing.T) {
+	nick, owner, email := "ab", "someone", Email("someone")
+	tests := []Config{
+		{},
+		{
+			Name:    "name",
+			Token:   "secret",
+			Color:   "RED",
+			Nick:    &nick,
+			Owner:   &owner,
+			Count:   2,
+			ID:      "id-1",
+			Main:    Server{Host: "a", Port: 80},
+			Backup:  &Server{Port: 1},
+			Servers: []Server{{Host: "b", Port: 2}, {Port: 3}},
+			Tags:    []string{"ok!", "no"},
+			Labels:  map[string]string{"A": "abc", "b": "x"},
+			Range:   Range{2, 1},
+			Email:   &email,
+			Hours:   &Window{9, 8},
+		},
+		{
+			Name:  "ab",
+			Token: "s",
+			Color: "12",
+			Owner: &nick,
+			Count: 3,
+			ID:    "x",
+			Hours: &Window{1, 20},
+		},
+	}
+	for i, c := range tests {
+		got := ValidateConfig(c)
+		want := validators.ValidateAndTag(c, "json")
+		if len(got) != len(want) {
+			t.Fatalf("%d: got %d errors, wanted %d:\n%v\n%v", i, len(got), len(want), got, want)
+		}
+		for j := range got {
+			g, w := got[j].(validate.BadField), want[j].(validate.BadField)
+			if g.Error() != w.Error() || g.Rule != w.Rule || !reflect.DeepEqual(g.Params, w.Params) ||
+				!reflect.DeepEqual(g.Value, w.Value) || !reflect.DeepEqual(g.Path, w.Path) {
+				t.Errorf("%d: error %d is %#v, wanted %#v", i, j, g, w)
+			}
+			for _, kind := range []error{validate.ErrRequired, validate.ErrUndefinedValidator, validate.ErrBadFormat} {
+				if errors.Is(g, kind) != errors.Is(w, kind) {
+					t.Errorf("%d: error %d wraps %v differently: %v", i, j, kind, g)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkValidateConfig(b *testing.B) {
+	owner := "someone"
+	c := Config{Name: "name", Token: "secret", Color: "RED", Owner: &owner, Count: 2, ID: "id-1",
+		Main: Server{Host: "a", Port: 80}, Servers: []Server{{Host: "b", Port: 2}}, Tags: []string{"tag"}}
+	b.Run("generated", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ValidateConfig(c)
+		}
+	})
+	b.Run("reflection", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			validators.ValidateAndTag(c, "json")
+		}
+	})
+}
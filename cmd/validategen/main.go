@@ -0,0 +1,123 @@
+// © 2013 Steve McCoy under the MIT license.
+
+/*
+Command validategen generates functions validating structs without
+reflection, for programs that validate so many values that Validate's
+walk over their fields shows up in profiles.
+
+Usage:
+
+	validategen [-v validators] [-type T,...] [-name tag,...] [-o file] [dir]
+
+It reads the Go package in dir, or in the current directory, and writes
+a file to it, validate_gen.go by default, declaring for each struct type T
+whose fields have validate tags, or each named by -type,
+
+	func ValidateT(x T) []error
+
+ValidateT reports the same errors as validators.Validate(x), where
+validators is the package-level validate.V named by -v, but reads the
+fields of x directly and calls the validators named by their tags
+without looking the tags up. The validators themselves are still found
+in validators when each rule is checked, so they may be added and
+replaced as usual. Extended validators are passed Fields as usual,
+which are made with reflection.
+
+Fields are reported by their names in the struct tags named by -name,
+as by ValidateAndTag, or by their Go names.
+
+Some things Validate does cannot be known when the code is generated,
+and are not supported: rules scoped to groups with "on", the conditional
+forms of "required", "present", "struct" rules on fields whose types are not
+declared in the package, "omitempty" and "required" on fields whose zero
+values cannot be compared with ==, aliases, and wrappers such as
+Optional[T], which are passed to validators as they are. validategen
+reports the fields using them rather than generate code that behaves
+differently.
+
+The exit status is 0 when the file is written, and 2 otherwise.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+func run(args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("validategen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	vname := fs.String("v", "validators", "name of the package's validate.V")
+	typeList := fs.String("type", "", "comma-separated names of the types to validate (default: all with validate tags)")
+	nameList := fs.String("name", "", "comma-separated tags naming fields in errors")
+	out := fs.String("o", "validate_gen.go", "name of the file to write")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 1 {
+		fmt.Fprintln(stderr, "usage: validategen [-v validators] [-type T,...] [-name tag,...] [-o file] [dir]")
+		return 2
+	}
+	dir := "."
+	if fs.NArg() == 1 {
+		dir = fs.Arg(0)
+	}
+
+	pkg, files, err := load(dir, *out)
+	if err != nil {
+		fmt.Fprintln(stderr, "validategen:", err)
+		return 2
+	}
+	g := newGenerator(pkg, *vname, split(*nameList), files)
+	src, err := g.generate(split(*typeList))
+	if err != nil {
+		fmt.Fprintln(stderr, "validategen:", err)
+		return 2
+	}
+	if err := os.WriteFile(filepath.Join(dir, *out), src, 0o644); err != nil {
+		fmt.Fprintln(stderr, "validategen:", err)
+		return 2
+	}
+	return 0
+}
+
+// load parses the Go files of the package in dir, except for out,
+// returning the package's name.
+func load(dir, out string) (string, []*ast.File, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return "", nil, err
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range bp.GoFiles {
+		if name == out {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return "", nil, err
+		}
+		files = append(files, f)
+	}
+	return bp.Name, files, nil
+}
+
+func split(list string) []string {
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// TestRun_example checks that the generated code in internal/example,
+// whose behavior is tested there, is up to date.
+func TestRun_example(t *testing.T) {
+	dir := t.TempDir()
+	src, err := os.ReadFile("internal/example/example.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, "example.go", string(src))
+
+	var errOut bytes.Buffer
+	if code := run([]string{"-name", "json", dir}, &errOut); code != 0 {
+		t.Fatalf("wrong exit code %d: %s", code, errOut.String())
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "validate_gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile("internal/example/validate_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatal("internal/example/validate_gen.go is out of date; run go generate ./...")
+	}
+}
+
+func TestRun_types(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "x.go", `package x
+
+type A struct {
+	B B `+"`validate:\"struct\"`"+`
+}
+
+type B struct {
+	N int
+}
+
+type C struct {
+	S string `+"`validate:\"long\"`"+`
+}
+`)
+
+	var errOut bytes.Buffer
+	if code := run([]string{"-type", "A", "-v", "vd", "-o", "a_gen.go", dir}, &errOut); code != 0 {
+		t.Fatalf("wrong exit code %d: %s", code, errOut.String())
+	}
+	src, err := os.ReadFile(filepath.Join(dir, "a_gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(src)
+	for _, want := range []string{"func ValidateA(x A) []error", "func validateB(x *B, path validate.Path) []error", "vd.Lookup(name)"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("generated code lacks %q:\n%s", want, s)
+		}
+	}
+	if strings.Contains(s, "ValidateB(") || strings.Contains(s, "validateC") {
+		t.Errorf("generated code for types not asked for:\n%s", s)
+	}
+
+	// The generated file is not read again.
+	if code := run([]string{"-o", "a_gen.go", dir}, &errOut); code != 0 {
+		t.Fatalf("wrong exit code %d regenerating: %s", code, errOut.String())
+	}
+}
+
+func TestRun_unsupported(t *testing.T) {
+	tests := []struct {
+		field, want string
+	}{
+		{"A int `validate:\"on=create,long\"`", `X.A: rule "on" is not supported`},
+		{"A int `validate:\"required_with=B\"`", `X.A: rule "required_with" is not supported`},
+		{"A Y `validate:\"omitempty,long\"`", "X.A: cannot tell whether Y holds its zero value"},
+		{"A [2]int `validate:\"required\"`", "X.A: cannot tell whether [2]int holds its zero value"},
+		{"A int `validate:\"struct\"`", "X.A: cannot validate int as a struct declared in package x"},
+		{"A time.Time `validate:\"struct\"`", "X.A: cannot validate time.Time as a struct declared in package x"},
+		{"A **Y `validate:\"long\"`", "X.A: cannot validate pointers to pointers"},
+		{"A []int `validate:\"keys=long\"`", "X.A: cannot check the keys of []int"},
+		{"A int `validate:\"each,long\"`", "X.A: cannot check the elements of int"},
+		{"A int `validate:\"a,,b\"`", `X.A: empty rule at position 2 in tag "a,,b"`},
+		{"A int `validate:\"a|struct\"`", `X.A: reserved rule "struct" cannot have alternatives`},
+	}
+	for _, test := range tests {
+		dir := t.TempDir()
+		writeFile(t, dir, "x.go", "package x\n\nimport \"time\"\n\nvar _ time.Time\n\ntype Y struct{}\n\ntype X struct {\n\t"+test.field+"\n}\n")
+		var errOut bytes.Buffer
+		if code := run([]string{dir}, &errOut); code != 2 {
+			t.Errorf("%s: wrong exit code %d", test.field, code)
+		}
+		if got := strings.TrimSpace(errOut.String()); got != "validategen: "+test.want {
+			t.Errorf("%s: wrong error %q, wanted %q", test.field, got, test.want)
+		}
+	}
+}
+
+func TestRun_noTypes(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "x.go", "package x\n\ntype X struct{ A int }\n")
+
+	var errOut bytes.Buffer
+	if code := run([]string{dir}, &errOut); code != 2 || !strings.Contains(errOut.String(), "package x has no structs with validate tags") {
+		t.Errorf("wrong result %d: %s", code, errOut.String())
+	}
+	errOut.Reset()
+	if code := run([]string{"-type", "Z", dir}, &errOut); code != 2 || !strings.Contains(errOut.String(), "package x has no struct type Z") {
+		t.Errorf("wrong result %d: %s", code, errOut.String())
+	}
+}
@@ -0,0 +1,160 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Comparer reports whether two values of the same type are equal.
+type Comparer func(a, b interface{}) bool
+
+// Comparers maps types to the Comparer used for their values by the
+// validators that compare values, such as "unique".
+type Comparers map[reflect.Type]Comparer
+
+// Equal reports whether a and b are equal. If c has a Comparer for the type
+// of a, it decides. Otherwise, values of the same comparable type are
+// compared with ==, and all others with reflect.DeepEqual.
+func (c Comparers) Equal(a, b interface{}) bool {
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	if ta != tb {
+		return false
+	}
+	if f := c[ta]; f != nil {
+		return f(a, b)
+	}
+	if ta != nil && ta.Comparable() {
+		return a == b
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// FoldStrings is a Comparer for strings that ignores case,
+// using Unicode case folding.
+func FoldStrings(a, b interface{}) bool {
+	return strings.EqualFold(a.(string), b.(string))
+}
+
+// Epsilon returns a Comparer for float64s that considers values equal
+// when they differ by no more than e.
+func Epsilon(e float64) Comparer {
+	return func(a, b interface{}) bool {
+		return math.Abs(a.(float64)-b.(float64)) <= e
+	}
+}
+
+// SetComparers adds the validators that compare values to v,
+// replacing any already present, and configures them to use c.
+// The validators are:
+//
+//	unique       no two elements of a slice, array, or map are equal
+//	eq=x         the value is equal to x
+//	oneof=a b …  the value is equal to one of a, b, …
+//
+// The parameters of "eq" and "oneof" are parsed as values of the field's
+// type, which must be a string, boolean, or number type; time.Durations
+// may also be given as durations, as in "eq=1m30s". Values given to
+// "oneof" that cannot be parsed so are never equal, and Check reports
+// them. A nil c compares every type in the default manner described
+// for Equal.
+func (v V) SetComparers(c Comparers) {
+	v.RegisterParam("eq", func(i interface{}, param string) error {
+		x, err := parseValue(reflect.TypeOf(i), param)
+		if err != nil {
+			return err
+		}
+		if !c.Equal(i, x) {
+			return errorf(ErrNotAllowed, "%v is not equal to %v", i, param)
+		}
+		return nil
+	})
+	v.RegisterParamCheck("eq", eqParam)
+	v.RegisterParam("oneof", func(i interface{}, param string) error {
+		return oneOf(c, i, param)
+	})
+	v.RegisterParamCheck("oneof", oneOfParam)
+
+	v["unique"] = func(i interface{}) error {
+		val := reflect.ValueOf(i)
+		switch val.Kind() {
+		case reflect.Slice, reflect.Array:
+		case reflect.Map:
+			keys := val.MapKeys()
+			for j := range keys {
+				for k := 0; k < j; k++ {
+					a, b := val.MapIndex(keys[k]).Interface(), val.MapIndex(keys[j]).Interface()
+					if c.Equal(a, b) {
+						return errorf(ErrNotUnique, "values for %v and %v are equal", keys[k], keys[j])
+					}
+				}
+			}
+			return nil
+		default:
+			return errorf(ErrWrongType, "cannot check uniqueness of %T", i)
+		}
+
+		for j := 0; j < val.Len(); j++ {
+			for k := 0; k < j; k++ {
+				if c.Equal(val.Index(k).Interface(), val.Index(j).Interface()) {
+					return errorf(ErrNotUnique, "element %d is equal to element %d", j, k)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// eqParam is the parameter check of "eq".
+func eqParam(t reflect.Type, param string) error {
+	if t == nil {
+		return nil
+	}
+	_, err := parseValue(t, param)
+	return err
+}
+
+// parseValue parses s as a value of type t, for the validators comparing
+// values with their parameters. Durations are parsed as integers,
+// or failing that, by time.ParseDuration.
+func parseValue(t reflect.Type, s string) (interface{}, error) {
+	if t == nil {
+		return nil, errorf(ErrWrongType, "nil is not a string, boolean, or number")
+	}
+	x := reflect.New(t).Elem()
+	var err error
+	switch t.Kind() {
+	case reflect.String:
+		x.SetString(s)
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(s)
+		x.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		n, err = strconv.ParseInt(s, 10, t.Bits())
+		if d, derr := time.ParseDuration(s); err != nil && t == durationType && derr == nil {
+			n, err = int64(d), nil
+		}
+		x.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var n uint64
+		n, err = strconv.ParseUint(s, 10, t.Bits())
+		x.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(s, t.Bits())
+		x.SetFloat(f)
+	default:
+		return nil, errorf(ErrWrongType, "%v is not a string, boolean, or number", t)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parameter %q is not of type %v", s, t)
+	}
+	return x.Interface(), nil
+}
@@ -0,0 +1,100 @@
+package validate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComparers_Equal(t *testing.T) {
+	c := Comparers{
+		reflect.TypeOf(""):         FoldStrings,
+		reflect.TypeOf(float64(0)): Epsilon(0.01),
+	}
+
+	tests := []struct {
+		a, b  interface{}
+		equal bool
+	}{
+		{"Hello", "hELLO", true},
+		{"Hello", "Hallo", false},
+		{1.0, 1.005, true},
+		{1.0, 1.5, false},
+		{1, 1, true},
+		{1, int64(1), false},
+		{[]int{1, 2}, []int{1, 2}, true},
+		{nil, nil, true},
+	}
+
+	for _, test := range tests {
+		if eq := c.Equal(test.a, test.b); eq != test.equal {
+			t.Fatalf("Equal(%#v, %#v) = %v, wanted %v", test.a, test.b, eq, test.equal)
+		}
+	}
+}
+
+func TestV_SetComparers(t *testing.T) {
+	type X struct {
+		A []string          `validate:"unique"`
+		B map[string]string `validate:"unique"`
+	}
+
+	vd := make(V)
+	vd.SetComparers(nil)
+
+	if errs := vd.Validate(X{A: []string{"a", "A"}}); errs != nil {
+		t.Fatalf("unexpected errors with default comparison: %v", errs)
+	}
+
+	vd.SetComparers(Comparers{reflect.TypeOf(""): FoldStrings})
+
+	errs := vd.Validate(X{
+		A: []string{"a", "b", "A"},
+		B: map[string]string{"x": "Same", "y": "same"},
+	})
+	if len(errs) != 2 {
+		t.Fatalf("wrong number of errors with folded comparison: %v", errs)
+	}
+	if errs[0].Error() != "field A is invalid: element 2 is equal to element 0" {
+		t.Fatalf("wrong error for slice: %v", errs[0])
+	}
+}
+
+func TestV_SetComparers_eq(t *testing.T) {
+	type X struct {
+		Lang  string        `validate:"eq=Go"`
+		Color string        `validate:"oneof=Red green"`
+		Sum   float64       `validate:"eq=0.3"`
+		On    bool          `validate:"eq=true"`
+		Wait  time.Duration `validate:"oneof=1m 90s"`
+	}
+	a, b := 0.1, 0.2
+	x := X{Lang: "go", Color: "GREEN", Sum: a + b, On: true, Wait: 90 * time.Second}
+
+	vd := make(V)
+	vd.SetComparers(nil)
+	var got []string
+	for _, err := range vd.Validate(x) {
+		got = append(got, err.Error())
+	}
+	want := []string{
+		"field Lang is invalid: go is not equal to Go",
+		"field Color is invalid: GREEN is not one of [Red green]",
+		"field Sum is invalid: 0.30000000000000004 is not equal to 0.3",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("wrong errors with default comparison:\n%s\nwanted:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	vd.SetComparers(Comparers{
+		reflect.TypeOf(""):         FoldStrings,
+		reflect.TypeOf(float64(0)): Epsilon(1e-9),
+	})
+	if errs := vd.Validate(x); errs != nil {
+		t.Fatalf("unexpected errors with folded comparison: %v", errs)
+	}
+	if errs := vd.Validate(X{Lang: "Go", Color: "red", Sum: 0.3, On: false, Wait: time.Second}); len(errs) != 2 {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+}
@@ -0,0 +1,87 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// A Schema validates values of a single struct type, whose tags were
+// parsed by Compile. Validate parses the tags of each type only once too,
+// when it is first validated, but a Schema reports malformed tags before
+// any value is validated, and lets hot paths, such as validating the body
+// of every request, skip looking the type up.
+// A Schema may be used by multiple goroutines at once.
+type Schema struct {
+	v      V
+	t      reflect.Type
+	fields map[reflect.Type][]fieldInfo
+}
+
+// Compile returns a Schema validating values of the struct type t, or of
+// pointers to it, with v. The types reached from t through "struct" rules
+// are compiled too; the types of values held by interface fields are
+// parsed when they are found.
+//
+// Compile returns an error if t is not a struct type or a pointer to one,
+// or if one of the tags it reaches cannot be parsed.
+func (v V) Compile(t reflect.Type) (*Schema, error) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot compile validation of %v", t)
+	}
+
+	s := &Schema{v: v, t: t, fields: make(map[reflect.Type][]fieldInfo)}
+	if err := s.compile(t); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Schema) compile(t reflect.Type) error {
+	if _, ok := s.fields[t]; ok {
+		return nil
+	}
+	fs := parseType(t, "validate", false)
+	s.fields[t] = fs
+	for _, fi := range fs {
+		if fi.err != nil {
+			return fmt.Errorf("%v.%s: %v", t, fi.field.Name, fi.err)
+		}
+		for _, r := range fi.rules {
+			if r.Name != "struct" {
+				continue
+			}
+			if ft := structType(fi.field.Type); ft != nil {
+				if err := s.compile(ft); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Validate behaves like V.ValidateOpts for x, which must be of the
+// Schema's type or a pointer to it. The TagKey and PlaygroundTags options
+// make it parse the tags of x as Validate would, rather than use the
+// Schema's. For values of other types,
+// it returns a single error wrapping ErrWrongType.
+func (s *Schema) Validate(x interface{}, opts ...Option) []error {
+	val := reflect.ValueOf(x)
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+	if !val.IsValid() || val.Type() != s.t {
+		return []error{errorf(ErrWrongType, "schema for %v cannot validate %T", s.t, x)}
+	}
+
+	w := walker{v: s.v, schema: s.fields}
+	for _, o := range opts {
+		o(&w)
+	}
+	return w.validate(val, nil)
+}
@@ -0,0 +1,64 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestV_Compile(t *testing.T) {
+	type Y struct {
+		A int `validate:"odd"`
+	}
+	type X struct {
+		Ys []Y         `validate:"each,struct"`
+		B  int         `validate:"odd"`
+		I  interface{} `validate:"struct"`
+	}
+
+	vd := V{
+		"odd": func(i interface{}) error {
+			if i.(int)%2 == 0 {
+				return fmt.Errorf("%d should be odd", i)
+			}
+			return nil
+		},
+	}
+
+	s, err := vd.Compile(reflect.TypeOf(&X{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.fields) != 2 {
+		t.Fatalf("wrong types compiled: %v", s.fields)
+	}
+
+	x := X{Ys: []Y{{1}, {2}}, B: 2, I: Y{4}}
+	got := s.Validate(&x)
+	if want := vd.Validate(x); fmt.Sprint(got) != fmt.Sprint(want) || len(got) != 3 {
+		t.Fatalf("schema found %v, Validate found %v", got, want)
+	}
+	if errs := s.Validate(x, Rules(map[string]string{"B": "odd"})); len(errs) != 4 {
+		t.Fatalf("wrong errors with options: %v", errs)
+	}
+	if errs := s.Validate(Y{}); len(errs) != 1 || !errors.Is(errs[0], ErrWrongType) {
+		t.Fatalf("wrong errors for a value of another type: %v", errs)
+	}
+}
+
+func TestV_Compile_errors(t *testing.T) {
+	type Y struct {
+		A int `validate:"odd,,odd"`
+	}
+	type X struct {
+		Y *Y `validate:"struct"`
+	}
+
+	if _, err := make(V).Compile(reflect.TypeOf(X{})); err == nil {
+		t.Fatal("no error for a malformed tag")
+	}
+	if _, err := make(V).Compile(reflect.TypeOf(1)); err == nil {
+		t.Fatal("no error for a non-struct type")
+	}
+}
@@ -0,0 +1,174 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+)
+
+// Coverage records the validators called by every V while it is active.
+// It is meant for tests, to find validators and tagged fields that
+// are declared but never exercised:
+//
+//	func TestMain(m *testing.M) {
+//		cov := validate.StartCoverage()
+//		code := m.Run()
+//		validate.StopCoverage()
+//		cov.WriteReport(os.Stderr, vd, X{}, Y{})
+//		os.Exit(code)
+//	}
+type Coverage struct {
+	mu         sync.Mutex
+	validators map[string]CoverageCount
+	fields     map[FieldRule]CoverageCount
+}
+
+// CoverageCount is the number of times a validator was called,
+// and how many of those calls reported an error.
+type CoverageCount struct {
+	Calls    int
+	Failures int
+}
+
+// FieldRule identifies a rule in the validate tag of a struct field.
+type FieldRule struct {
+	Type  reflect.Type // the struct type declaring the field
+	Field string       // the Go name of the field
+	Rule  string       // the name of the rule
+}
+
+func (f FieldRule) String() string {
+	return fmt.Sprintf("%v.%s %s", f.Type, f.Field, f.Rule)
+}
+
+var coverage atomic.Pointer[Coverage]
+
+// StartCoverage begins recording coverage in a new Coverage,
+// replacing any that is already active, and returns it.
+func StartCoverage() *Coverage {
+	c := &Coverage{
+		validators: make(map[string]CoverageCount),
+		fields:     make(map[FieldRule]CoverageCount),
+	}
+	coverage.Store(c)
+	return c
+}
+
+// StopCoverage stops recording coverage.
+func StopCoverage() {
+	coverage.Store(nil)
+}
+
+func (c *Coverage) record(fr FieldRule, failed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	vc := c.validators[fr.Rule]
+	fc := c.fields[fr]
+	vc.Calls++
+	fc.Calls++
+	if failed {
+		vc.Failures++
+		fc.Failures++
+	}
+	c.validators[fr.Rule] = vc
+	c.fields[fr] = fc
+}
+
+// Validators returns the coverage of every validator in v.
+// Validators that were never called have a zero CoverageCount.
+func (c *Coverage) Validators(v V) map[string]CoverageCount {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v = v.flatten()
+	m := make(map[string]CoverageCount, len(v))
+	for name := range v {
+		name = strings.TrimSuffix(name, "=")
+		if name != "" && name != metaKey {
+			m[name] = c.validators[name]
+		}
+	}
+	return m
+}
+
+// Fields returns the coverage of every rule in the validate tags of the
+// types of samples, which should be structs or pointers to structs.
+// Types reached through "struct" tags are included.
+// Rules that were never called have a zero CoverageCount.
+func (c *Coverage) Fields(samples ...interface{}) map[FieldRule]CoverageCount {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	m := make(map[FieldRule]CoverageCount)
+	seen := make(map[reflect.Type]bool)
+	for _, s := range samples {
+		c.collect(reflect.TypeOf(s), m, seen)
+	}
+	return m
+}
+
+func (c *Coverage) collect(t reflect.Type, m map[FieldRule]CoverageCount, seen map[reflect.Type]bool) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || seen[t] {
+		return
+	}
+	seen[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		rules, _ := ParseTag(f.Tag.Get("validate"))
+		for _, r := range rules {
+			if r.Name == "struct" {
+				c.collect(structType(f.Type), m, seen)
+			}
+			if Reserved(r.Name) {
+				continue
+			}
+			fr := FieldRule{t, f.Name, r.Name}
+			m[fr] = c.fields[fr]
+		}
+	}
+}
+
+// WriteReport writes a table of the coverage of the validators in v
+// and the rules declared by the types of samples, as described for
+// Validators and Fields.
+func (c *Coverage) WriteReport(w io.Writer, v V, samples ...interface{}) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "VALIDATOR\tCALLS\tFAILURES")
+	vs := c.Validators(v)
+	names := make([]string, 0, len(vs))
+	for name := range vs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(tw, "%s\t%d\t%d\n", name, vs[name].Calls, vs[name].Failures)
+	}
+
+	fmt.Fprintln(tw, "\nFIELD\tCALLS\tFAILURES")
+	fs := c.Fields(samples...)
+	frs := make([]FieldRule, 0, len(fs))
+	for fr := range fs {
+		frs = append(frs, fr)
+	}
+	sort.Slice(frs, func(i, j int) bool {
+		return frs[i].String() < frs[j].String()
+	})
+	for _, fr := range frs {
+		fmt.Fprintf(tw, "%v\t%d\t%d\n", fr, fs[fr].Calls, fs[fr].Failures)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,63 @@
+package validate
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestCoverage(t *testing.T) {
+	type X struct {
+		A int `validate:"odd"`
+		B int `validate:"even"`
+	}
+	type Y struct {
+		X X   `validate:"struct"`
+		C int `validate:"odd"`
+	}
+
+	vd := make(V)
+	vd["odd"] = func(i interface{}) error {
+		if i.(int)&1 == 0 {
+			return fmt.Errorf("not odd")
+		}
+		return nil
+	}
+	vd["even"] = func(i interface{}) error {
+		return nil
+	}
+	vd["unused"] = func(i interface{}) error {
+		return nil
+	}
+
+	vd.Validate(X{}) // not recorded
+	cov := StartCoverage()
+	vd.Validate(Y{X: X{A: 1}})
+	vd.Validate(Y{C: 1})
+	StopCoverage()
+	vd.Validate(X{}) // not recorded
+
+	vs := cov.Validators(vd)
+	if vs["odd"] != (CoverageCount{4, 2}) || vs["even"] != (CoverageCount{2, 0}) || vs["unused"] != (CoverageCount{}) {
+		t.Fatalf("wrong validator coverage: %v", vs)
+	}
+
+	xt := reflect.TypeOf(X{})
+	fs := cov.Fields(Y{})
+	if len(fs) != 3 {
+		t.Fatalf("wrong number of field rules: %v", fs)
+	}
+	if fs[FieldRule{xt, "A", "odd"}] != (CoverageCount{2, 1}) {
+		t.Fatalf("wrong field coverage: %v", fs)
+	}
+
+	var b bytes.Buffer
+	if err := cov.WriteReport(&b, vd, Y{}); err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`unused +0 +0`).MatchString(b.String()) {
+		t.Fatalf("report is missing unused validator:\n%s", b.String())
+	}
+}
@@ -0,0 +1,107 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// dateFormat is the "dateformat" builtin, which reports strings that
+// time.Parse cannot parse with the layout given as its parameter.
+func dateFormat(i interface{}, layout string) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a string", i)
+	}
+	if _, err := time.Parse(layout, s); err != nil {
+		return errorf(ErrBadFormat, "%q is not in the format %q", s, layout)
+	}
+	return nil
+}
+
+func before(i interface{}, param string) error {
+	t, limit, err := times(i, param)
+	if err == nil && !t.Before(limit) {
+		err = errorf(ErrOutOfRange, "%v is not before %v", t.Format(time.RFC3339), param)
+	}
+	return err
+}
+
+func after(i interface{}, param string) error {
+	t, limit, err := times(i, param)
+	if err == nil && !t.After(limit) {
+		err = errorf(ErrOutOfRange, "%v is not after %v", t.Format(time.RFC3339), param)
+	}
+	return err
+}
+
+// times returns the time in i, a time.Time or a string holding an RFC 3339
+// time or a date, and the time given by param, as parsed by timeLimit.
+func times(i interface{}, param string) (t, limit time.Time, err error) {
+	switch x := i.(type) {
+	case time.Time:
+		t = x
+	default:
+		s, ok := asString(i)
+		if !ok {
+			return t, limit, errorf(ErrWrongType, "%T is not a time", i)
+		}
+		if t, ok = parseTime(s); !ok {
+			return t, limit, errorf(ErrBadFormat, "%q is not a time or a date", s)
+		}
+	}
+	limit, err = timeLimit(param)
+	return t, limit, err
+}
+
+// timeLimit returns the time given by param: "now", optionally followed
+// by a signed duration, as in "now-24h", or an RFC 3339 time or a date.
+// Dates are at midnight UTC.
+func timeLimit(param string) (time.Time, error) {
+	if rest, ok := strings.CutPrefix(param, "now"); ok {
+		limit := time.Now()
+		if rest == "" {
+			return limit, nil
+		}
+		d, err := time.ParseDuration(rest)
+		if err != nil || rest[0] != '+' && rest[0] != '-' {
+			return limit, fmt.Errorf("parameter %q is not a time, as in \"now-24h\"", param)
+		}
+		return limit.Add(d), nil
+	}
+	limit, ok := parseTime(param)
+	if !ok {
+		return limit, fmt.Errorf("parameter %q is not a time, as in \"2006-01-02\"", param)
+	}
+	return limit, nil
+}
+
+// timeParam is the parameter check of "before" and "after".
+func timeParam(t reflect.Type, param string) error {
+	if t != nil && t != timeType && t.Kind() != reflect.String {
+		return errorf(ErrWrongType, "%v is not a time", t)
+	}
+	_, err := timeLimit(param)
+	return err
+}
+
+// dateFormatParam is the parameter check of "dateformat", which reports
+// layouts holding none of time.Parse's elements.
+func dateFormatParam(t reflect.Type, layout string) error {
+	if when := time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC); when.Format(layout) == layout {
+		return fmt.Errorf("parameter %q is not a time layout, as in \"2006-01-02\"", layout)
+	}
+	return stringType(t)
+}
+
+// parseTime parses s as an RFC 3339 time or a date.
+func parseTime(s string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, true
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	return t, err == nil
+}
@@ -0,0 +1,70 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTimes(t *testing.T) {
+	jan1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value  interface{}
+		param  string
+		before bool
+	}{
+		{jan1, "2024-01-02", true},
+		{jan1, "2024-01-01", false},
+		{jan1.In(time.FixedZone("east", 2*60*60)), "2024-01-01T01:00:00+02:00", false},
+		{"2024-01-01T00:00:00+02:00", "2024-01-01", true},
+		{"2024-01-01T00:00:00-02:00", "2024-01-01", false},
+		{testName("2023-12-31"), "2024-01-01T00:00:00Z", true},
+		{time.Now().Add(-time.Hour), "now", true},
+		{time.Now().Add(-time.Hour), "now-2h", false},
+		{time.Now().Add(time.Hour), "now+90m", true},
+	}
+	for _, test := range tests {
+		if err := before(test.value, test.param); (err == nil) != test.before {
+			t.Errorf("before(%v, %q): %v", test.value, test.param, err)
+		}
+		if err := after(test.value, test.param); err == nil && test.before {
+			t.Errorf("after(%v, %q) passed", test.value, test.param)
+		}
+	}
+
+	if err := before(1, "now"); !errors.Is(err, ErrWrongType) {
+		t.Error("wrong error for an int:", err)
+	}
+	if err := before("tomorrow", "now"); !errors.Is(err, ErrBadFormat) {
+		t.Error("wrong error for a string that is not a time:", err)
+	}
+	for _, param := range []string{"", "today", "now24h", "now-soon", "01/02/2024"} {
+		if err := before(jan1, param); err == nil || errors.Is(err, ErrOutOfRange) {
+			t.Errorf("wrong error for parameter %q: %v", param, err)
+		}
+	}
+}
+
+func TestV_Validate_dates(t *testing.T) {
+	type X struct {
+		Born    *time.Time `validate:"after=1900-01-01,before=now"`
+		Expires string     `validate:"dateformat=2006-01-02,after=now"`
+		Stamp   string     `validate:"dateformat:Mon, 02 Jan 2006"`
+	}
+
+	born := time.Date(1850, 6, 1, 0, 0, 0, 0, time.UTC)
+	errs := Builtin().Validate(X{Born: &born, Expires: "2020-01-01", Stamp: "Tues, 02 Jan 2024"})
+	want := []string{
+		"field Born is invalid: 1850-06-01T00:00:00Z is not after 1900-01-01",
+		"field Expires is invalid: 2020-01-01T00:00:00Z is not after now",
+		`field Stamp is invalid: "Tues, 02 Jan 2024" is not in the format "Mon, 02 Jan 2006"`,
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+	for i, err := range errs {
+		if err.Error() != want[i] {
+			t.Errorf("error %d is %q, wanted %q", i, err, want[i])
+		}
+	}
+}
@@ -0,0 +1,26 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+// Default holds the validators used by the package-level Validate and
+// ValidateOpts, which begin as those of Builtin. Libraries can add their
+// validators to it from their init functions,
+//
+//	func init() {
+//		validate.Default.Register("sku", checkSKU)
+//	}
+//
+// so that applications need not pass a V through every layer to use them.
+// Programs that need isolation, such as tests, or tenants with their own
+// rules, should use a V or Registry of their own.
+var Default = NewRegistry(Builtin())
+
+// Validate behaves like V.Validate, with the validators in Default.
+func Validate(s interface{}) []error {
+	return Default.Validate(s)
+}
+
+// ValidateOpts behaves like V.ValidateOpts, with the validators in Default.
+func ValidateOpts(s interface{}, opts ...Option) []error {
+	return Default.ValidateOpts(s, opts...)
+}
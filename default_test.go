@@ -0,0 +1,25 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	type X struct {
+		A string `validate:"nonzero,defaulttest"`
+	}
+
+	defer func(r *Registry) { Default = r }(Default)
+	Default = NewRegistry(Builtin())
+
+	errs := Validate(X{"a"})
+	if len(errs) != 1 || !errors.Is(errs[0], ErrUndefinedValidator) {
+		t.Fatalf("wrong errors before registering: %v", errs)
+	}
+	Default.Register("defaulttest", func(interface{}) error { return errors.New("bad") })
+	errs = ValidateOpts(X{}, NameTags("json"))
+	if len(errs) != 2 || errs[0].(BadField).Rule != "nonzero" || errs[1].Error() != "field A is invalid: bad" {
+		t.Fatalf("wrong errors after registering: %v", errs)
+	}
+}
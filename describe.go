@@ -0,0 +1,125 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// A RuleDescription describes one rule that Validate applies to a field,
+// as reported by Describe.
+type RuleDescription struct {
+	// Path is the path to the field, by the names it would have in
+	// a BadField. The elements of slices, arrays, and maps whose structs
+	// are reached through "each" or "values" appear as "[]".
+	Path Path
+
+	// Type is the type of the field.
+	Type reflect.Type
+
+	// Rule is the rule, with its parameter, which Rule.Params splits.
+	Rule Rule
+
+	// Defined reports whether the rule is reserved or names a validator
+	// in the V.
+	Defined bool
+}
+
+// Describe reports the rules that Validate would apply to values of
+// sample's type, so that tools can document them, build forms from them,
+// or compare the rules of services. The rules are listed field by field,
+// in the order the fields are declared and the rules are named, with
+// the fields of types reached through "struct" rules listed after the
+// field naming them. Aliases are reported as the rules they stand for.
+//
+// The options that affect how fields are named and how their rules
+// are found, such as NameTags, TagKey, and Rules, apply to Describe as
+// they do to ValidateOpts; the rest are ignored.
+//
+// Like Snapshot, Describe does not look beyond types: a type that contains
+// itself is described once, and the fields of the values held by interface
+// fields are not described. It returns an error if sample is not a struct
+// or a pointer to one, or if one of the tags it reaches cannot be parsed.
+func (v V) Describe(sample interface{}, opts ...Option) ([]RuleDescription, error) {
+	t := reflect.TypeOf(sample)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot describe validation of %T", sample)
+	}
+
+	w := walker{v: v}
+	for _, o := range opts {
+		o(&w)
+	}
+	var ds []RuleDescription
+	err := w.describe(&ds, t, nil, map[reflect.Type]bool{t: true})
+	return ds, err
+}
+
+// describe appends the rules of the fields of t, found at path, to ds.
+// Types being described are in outer.
+func (w *walker) describe(ds *[]RuleDescription, t reflect.Type, path []string, outer map[reflect.Type]bool) error {
+	for _, fi := range w.fields(t) {
+		f := fi.field
+		fpath := append(path[:len(path):len(path)], w.fieldName(f))
+		rules, err := fi.rules, fi.err
+		if more := w.rules[DotPath(fpath)]; more != "" {
+			tag := fi.tag
+			if tag != "" {
+				tag += ","
+			}
+			rules, err = ParseTag(tag + more)
+		}
+		if err == nil {
+			rules, err = w.expand(rules, nil)
+		}
+		if err != nil {
+			return fmt.Errorf("%v.%s: %w", t, f.Name, err)
+		}
+
+		for _, r := range rules {
+			vf, _ := w.lookup(r.Name)
+			*ds = append(*ds, RuleDescription{
+				Path:    fpath,
+				Type:    f.Type,
+				Rule:    r,
+				Defined: vf != nil || Reserved(r.Name),
+			})
+		}
+
+		if !namesRule(rules, "struct") {
+			continue
+		}
+		ft := structType(f.Type)
+		if ft == nil || outer[ft] {
+			continue
+		}
+		spath := fpath
+		if elemKind(f.Type) {
+			spath = append(fpath[:len(fpath):len(fpath)], "[]")
+		}
+		outer[ft] = true
+		err = w.describe(ds, ft, spath, outer)
+		delete(outer, ft)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// elemKind reports whether t, through any pointers,
+// is a slice, array, or map.
+func elemKind(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package validate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestV_Describe(t *testing.T) {
+	type Server struct {
+		Port int `validate:"port" json:"port"`
+	}
+	type Config struct {
+		Name    string   `validate:"nonzero,len=3 10" json:"name"`
+		Servers []Server `validate:"each,struct"`
+		List    testNode `validate:"struct"`
+		Color   string   `validate:"hex|named"`
+		Skip    int      `validate:"-"`
+		Plain   int
+	}
+
+	vd := V{"nonzero": nonzero, "port": nonzero, "odd": nonzero, "hex": nonzero}
+	vd.RegisterField("len", func(Field) error { return nil })
+
+	ds, err := vd.Describe(&Config{}, NameTags("json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range ds {
+		s := d.Path.String() + " " + d.Type.String() + " " + d.Rule.Name + "(" + strings.Join(d.Rule.Params(), " ") + ")"
+		if d.Rule.Or {
+			s += " or"
+		}
+		if !d.Defined {
+			s += " undefined"
+		}
+		got = append(got, s)
+	}
+	want := []string{
+		"name string nonzero()",
+		"name string len(3 10)",
+		"Servers []validate.Server each()",
+		"Servers []validate.Server struct()",
+		"Servers[].port int port()",
+		"List validate.testNode struct()",
+		"List.Value int odd()",
+		"List.Next *validate.testNode struct()",
+		"Color string hex()",
+		"Color string named() or undefined",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("wrong descriptions:\n%s\nwanted:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if _, err := vd.Describe(3); err == nil {
+		t.Error("described an int")
+	}
+	type Bad struct {
+		A int `validate:"a,,b"`
+	}
+	if _, err := vd.Describe(Bad{}); err == nil || !strings.Contains(err.Error(), "validate.Bad.A: empty rule") {
+		t.Errorf("wrong error for a bad tag: %v", err)
+	}
+}
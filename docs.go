@@ -0,0 +1,109 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"html"
+	"strings"
+)
+
+// Markdown renders the rules that Validate would apply to values of
+// sample's type as a Markdown table, for READMEs and developer portals
+// that would otherwise describe the constraints by hand:
+//
+//	| Field | Type | Rules |
+//	| --- | --- | --- |
+//	| Name | string | `nonzero`, `len=3 10` |
+//	| Color | string | `hex` or `named` |
+//
+// The table has a row for each field with rules, as reported by Describe,
+// whose options it takes. Rules written with a colon in their tags are
+// shown that way, and alternatives are joined by "or".
+func (v V) Markdown(sample interface{}, opts ...Option) (string, error) {
+	rows, err := v.docRows(sample, opts)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString("| Field | Type | Rules |\n| --- | --- | --- |\n")
+	for _, r := range rows {
+		rules := make([]string, len(r.rules))
+		for i, alts := range r.rules {
+			for j, a := range alts {
+				alts[j] = "`" + strings.ReplaceAll(a, "|", `\|`) + "`"
+			}
+			rules[i] = strings.Join(alts, " or ")
+		}
+		b.WriteString("| " + markdownCell(r.field) + " | " + markdownCell(r.typ) + " | " + strings.Join(rules, ", ") + " |\n")
+	}
+	return b.String(), nil
+}
+
+// HTML renders the rules that Validate would apply to values of sample's
+// type as an HTML table, with the same rows as Markdown's. Rules are in
+// code elements, and the table has the class "validate-rules" for styling.
+func (v V) HTML(sample interface{}, opts ...Option) (string, error) {
+	rows, err := v.docRows(sample, opts)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString("<table class=\"validate-rules\">\n<thead><tr><th>Field</th><th>Type</th><th>Rules</th></tr></thead>\n<tbody>\n")
+	for _, r := range rows {
+		rules := make([]string, len(r.rules))
+		for i, alts := range r.rules {
+			for j, a := range alts {
+				alts[j] = "<code>" + html.EscapeString(a) + "</code>"
+			}
+			rules[i] = strings.Join(alts, " or ")
+		}
+		b.WriteString("<tr><td>" + html.EscapeString(r.field) + "</td><td>" + html.EscapeString(r.typ) + "</td><td>" + strings.Join(rules, ", ") + "</td></tr>\n")
+	}
+	b.WriteString("</tbody>\n</table>\n")
+	return b.String(), nil
+}
+
+// A docRow is a row of the tables of Markdown and HTML: a field,
+// its type, and its rules, each a list of alternatives.
+type docRow struct {
+	field, typ string
+	rules      [][]string
+}
+
+func (v V) docRows(sample interface{}, opts []Option) ([]docRow, error) {
+	ds, err := v.Describe(sample, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var rows []docRow
+	for _, d := range ds {
+		field := d.Path.String()
+		if len(rows) == 0 || rows[len(rows)-1].field != field {
+			rows = append(rows, docRow{field: field, typ: d.Type.String()})
+		}
+		r := &rows[len(rows)-1]
+		if d.Rule.Or {
+			last := len(r.rules) - 1
+			r.rules[last] = append(r.rules[last], ruleString(d.Rule))
+		} else {
+			r.rules = append(r.rules, []string{ruleString(d.Rule)})
+		}
+	}
+	return rows, nil
+}
+
+// ruleString returns r as it would be written in a tag.
+func ruleString(r Rule) string {
+	switch {
+	case strings.Contains(r.Param, ",") || strings.Contains(r.Param, "|"):
+		return r.Name + ":" + r.Param
+	case r.Param != "":
+		return r.Name + "=" + r.Param
+	}
+	return r.Name
+}
+
+// markdownCell escapes s for a cell of a Markdown table.
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
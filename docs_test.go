@@ -0,0 +1,61 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+)
+
+type docConfig struct {
+	Name  string `validate:"nonzero,len=3 10" json:"name"`
+	Expr  string `validate:"expr:a|b, c"`
+	Color string `validate:"hex|named"`
+	Plain int
+}
+
+func TestV_Markdown(t *testing.T) {
+	want := "| Field | Type | Rules |\n" +
+		"| --- | --- | --- |\n" +
+		"| name | string | `nonzero`, `len=3 10` |\n" +
+		"| Expr | string | `expr:a\\|b, c` |\n" +
+		"| Color | string | `hex` or `named` |\n"
+	got, err := V{}.Markdown(docConfig{}, NameTags("json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Fatalf("wrong table:\n%s\nwanted:\n%s", got, want)
+	}
+	if _, err := (V{}).Markdown(3); err == nil {
+		t.Error("rendered an int")
+	}
+}
+
+func TestV_HTML(t *testing.T) {
+	want := `<table class="validate-rules">
+<thead><tr><th>Field</th><th>Type</th><th>Rules</th></tr></thead>
+<tbody>
+<tr><td>Name</td><td>string</td><td><code>nonzero</code>, <code>len=3 10</code></td></tr>
+<tr><td>Expr</td><td>string</td><td><code>expr:a|b, c</code></td></tr>
+<tr><td>Color</td><td>string</td><td><code>hex</code> or <code>named</code></td></tr>
+</tbody>
+</table>
+`
+	got, err := V{}.HTML(&docConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Fatalf("wrong table:\n%s\nwanted:\n%s", got, want)
+	}
+
+	type Escaped struct {
+		A map[string]int `validate:"keys=a<b"`
+	}
+	got, err = V{}.HTML(Escaped{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "<tr><td>A</td><td>map[string]int</td><td><code>keys=a&lt;b</code></td></tr>"; !strings.Contains(got, want) {
+		t.Fatalf("not escaped:\n%s", got)
+	}
+}
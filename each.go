@@ -0,0 +1,86 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Each returns a validator that applies fn to each element of a slice
+// or array, so a validator for single values can be reused for collections:
+//
+//	vd["names"] = validate.Each(vd["name"])
+//
+// The validator reports the first element fn rejects,
+// wrapping fn's error with the element's index.
+// It reports an error for values that are not slices or arrays.
+//
+// The reserved "each" rule applies validators to every element instead,
+// reporting each invalid element separately.
+func Each(fn func(interface{}) error) func(interface{}) error {
+	return func(i interface{}) error {
+		val := reflect.ValueOf(i)
+		if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+			return errorf(ErrWrongType, "cannot validate elements of %T", i)
+		}
+		for j := 0; j < val.Len(); j++ {
+			if err := fn(val.Index(j).Interface()); err != nil {
+				return fmt.Errorf("element %d: %w", j, err)
+			}
+		}
+		return nil
+	}
+}
+
+// Keys returns a validator that applies fn to each key of a map.
+// The validator reports the first key fn rejects, with keys visited
+// in the order of their formatted values, wrapping fn's error with the key.
+// It reports an error for values that are not maps.
+func Keys(fn func(interface{}) error) func(interface{}) error {
+	return func(i interface{}) error {
+		keys, err := sortedKeys(i)
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if err := fn(k.Interface()); err != nil {
+				return fmt.Errorf("key %v: %w", k, err)
+			}
+		}
+		return nil
+	}
+}
+
+// Values returns a validator that applies fn to each value of a map.
+// The validator reports the first value fn rejects, with values visited
+// in the order of the formatted values of their keys, wrapping fn's error
+// with the key. It reports an error for values that are not maps.
+func Values(fn func(interface{}) error) func(interface{}) error {
+	return func(i interface{}) error {
+		keys, err := sortedKeys(i)
+		if err != nil {
+			return err
+		}
+		m := reflect.ValueOf(i)
+		for _, k := range keys {
+			if err := fn(m.MapIndex(k).Interface()); err != nil {
+				return fmt.Errorf("value for key %v: %w", k, err)
+			}
+		}
+		return nil
+	}
+}
+
+func sortedKeys(i interface{}) ([]reflect.Value, error) {
+	val := reflect.ValueOf(i)
+	if val.Kind() != reflect.Map {
+		return nil, errorf(ErrWrongType, "cannot validate entries of %T", i)
+	}
+	keys := val.MapKeys()
+	sort.Slice(keys, func(a, b int) bool {
+		return fmt.Sprint(keys[a]) < fmt.Sprint(keys[b])
+	})
+	return keys, nil
+}
@@ -0,0 +1,57 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var errShort = errors.New("too short")
+
+func long(i interface{}) error {
+	if len(i.(string)) < 3 {
+		return errShort
+	}
+	return nil
+}
+
+func TestEach(t *testing.T) {
+	type X struct {
+		A []string  `validate:"elements"`
+		B [2]string `validate:"elements"`
+		C int       `validate:"elements"`
+	}
+
+	vd := V{"elements": Each(long)}
+	errs := vd.Validate(X{
+		A: []string{"long", "no", "x"},
+		B: [2]string{"abc", "def"},
+	})
+
+	if len(errs) != 2 {
+		t.Fatalf("wrong number of errors: %v", errs)
+	}
+	if errs[0].Error() != "field A is invalid: element 1: too short" {
+		t.Fatalf("wrong error for slice: %v", errs[0])
+	}
+	if !errors.Is(errs[0].(BadField).Err, errShort) {
+		t.Fatalf("element error is not wrapped: %v", errs[0])
+	}
+	if errs[1].(BadField).Field != "C" {
+		t.Fatalf("no error for non-slice: %v", errs[1])
+	}
+}
+
+func TestKeysValues(t *testing.T) {
+	m := map[string]string{"ok": "fine", "abcd": "no", "x": "abc"}
+
+	if err := Keys(long)(m); fmt.Sprint(err) != "key ok: too short" {
+		t.Fatalf("wrong error for keys: %v", err)
+	}
+	if err := Values(long)(m); fmt.Sprint(err) != "value for key abcd: too short" {
+		t.Fatalf("wrong error for values: %v", err)
+	}
+	if err := Keys(long)([]string{}); err == nil {
+		t.Fatal("no error for non-map")
+	}
+}
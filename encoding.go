@@ -0,0 +1,93 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var base64Encodings = map[string]*base64.Encoding{
+	"":       base64.StdEncoding,
+	"url":    base64.URLEncoding,
+	"raw":    base64.RawStdEncoding,
+	"rawurl": base64.RawURLEncoding,
+}
+
+// base64Encoded is the "base64" builtin, which reports strings that are not
+// base64 in the encoding named by its parameter: the standard encoding by
+// default, or "url", "raw", or "rawurl" for the URL-safe alphabet, without
+// padding, or both.
+func base64Encoded(i interface{}, encoding string) error {
+	enc := base64Encodings[encoding]
+	if enc == nil {
+		return fmt.Errorf("parameter %q is not a base64 encoding", encoding)
+	}
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a string", i)
+	}
+	if _, err := enc.DecodeString(s); err != nil {
+		return errorf(ErrBadFormat, "%q is not valid base64: %v", s, err)
+	}
+	return nil
+}
+
+// base64Param is the parameter check of "base64".
+func base64Param(t reflect.Type, encoding string) error {
+	if base64Encodings[encoding] == nil {
+		return fmt.Errorf("parameter %q is not a base64 encoding", encoding)
+	}
+	return stringType(t)
+}
+
+func hexEncoded(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a string", i)
+	}
+	if _, err := hex.DecodeString(s); err != nil {
+		return errorf(ErrBadFormat, "%q is not valid hex: %v", s, err)
+	}
+	return nil
+}
+
+func validJSON(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a string", i)
+	}
+	if !json.Valid([]byte(s)) {
+		return errorf(ErrBadFormat, "%q is not valid JSON", s)
+	}
+	return nil
+}
+
+// jwt is the "jwt" builtin, which reports strings that are not JSON Web
+// Tokens in the compact serialization: a header and payload holding JSON
+// objects and a signature, which may be empty, each encoded as unpadded
+// base64url and separated by dots. The signature is not verified.
+func jwt(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a JWT", i)
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return errorf(ErrBadFormat, "%q is not a valid JWT: want three parts separated by dots", s)
+	}
+	for j, p := range parts {
+		b, err := base64.RawURLEncoding.DecodeString(p)
+		if err != nil {
+			return errorf(ErrBadFormat, "%q is not a valid JWT: part %d is not base64url: %v", s, j+1, err)
+		}
+		if j < 2 && (!json.Valid(b) || !strings.HasPrefix(strings.TrimSpace(string(b)), "{")) {
+			return errorf(ErrBadFormat, "%q is not a valid JWT: part %d is not a JSON object", s, j+1)
+		}
+	}
+	return nil
+}
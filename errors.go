@@ -0,0 +1,59 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"errors"
+)
+
+// These errors are wrapped by the errors of the validators provided by this
+// package, so that callers can use errors.Is to tell the kinds of failure
+// apart without depending on the text of messages.
+var (
+	// ErrWrongType is reported for a value of a type the validator
+	// does not accept.
+	ErrWrongType = errors.New("wrong type")
+
+	// ErrNotUnique is reported for a collection with equal elements.
+	ErrNotUnique = errors.New("not unique")
+
+	// ErrRequired is reported for a required value that is missing.
+	ErrRequired = errors.New("required")
+
+	// ErrTooShort and ErrTooLong are reported for a string or collection
+	// whose length is out of range.
+	ErrTooShort = errors.New("too short")
+	ErrTooLong  = errors.New("too long")
+
+	// ErrOutOfRange is reported for a number that is out of range.
+	ErrOutOfRange = errors.New("out of range")
+
+	// ErrNotAllowed is reported for a value that is not one of
+	// those allowed.
+	ErrNotAllowed = errors.New("not allowed")
+
+	// ErrBadFormat is reported for a string that is not in
+	// the required format.
+	ErrBadFormat = errors.New("bad format")
+)
+
+// kindError is an error with its own message
+// that wraps one of the sentinel errors.
+type kindError struct {
+	kind error
+	msg  Message
+}
+
+func (e kindError) Error() string {
+	return e.msg.Error()
+}
+
+func (e kindError) Unwrap() error {
+	return e.kind
+}
+
+// errorf returns an error formatted as for fmt.Errorf that wraps kind.
+// Its format is the key of its message in a Catalog.
+func errorf(kind error, format string, args ...interface{}) error {
+	return kindError{kind, Message{format, args}}
+}
@@ -0,0 +1,29 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSentinels(t *testing.T) {
+	vd := make(V)
+	vd.SetComparers(nil)
+
+	tests := []struct {
+		err  error
+		kind error
+	}{
+		{vd["unique"]([]int{1, 1}), ErrNotUnique},
+		{vd["unique"](map[int]int{1: 2, 2: 2}), ErrNotUnique},
+		{vd["unique"](7), ErrWrongType},
+		{Each(long)(7), ErrWrongType},
+		{Keys(long)(7), ErrWrongType},
+		{Types(0)(""), ErrWrongType},
+	}
+
+	for _, test := range tests {
+		if !errors.Is(test.err, test.kind) {
+			t.Fatalf("%v does not wrap %v", test.err, test.kind)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Errors is a list of the errors found by a validation,
+// usable as a single error. The errors returned by Validate
+// can be converted to Errors for its helpers:
+//
+//	if validate.Errors(vd.Validate(x)).Has("Email") {
+//		…
+//	}
+type Errors []error
+
+// Error returns the messages of the errors, separated by semicolons.
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the errors, so that errors.Is and errors.As
+// consider each of them.
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+// First returns the first of the errors, or nil if there are none.
+func (e Errors) First() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e[0]
+}
+
+// Filter returns the errors for which keep returns true.
+func (e Errors) Filter(keep func(error) bool) Errors {
+	var out Errors
+	for _, err := range e {
+		if keep(err) {
+			out = append(out, err)
+		}
+	}
+	return out
+}
+
+// ByField returns the errors for the field named name,
+// as it appears in BadField.Field.
+func (e Errors) ByField(name string) Errors {
+	return e.Filter(func(err error) bool {
+		var bf BadField
+		return errors.As(err, &bf) && bf.Field == name
+	})
+}
+
+// Has reports whether there are errors for the field named name.
+func (e Errors) Has(name string) bool {
+	return len(e.ByField(name)) > 0
+}
+
+// Fields returns the names of the invalid fields,
+// in the order of their first errors.
+func (e Errors) Fields() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, err := range e {
+		var bf BadField
+		if errors.As(err, &bf) && !seen[bf.Field] {
+			seen[bf.Field] = true
+			names = append(names, bf.Field)
+		}
+	}
+	return names
+}
+
+// MarshalJSON encodes e as an object mapping the names of invalid fields
+// to the messages of their errors, in the form web front ends expect:
+//
+//	{"Name": ["should not be empty"], "Server.Port": ["is out of range"]}
+//
+// Errors that are not BadFields are listed under the empty name.
+func (e Errors) MarshalJSON() ([]byte, error) {
+	fields := make(map[string][]string)
+	for _, err := range e {
+		var bf BadField
+		if errors.As(err, &bf) {
+			fields[bf.Field] = append(fields[bf.Field], bf.Err.Error())
+		} else {
+			fields[""] = append(fields[""], err.Error())
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// ValidateErr behaves like Validate, but returns its errors as a single
+// error, or nil if there are none, for code that handles single errors.
+// The error is an Errors, from which errors.As can extract any BadField:
+//
+//	var bf validate.BadField
+//	if errors.As(vd.ValidateErr(x), &bf) {
+//		…
+//	}
+func (v V) ValidateErr(s interface{}) error {
+	if errs := v.Validate(s); len(errs) > 0 {
+		return Errors(errs)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestV_ValidateErr(t *testing.T) {
+	type X struct {
+		A int `validate:"odd"`
+		B int `validate:"odd"`
+	}
+
+	errEven := errors.New("even")
+	vd := V{
+		"odd": func(i interface{}) error {
+			if i.(int)%2 == 0 {
+				return fmt.Errorf("%d is %w", i, errEven)
+			}
+			return nil
+		},
+	}
+
+	if err := vd.ValidateErr(X{1, 3}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := vd.ValidateErr(X{2, 4})
+	if err.Error() != "field A is invalid: 2 is even; field B is invalid: 4 is even" {
+		t.Fatalf("wrong message: %v", err)
+	}
+	var bf BadField
+	if !errors.As(err, &bf) || bf.Field != "A" {
+		t.Fatalf("no BadField in error: %v", err)
+	}
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("error is not an Errors: %v", err)
+	}
+}
+
+func TestBadField_Unwrap(t *testing.T) {
+	type X struct {
+		A int `validate:"missing"`
+		B int `validate:"unique"`
+	}
+
+	errs := Builtin().Validate(X{})
+	if len(errs) != 2 {
+		t.Fatalf("wrong number of errors: %v", errs)
+	}
+	if !errors.Is(errs[0], ErrUndefinedValidator) || errs[0].Error() != `field A is invalid: undefined validator: "missing"` {
+		t.Fatalf("wrong error for an undefined validator: %v", errs[0])
+	}
+	var ke kindError
+	if !errors.Is(errs[1], ErrWrongType) || !errors.As(errs[1], &ke) {
+		t.Fatalf("validator's error is not reachable: %v", errs[1])
+	}
+}
+
+func TestErrors_MarshalJSON(t *testing.T) {
+	type Y struct {
+		B []int `validate:"nonempty,each,nonzero"`
+	}
+	type X struct {
+		A string `json:"a" validate:"nonempty,alpha"`
+		Y Y      `json:"y" validate:"struct"`
+	}
+
+	errs := Builtin().ValidateAndTag(X{Y: Y{B: []int{1, 0}}}, "json")
+	b, err := json.Marshal(append(Errors(errs), errors.New("canceled")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"":["canceled"],"a":["should not be empty","empty string is not alphabetic"],"y.B[1]":["should be nonzero"]}`
+	if string(b) != want {
+		t.Fatalf("wrong JSON: %s, wanted %s", b, want)
+	}
+}
+
+func TestErrors_helpers(t *testing.T) {
+	type X struct {
+		A string `validate:"nonempty,alpha"`
+		B string `validate:"email"`
+		C string `validate:"alpha"`
+	}
+
+	errs := Errors(Builtin().Validate(X{B: "x", C: "c"}))
+	if fmt.Sprint(errs.Fields()) != "[A B]" {
+		t.Fatalf("wrong fields: %v", errs.Fields())
+	}
+	if !errs.Has("A") || !errs.Has("B") || errs.Has("C") {
+		t.Fatal("wrong fields reported by Has")
+	}
+	if a := errs.ByField("A"); a.Error() != errs[:2].Error() {
+		t.Fatalf("wrong errors for A: %v", a)
+	}
+	if errs.First().Error() != errs[0].Error() || Errors(nil).First() != nil {
+		t.Fatal("wrong first error")
+	}
+	bad := errs.Filter(func(err error) bool {
+		return errors.Is(err, ErrBadFormat)
+	})
+	if fmt.Sprint(bad.Fields()) != "[A B]" || len(bad) != 2 {
+		t.Fatalf("wrong filtered errors: %v", bad)
+	}
+}
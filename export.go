@@ -0,0 +1,113 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"reflect"
+	"strings"
+)
+
+// A RuleSet holds the rules of a struct type's fields in a form that can
+// be encoded as JSON and sent to clients, such as web forms, which can
+// mirror the simple constraints before submitting values:
+//
+//	{
+//		"type": "main.Signup",
+//		"fields": [
+//			{
+//				"field": "name",
+//				"type": "string",
+//				"tag": "nonzero,len=3 10",
+//				"rules": [
+//					{"name": "nonzero"},
+//					{"name": "len", "param": "3 10", "params": ["3", "10"]}
+//				]
+//			}
+//		]
+//	}
+type RuleSet struct {
+	Type   string       `json:"type"`
+	Fields []FieldRules `json:"fields"`
+}
+
+// FieldRules holds the rules of a field in a RuleSet.
+type FieldRules struct {
+	// Field is the path to the field, as in a BadField's Field, with
+	// the elements of slices, arrays, and maps written as "[]".
+	Field string `json:"field"`
+
+	// Type is the JSON type of the field's values: "string", "number",
+	// "integer", "boolean", "array", or "object", or "" if they have none.
+	Type string `json:"type,omitempty"`
+
+	// Tag holds the rules as they would be written in a validate tag.
+	Tag string `json:"tag"`
+
+	Rules []ExportedRule `json:"rules"`
+}
+
+// ExportedRule is a rule in a RuleSet.
+type ExportedRule struct {
+	Name   string   `json:"name"`
+	Param  string   `json:"param,omitempty"`
+	Params []string `json:"params,omitempty"`
+
+	// Or reports whether the rule is an alternative to the rule
+	// before it, as for Rule.
+	Or bool `json:"or,omitempty"`
+}
+
+// RuleSet returns the rules that Validate would apply to values of
+// sample's type, as reported by Describe, whose options it takes.
+// Fields with no rules are omitted.
+func (v V) RuleSet(sample interface{}, opts ...Option) (*RuleSet, error) {
+	ds, err := v.Describe(sample, opts...)
+	if err != nil {
+		return nil, err
+	}
+	t := reflect.TypeOf(sample)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	rs := &RuleSet{Type: t.String(), Fields: []FieldRules{}}
+	for _, d := range ds {
+		field := d.Path.String()
+		if n := len(rs.Fields); n == 0 || rs.Fields[n-1].Field != field {
+			ft := d.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			typ, _ := typeSchema(ft).Type.(string)
+			rs.Fields = append(rs.Fields, FieldRules{Field: field, Type: typ})
+		}
+		f := &rs.Fields[len(rs.Fields)-1]
+		switch {
+		case d.Rule.Or:
+			f.Tag += "|"
+		case f.Tag != "":
+			f.Tag += ","
+		}
+		f.Tag += ruleString(d.Rule)
+		f.Rules = append(f.Rules, ExportedRule{
+			Name:   d.Rule.Name,
+			Param:  d.Rule.Param,
+			Params: d.Rule.Params(),
+			Or:     d.Rule.Or,
+		})
+	}
+	return rs, nil
+}
+
+// Manifest returns the rules of s as a manifest for the validate command,
+// mapping the paths of fields to their tags. The fields of elements are
+// omitted, as manifests cannot address them.
+func (s *RuleSet) Manifest() map[string]string {
+	m := make(map[string]string, len(s.Fields))
+	for _, f := range s.Fields {
+		if !strings.Contains(f.Field, "[]") {
+			m[f.Field] = f.Tag
+		}
+	}
+	return m
+}
@@ -0,0 +1,52 @@
+package validate
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestV_RuleSet(t *testing.T) {
+	type Item struct {
+		SKU string `validate:"nonzero" json:"sku"`
+	}
+	type Order struct {
+		Name  string  `validate:"nonzero,len=3 10" json:"name"`
+		Color *string `validate:"hex|named" json:"color"`
+		Items []Item  `validate:"each,struct" json:"items"`
+		Note  string  `validate:"expr:a, b" json:"note"`
+		Plain int     `json:"plain"`
+	}
+
+	rs, err := V{}.RuleSet(&Order{}, NameTags("json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(rs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"validate.Order","fields":[` +
+		`{"field":"name","type":"string","tag":"nonzero,len=3 10","rules":[{"name":"nonzero"},{"name":"len","param":"3 10","params":["3","10"]}]},` +
+		`{"field":"color","type":"string","tag":"hex|named","rules":[{"name":"hex"},{"name":"named","or":true}]},` +
+		`{"field":"items","type":"array","tag":"each,struct","rules":[{"name":"each"},{"name":"struct"}]},` +
+		`{"field":"items[].sku","type":"string","tag":"nonzero","rules":[{"name":"nonzero"}]},` +
+		`{"field":"note","type":"string","tag":"expr:a, b","rules":[{"name":"expr","param":"a, b","params":["a,","b"]}]}]}`
+	if string(b) != want {
+		t.Fatalf("wrong JSON:\n%s\nwanted:\n%s", b, want)
+	}
+
+	wantManifest := map[string]string{
+		"name":  "nonzero,len=3 10",
+		"color": "hex|named",
+		"items": "each,struct",
+		"note":  "expr:a, b",
+	}
+	if m := rs.Manifest(); !reflect.DeepEqual(m, wantManifest) {
+		t.Errorf("wrong manifest: %v", m)
+	}
+
+	if _, err := (V{}).RuleSet(3); err == nil {
+		t.Error("exported the rules of an int")
+	}
+}
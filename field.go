@@ -0,0 +1,285 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Field describes a field to an extended validator.
+type Field struct {
+	// Name is the field's name, as it would appear in a BadField.
+	Name string
+
+	// Value is the field's value. It is settable if the struct
+	// containing the field was passed by pointer.
+	Value reflect.Value
+
+	// Parent is the struct containing the field.
+	Parent reflect.Value
+
+	// Rule is the rule naming the validator.
+	Rule Rule
+
+	// Context is the context passed to ValidateContext or by the Context
+	// option, or context.Background for other methods.
+	// Validators can use it to find the locale for their messages.
+	Context context.Context
+}
+
+// RegisterField adds an extended validator to v under name.
+// Validate passes fn a Field instead of the field's value.
+// Only extended validators accept parameters, including the raw
+// parameters of rules written with a colon; see Rule.
+// Any parameter check of a validator it replaces is forgotten.
+func (v V) RegisterField(name string, fn func(Field) error) {
+	notReserved(name)
+	v[name+"="] = func(i interface{}) error {
+		return fn(i.(Field))
+	}
+	if m := v.meta(); m != nil && m.params[name] != nil {
+		delete(v.editMeta().params, name)
+	}
+}
+
+// RegisterParam adds a validator taking a parameter to v under name.
+// Validate passes fn the field's value and the parameter given to the rule
+// in the tag, which is empty if there is none, so that one validator can
+// serve many fields:
+//
+//	vd.RegisterParam("min", func(i interface{}, param string) error {
+//		min, err := strconv.Atoi(param)
+//		…
+//	})
+//
+//	type X struct {
+//		A int `validate:"min=5"`
+//		B int `validate:"min=40"`
+//	}
+//
+// The validator is stored in v as an extended validator.
+func (v V) RegisterParam(name string, fn func(value interface{}, param string) error) {
+	v.RegisterField(name, func(f Field) error {
+		return fn(f.Value.Interface(), f.Rule.Param)
+	})
+}
+
+// RegisterParamCheck adds to v a check of the parameters given to the
+// extended validator named name, so that Check can report malformed
+// parameters, and fields the validator cannot handle, without validating
+// any values:
+//
+//	vd.RegisterParam("min", min)
+//	vd.RegisterParamCheck("min", func(t reflect.Type, param string) error {
+//		if _, err := strconv.Atoi(param); err != nil {
+//			return fmt.Errorf("parameter %q is not an integer", param)
+//		}
+//		…
+//	})
+//
+// Check passes fn the type of each field the rule is applied to, or of
+// its elements for rules following "each", with pointers followed, and
+// the rule's parameter. The type is nil if it is not known, as for
+// interface fields and Optionals. The check is kept alongside the
+// validator in v, and is forgotten if the validator is replaced by
+// RegisterField or the methods using it.
+func (v V) RegisterParamCheck(name string, fn func(t reflect.Type, param string) error) {
+	m := v.editMeta()
+	if m.params == nil {
+		m.params = make(map[string]func(reflect.Type, string) error)
+	}
+	m.params[name] = fn
+}
+
+// paramCheck returns the parameter check of the extended validator for
+// the rule name, if it has one.
+func (w *walker) paramCheck(name string) func(reflect.Type, string) error {
+	ls, _ := w.layers()
+	for _, l := range ls {
+		if l.m != nil && l.m.params[name] != nil {
+			return l.m.params[name]
+		}
+		if l.v[name] != nil || l.v[name+"="] != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// RegisterCross adds a validator comparing fields of a struct to v under
+// name. Validate passes fn the struct containing the field, by value,
+// and the field's value, so that rules relating fields can be named in
+// their tags:
+//
+//	vd.RegisterCross("after_start", func(s, field interface{}) error {
+//		if !field.(time.Time).After(s.(Event).Start) {
+//			return errors.New("should be after the start")
+//		}
+//		return nil
+//	})
+//
+//	type Event struct {
+//		Start time.Time
+//		End   time.Time `validate:"after_start"`
+//	}
+//
+// The validator is stored in v as an extended validator. Validators that
+// need the rule's parameter, such as the name of the field to compare with,
+// can be written with RegisterField, using Field.Parent.
+func (v V) RegisterCross(name string, fn func(s, field interface{}) error) {
+	v.RegisterField(name, func(f Field) error {
+		return fn(f.Parent.Interface(), f.Value.Interface())
+	})
+}
+
+// ErrValidatorTimeout is wrapped by the error reported for a rule whose
+// validator, added with RegisterContext, did not finish in time.
+var ErrValidatorTimeout = errors.New("validator timed out")
+
+// RegisterContext adds a validator that may block, such as one looking a
+// value up in a database, to v under name. Validate passes fn the field's
+// value and the validation's context, which is done once timeout has
+// passed, if it is positive, or once the context passed to ValidateContext
+// or the Context option is done:
+//
+//	vd.RegisterContext("unique_email", 2*time.Second, func(ctx context.Context, i interface{}) error {
+//		taken, err := db.EmailTaken(ctx, i.(string))
+//		…
+//	})
+//
+// If fn has not returned by then, Validate stops waiting for it and reports
+// an error wrapping ErrValidatorTimeout, or ErrValidationCanceled if the
+// validation's context is done, so that one slow dependency cannot hold up
+// the rest of the validation. fn should return soon after its context is
+// done, as it is left running.
+//
+// The validator is stored in v as an extended validator.
+func (v V) RegisterContext(name string, timeout time.Duration, fn func(ctx context.Context, value interface{}) error) {
+	v.RegisterField(name, func(f Field) error {
+		ctx, cancel := f.Context, context.CancelFunc(func() {})
+		if timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		defer cancel()
+
+		// A panic in fn is passed back to be recovered as usual.
+		type result struct {
+			err   error
+			panic interface{}
+		}
+		done := make(chan result, 1)
+		value := f.Value.Interface()
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					done <- result{panic: p}
+				}
+			}()
+			done <- result{err: fn(ctx, value)}
+		}()
+
+		select {
+		case r := <-done:
+			if r.panic != nil {
+				panic(r.panic)
+			}
+			return r.err
+		case <-ctx.Done():
+			if err := f.Context.Err(); err != nil {
+				return fmt.Errorf("%w: %v", ErrValidationCanceled, err)
+			}
+			return fmt.Errorf("%w: %q after %v", ErrValidatorTimeout, f.Rule.Name, timeout)
+		}
+	})
+}
+
+// RegisterFallback sets the extended validator called for rules that name
+// no validator in v, in place of reporting them as undefined. It can, for
+// example, consult a service for rules defined elsewhere, or ignore unknown
+// rules by returning nil. The rule's name is in the Field passed to fn.
+//
+// The fallback is stored in v as an extended validator with the empty name.
+func (v V) RegisterFallback(fn func(Field) error) {
+	v.RegisterField("", fn)
+}
+
+// ErrNotAddressable is reported for a field with a normalizer
+// when its struct was not passed by pointer.
+var ErrNotAddressable = errors.New("cannot normalize a field of a struct not passed by pointer")
+
+// RegisterNormalizer adds a normalizer to v under name.
+// A normalizer may rewrite a field's value, through the settable
+// reflect.Value passed to fn, before the validators named after it in
+// the field's tag are applied. For example, with
+//
+//	vd.RegisterNormalizer("trim", func(v reflect.Value) error {
+//		v.SetString(strings.TrimSpace(v.String()))
+//		return nil
+//	})
+//
+// the tag `validate:"trim,nonempty"` trims a field before checking
+// that it is not empty.
+//
+// Normalizers can only change the fields of structs passed to Validate
+// by pointer; for other structs, fn is not called and the field is reported
+// with ErrNotAddressable.
+func (v V) RegisterNormalizer(name string, fn func(reflect.Value) error) {
+	v.RegisterField(name, func(f Field) error {
+		if !f.Value.CanSet() {
+			return ErrNotAddressable
+		}
+		return fn(f.Value)
+	})
+}
+
+// redacted replaces the error of a validator for a sensitive field.
+// It keeps only the kind of the error, which says nothing of the value.
+type redacted struct {
+	rule string
+	kind error
+}
+
+// kinds are the errors a redacted error may wrap.
+var kinds = []error{
+	ErrWrongType, ErrNotUnique, ErrRequired, ErrTooShort, ErrTooLong,
+	ErrOutOfRange, ErrNotAllowed, ErrBadFormat, ErrUndefinedValidator,
+	ErrValidatorPanicked, ErrValidatorTimeout, ErrMaxDepth,
+}
+
+// redact returns the error reported for a sensitive field in place of
+// err, the error of the rule named rule.
+func redact(rule string, err error) redacted {
+	for _, k := range kinds {
+		if errors.Is(err, k) {
+			return redacted{rule, k}
+		}
+	}
+	return redacted{rule, nil}
+}
+
+func (r redacted) Error() string {
+	return fmt.Sprintf("failed %q (details of sensitive field redacted)", r.rule)
+}
+
+func (r redacted) Unwrap() error {
+	return r.kind
+}
+
+// message replaces the error of a field's validator with the text given
+// by the field's "msg" rule.
+type message struct {
+	text string
+	err  error
+}
+
+func (m message) Error() string {
+	return m.text
+}
+
+func (m message) Unwrap() error {
+	return m.err
+}
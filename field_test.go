@@ -0,0 +1,265 @@
+package validate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestV_RegisterField(t *testing.T) {
+	type X struct {
+		A int `validate:"named"`
+	}
+	type Y struct {
+		X X `validate:"struct"`
+	}
+
+	var got Field
+	vd := make(V)
+	vd.RegisterField("named", func(f Field) error {
+		got = f
+		return fmt.Errorf("%s is %v", f.Name, f.Value)
+	})
+
+	errs := vd.Validate(Y{X{A: 3}})
+	if len(errs) != 1 || errs[0].Error() != "field X.A is invalid: X.A is 3" {
+		t.Fatalf("wrong errors from extended validator: %v", errs)
+	}
+	if got.Value.Int() != 3 {
+		t.Fatalf("wrong value passed to extended validator: %v", got.Value)
+	}
+}
+
+func TestV_RegisterParam(t *testing.T) {
+	type X struct {
+		A int `validate:"min=5"`
+		B int `validate:"min=40"`
+		C int `validate:"min"`
+	}
+
+	vd := make(V)
+	vd.RegisterParam("min", func(i interface{}, param string) error {
+		min, err := strconv.Atoi(param)
+		if err != nil {
+			return fmt.Errorf("bad minimum %q", param)
+		}
+		if i.(int) < min {
+			return fmt.Errorf("%d is less than %d", i, min)
+		}
+		return nil
+	})
+
+	errs := vd.Validate(X{5, 39, 0})
+	want := `[field B is invalid: 39 is less than 40 field C is invalid: bad minimum ""]`
+	if fmt.Sprint(errs) != want {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+}
+
+func TestV_RegisterCross(t *testing.T) {
+	type Signup struct {
+		Password string
+		Confirm  string `validate:"pwmatch"`
+	}
+	type Form struct {
+		Signup Signup `validate:"struct"`
+	}
+
+	vd := make(V)
+	vd.RegisterCross("pwmatch", func(s, field interface{}) error {
+		if field.(string) != s.(Signup).Password {
+			return fmt.Errorf("passwords do not match")
+		}
+		return nil
+	})
+
+	if errs := vd.Validate(&Form{Signup{"a", "a"}}); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	errs := vd.Validate(Form{Signup{"a", "b"}})
+	if len(errs) != 1 || errs[0].Error() != "field Signup.Confirm is invalid: passwords do not match" {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+}
+
+func TestV_RegisterNormalizer(t *testing.T) {
+	type X struct {
+		A string `validate:"trim,nonempty"`
+	}
+	type Y struct {
+		X *X    `validate:"struct"`
+		B X     `validate:"struct"`
+		C []int `validate:"trim"`
+	}
+
+	vd := make(V)
+	vd.RegisterNormalizer("trim", func(v reflect.Value) error {
+		if v.Kind() != reflect.String {
+			return fmt.Errorf("cannot trim %v", v.Type())
+		}
+		v.SetString(strings.TrimSpace(v.String()))
+		return nil
+	})
+	vd["nonempty"] = func(i interface{}) error {
+		if i.(string) == "" {
+			return fmt.Errorf("is empty")
+		}
+		return nil
+	}
+
+	y := Y{X: &X{"  x  "}, B: X{" "}}
+	errs := vd.Validate(&y)
+	if len(errs) != 2 {
+		t.Fatalf("wrong number of errors: %v", errs)
+	}
+	if errs[0].Error() != "field B.A is invalid: is empty" {
+		t.Fatalf("normalized value was not validated: %v", errs[0])
+	}
+	if y.X.A != "x" || y.B.A != "" {
+		t.Fatalf("fields were not normalized: %q, %q", y.X.A, y.B.A)
+	}
+
+	x := X{" x "}
+	errs = vd.Validate(x)
+	if len(errs) != 1 || !errors.Is(errs[0].(BadField).Err, ErrNotAddressable) {
+		t.Fatalf("wrong errors for unaddressable struct: %v", errs)
+	}
+}
+
+func TestV_Validate_sensitive(t *testing.T) {
+	type X struct {
+		Password string `validate:"sensitive,long"`
+		Token    string `validate:"sensitive,explode"`
+	}
+
+	vd := make(V)
+	vd["long"] = func(i interface{}) error {
+		if len(i.(string)) < 8 {
+			return fmt.Errorf("%q is %w", i, ErrTooShort)
+		}
+		return nil
+	}
+	vd["explode"] = func(i interface{}) error {
+		panic(fmt.Sprintf("cannot parse %q", i))
+	}
+
+	errs := vd.Validate(X{"hunter2", "hunter2"})
+	if len(errs) != 2 {
+		t.Fatalf("wrong number of errors: %v", errs)
+	}
+	for _, err := range errs {
+		if leaks(err, "hunter2") {
+			t.Fatalf("sensitive value appears in error: %v", err)
+		}
+	}
+	if !errors.Is(errs[0], ErrTooShort) || !errors.Is(errs[1], ErrValidatorPanicked) {
+		t.Fatalf("kinds of errors are lost: %v", errs)
+	}
+	if errs[0].(BadField).Rule != "long" || errs[1].(BadField).Rule != "explode" {
+		t.Fatalf("wrong rules: %v", errs)
+	}
+}
+
+// leaks reports whether s appears in err or any error it wraps.
+func leaks(err error, s string) bool {
+	if err == nil {
+		return false
+	}
+	if strings.Contains(fmt.Sprintf("%v %+v", err, err), s) {
+		return true
+	}
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return leaks(u.Unwrap(), s)
+	case interface{ Unwrap() []error }:
+		for _, e := range u.Unwrap() {
+			if leaks(e, s) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestV_RegisterContext(t *testing.T) {
+	type X struct {
+		A string `validate:"unique"`
+		B string `validate:"unique"`
+		C string `validate:"unique"`
+		D string `validate:"unique"`
+	}
+
+	vd := make(V)
+	vd.RegisterContext("unique", 20*time.Millisecond, func(ctx context.Context, i interface{}) error {
+		switch i.(string) {
+		case "slow":
+			<-ctx.Done()
+			time.Sleep(time.Millisecond)
+			return nil
+		case "taken":
+			return errors.New("is taken")
+		case "panic":
+			panic("oops")
+		}
+		return nil
+	})
+
+	start := time.Now()
+	errs := vd.Validate(X{"ok", "taken", "slow", "panic"})
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("validation took %v", elapsed)
+	}
+	if len(errs) != 3 || errs[0].Error() != "field B is invalid: is taken" {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+	if !errors.Is(errs[1], ErrValidatorTimeout) || errs[1].Error() != `field C is invalid: validator timed out: "unique" after 20ms` {
+		t.Fatal("wrong error for the slow validator:", errs[1])
+	}
+	if !errors.Is(errs[2], ErrValidatorPanicked) {
+		t.Fatal("wrong error for the panic:", errs[2])
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	vd.RegisterContext("wait", 0, func(ctx context.Context, i interface{}) error {
+		cancel()
+		<-ctx.Done()
+		return nil
+	})
+	type Y struct {
+		A string `validate:"wait"`
+	}
+	errs = vd.ValidateOpts(Y{}, Context(ctx))
+	if len(errs) != 1 || !errors.Is(errs[0], ErrValidationCanceled) || errors.Is(errs[0], ErrValidatorTimeout) {
+		t.Fatalf("wrong errors when canceled: %v", errs)
+	}
+}
+
+func TestV_RegisterFallback(t *testing.T) {
+	type X struct {
+		A int `validate:"odd,remote,unknown"`
+	}
+
+	vd := make(V)
+	vd["odd"] = func(i interface{}) error {
+		return fmt.Errorf("not odd")
+	}
+	vd.RegisterFallback(func(f Field) error {
+		if f.Rule.Name == "remote" {
+			return fmt.Errorf("rejected by remote rule")
+		}
+		return nil
+	})
+
+	errs := vd.Validate(X{})
+	if fmt.Sprint(errs) != "[field A is invalid: not odd field A is invalid: rejected by remote rule]" {
+		t.Fatalf("wrong errors with fallback: %v", errs)
+	}
+	if errs[1].(BadField).Rule != "remote" {
+		t.Fatalf("wrong rule for fallback error: %v", errs[1])
+	}
+}
@@ -0,0 +1,178 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import "strings"
+
+// These validators check the identifiers used in payments: card numbers,
+// bank account numbers, and currencies. Each reports a value whose kind is
+// not string with an error wrapping ErrWrongType. Card and account numbers
+// that are malformed or fail their checksums are reported with errors
+// wrapping ErrBadFormat, and unknown currencies with one wrapping
+// ErrNotAllowed.
+
+// Luhn validates a string of digits ending in a check digit computed by
+// the Luhn algorithm, as are card numbers and many other identifiers.
+func Luhn(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a number with a Luhn check digit", i)
+	}
+	if len(s) < 2 || strings.Trim(s, "0123456789") != "" {
+		return errorf(ErrBadFormat, "%q is not a number with a Luhn check digit: want only digits", s)
+	}
+	if !luhn(s) {
+		return errorf(ErrBadFormat, "%q has the wrong Luhn check digit", s)
+	}
+	return nil
+}
+
+// luhn reports whether the digits in s have a valid Luhn checksum.
+func luhn(s string) bool {
+	sum := 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		d := int(s[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// CreditCard validates a payment card number: 12 to 19 digits, which may
+// be grouped with single spaces or hyphens, as in "4111 1111 1111 1111",
+// ending in a Luhn check digit.
+func CreditCard(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a card number", i)
+	}
+	digits := make([]byte, 0, len(s))
+	for j := 0; j < len(s); j++ {
+		switch c := s[j]; {
+		case '0' <= c && c <= '9':
+			digits = append(digits, c)
+		case (c == ' ' || c == '-') && j > 0 && j < len(s)-1 && s[j-1] != ' ' && s[j-1] != '-':
+		default:
+			return errorf(ErrBadFormat, "%q is not a card number: want digits, grouped by spaces or hyphens", s)
+		}
+	}
+	if len(digits) < 12 || len(digits) > 19 {
+		return errorf(ErrBadFormat, "%q is not a card number: want 12 to 19 digits", s)
+	}
+	if !luhn(string(digits)) {
+		return errorf(ErrBadFormat, "%q is not a card number: wrong check digit", s)
+	}
+	return nil
+}
+
+// ibanLengths holds the length of the IBANs of each country in the IBAN
+// registry.
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16,
+	"BG": 22, "BH": 22, "BI": 27, "BR": 29, "BY": 28, "CH": 21, "CR": 22,
+	"CY": 28, "CZ": 24, "DE": 22, "DJ": 27, "DK": 18, "DO": 28, "EE": 20,
+	"EG": 29, "ES": 24, "FI": 18, "FK": 18, "FO": 18, "FR": 27, "GB": 22,
+	"GE": 22, "GI": 23, "GL": 18, "GR": 27, "GT": 28, "HN": 28, "HR": 21,
+	"HU": 28, "IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27, "JO": 30,
+	"KW": 30, "KZ": 20, "LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20,
+	"LV": 21, "LY": 25, "MC": 27, "MD": 24, "ME": 22, "MK": 19, "MN": 20,
+	"MR": 27, "MT": 31, "MU": 30, "NI": 28, "NL": 18, "NO": 15, "OM": 23,
+	"PK": 24, "PL": 28, "PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22,
+	"RU": 33, "SA": 24, "SC": 31, "SD": 18, "SE": 24, "SI": 19, "SK": 24,
+	"SM": 27, "SO": 23, "ST": 25, "SV": 28, "TL": 23, "TN": 24, "TR": 26,
+	"UA": 29, "VA": 22, "VG": 24, "XK": 20, "YE": 30,
+}
+
+// IBAN validates an International Bank Account Number, as defined by
+// ISO 13616: the code of a country in the IBAN registry, two check digits,
+// and uppercase letters and digits, making up the length used by the
+// country. It may be written in groups of four separated by single spaces,
+// as in "GB82 WEST 1234 5698 7654 32".
+func IBAN(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not an IBAN", i)
+	}
+	iban := s
+	if strings.Contains(s, " ") {
+		groups := strings.Split(s, " ")
+		for j, g := range groups {
+			if len(g) != 4 && (j < len(groups)-1 || g == "") {
+				return errorf(ErrBadFormat, "%q is not a valid IBAN: want groups of four characters", s)
+			}
+		}
+		iban = strings.Join(groups, "")
+	}
+	for j := 0; j < len(iban); j++ {
+		c := iban[j]
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return errorf(ErrBadFormat, "%q is not a valid IBAN: want uppercase letters and digits", s)
+		}
+	}
+	if len(iban) < 4 || strings.Trim(iban[2:4], "0123456789") != "" {
+		return errorf(ErrBadFormat, "%q is not a valid IBAN: want a country code and two check digits", s)
+	}
+	n, ok := ibanLengths[iban[:2]]
+	if !ok {
+		return errorf(ErrBadFormat, "%q is not a valid IBAN: %s is not a country using IBANs", s, iban[:2])
+	}
+	if len(iban) != n {
+		return errorf(ErrBadFormat, "%q is not a valid IBAN: want %d characters for %s", s, n, iban[:2])
+	}
+
+	// The check digits make the number formed by moving the first four
+	// characters to the end, with letters replaced by 10 to 35, one more
+	// than a multiple of 97.
+	rem := 0
+	for _, c := range iban[4:] + iban[:4] {
+		if c >= 'A' {
+			rem = (rem*100 + int(c-'A'+10)) % 97
+		} else {
+			rem = (rem*10 + int(c-'0')) % 97
+		}
+	}
+	if rem != 1 {
+		return errorf(ErrBadFormat, "%q is not a valid IBAN: wrong check digits", s)
+	}
+	return nil
+}
+
+// currencies holds the active currency codes of ISO 4217.
+var currencies = make(map[string]bool)
+
+func init() {
+	for _, c := range strings.Fields(`
+		AED AFN ALL AMD AOA ARS AUD AWG AZN BAM BBD BDT BGN BHD BIF BMD BND
+		BOB BOV BRL BSD BTN BWP BYN BZD CAD CDF CHE CHF CHW CLF CLP CNY COP
+		COU CRC CUP CVE CZK DJF DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL
+		GHS GIP GMD GNF GTQ GYD HKD HNL HTG HUF IDR ILS INR IQD IRR ISK JMD
+		JOD JPY KES KGS KHR KMF KPW KRW KWD KYD KZT LAK LBP LKR LRD LSL LYD
+		MAD MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MXV MYR MZN NAD NGN
+		NIO NOK NPR NZD OMR PAB PEN PGK PHP PKR PLN PYG QAR RON RSD RUB RWF
+		SAR SBD SCR SDG SEK SGD SHP SLE SOS SRD SSP STN SVC SYP SZL THB TJS
+		TMT TND TOP TRY TTD TWD TZS UAH UGX USD USN UYI UYU UYW UZS VED VES
+		VND VUV WST XAF XAG XAU XBA XBB XBC XBD XCD XCG XDR XOF XPD XPF XPT
+		XSU XTS XUA XXX YER ZAR ZMW ZWG`) {
+		currencies[c] = true
+	}
+}
+
+// Currency validates an active ISO 4217 currency code, as in "EUR".
+// The codes are uppercase.
+func Currency(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a currency code", i)
+	}
+	if !currencies[s] {
+		return errorf(ErrNotAllowed, "%q is not an ISO 4217 currency code", s)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package validate
+
+import "testing"
+
+func TestLuhn(t *testing.T) {
+	testFormat(t, "Luhn", Luhn, map[interface{}]error{
+		"79927398713":      nil,
+		"4111111111111111": nil,
+		"00":               nil,
+		"79927398710":      ErrBadFormat,
+		"7992 7398 713":    ErrBadFormat,
+		"0":                ErrBadFormat,
+		"":                 ErrBadFormat,
+		79927398713:        ErrWrongType,
+	})
+}
+
+func TestCreditCard(t *testing.T) {
+	testFormat(t, "CreditCard", CreditCard, map[interface{}]error{
+		"4111111111111111":     nil,
+		"4111 1111 1111 1111":  nil,
+		"3782-822463-10005":    nil,
+		"4111111111111112":     ErrBadFormat,
+		"4111  1111111111111":  ErrBadFormat,
+		" 4111111111111111":    ErrBadFormat,
+		"4111111111111111-":    ErrBadFormat,
+		"4111-1111-1111-111a":  ErrBadFormat,
+		"79927398713":          ErrBadFormat,
+		"41111111111111111113": ErrBadFormat,
+		nil:                    ErrWrongType,
+	})
+}
+
+func TestIBAN(t *testing.T) {
+	testFormat(t, "IBAN", IBAN, map[interface{}]error{
+		"GB82WEST12345698765432":       nil,
+		"GB82 WEST 1234 5698 7654 32":  nil,
+		"DE89370400440532013000":       nil,
+		"NO9386011117947":              nil,
+		"GB82WEST12345698765433":       ErrBadFormat,
+		"gb82west12345698765432":       ErrBadFormat,
+		"GB82WEST1234569876543":        ErrBadFormat,
+		"GB82 WEST 12345698 7654 32":   ErrBadFormat,
+		"GB82 WEST 1234 5698 7654 32 ": ErrBadFormat,
+		"ZZ82WEST12345698765432":       ErrBadFormat,
+		"GB8":                          ErrBadFormat,
+		"GBX2WEST12345698765432":       ErrBadFormat,
+		"GB82-WEST-1234-5698-7654-32":  ErrBadFormat,
+		1:                              ErrWrongType,
+	})
+}
+
+func TestCurrency(t *testing.T) {
+	testFormat(t, "Currency", Currency, map[interface{}]error{
+		"EUR": nil,
+		"USD": nil,
+		"XAU": nil,
+		"usd": ErrNotAllowed,
+		"ABC": ErrNotAllowed,
+		"":    ErrNotAllowed,
+		978:   ErrWrongType,
+	})
+}
@@ -1,3 +1,3 @@
 module mccoy.space/g/validate
 
-go 1.19
+go 1.23
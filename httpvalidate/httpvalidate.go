@@ -0,0 +1,148 @@
+// © 2013 Steve McCoy under the MIT license.
+
+/*
+Package httpvalidate decodes and validates the JSON bodies of HTTP requests.
+
+A handler can decode a request's body into a struct and validate it in
+one step, responding to bad requests with the errors found:
+
+	var body CreateUser
+	if err := httpvalidate.DecodeAndValidate(r, vd, &body); err != nil {
+		httpvalidate.WriteError(w, err)
+		return
+	}
+
+Or it can be wrapped by Handler, which does the same:
+
+	http.Handle("/users", httpvalidate.Handler(vd, createUser))
+
+Invalid bodies are answered with 422 Unprocessable Entity, and a JSON
+object listing the invalid fields by their JSON names:
+
+	{
+		"errors": [
+			{"field": "email", "rule": "email", "error": "\"x\" is not a valid email address"}
+		]
+	}
+
+Bodies that cannot be decoded are answered with 400 Bad Request,
+and a single error naming no field.
+
+The errors are written in the locale of the request if its context
+carries a validate.Catalog, as set by Catalog.Localize, which chooses
+the locale from the request's Accept-Language header:
+
+	http.Handle("/users", catalog.Localize("en", httpvalidate.Handler(vd, createUser)))
+
+WriteProblem answers with an RFC 7807 problem details document instead.
+*/
+package httpvalidate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"mccoy.space/g/validate"
+)
+
+// Error is the error returned by DecodeAndValidate for a bad request.
+type Error struct {
+	// Status is the status of the response to the request:
+	// http.StatusBadRequest if its body cannot be decoded, or
+	// http.StatusUnprocessableEntity if the decoded body is invalid.
+	Status int
+
+	// Err is the error from decoding the body, or the validate.Errors
+	// found in it.
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Status == http.StatusBadRequest {
+		return fmt.Sprintf("cannot decode request body: %v", e.Err)
+	}
+	return fmt.Sprintf("invalid request body: %v", e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// DecodeAndValidate decodes the JSON body of r into dst, a pointer to
+// a struct, and validates it with v and the context of r, reporting fields
+// by their JSON names. Any error is an *Error, for WriteError.
+func DecodeAndValidate(r *http.Request, v validate.V, dst interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		return &Error{http.StatusBadRequest, err}
+	}
+	errs := v.ValidateOpts(dst, validate.Context(r.Context()), validate.NameTags("json"))
+	if len(errs) > 0 {
+		return &Error{http.StatusUnprocessableEntity, validate.Errors(errs)}
+	}
+	return nil
+}
+
+// Handler returns a handler that decodes and validates the body of each
+// request into a new T, as DecodeAndValidate does, and passes it to h.
+// Bad requests are answered by WriteError, without calling h.
+func Handler[T any](v validate.V, h func(w http.ResponseWriter, r *http.Request, body *T)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := new(T)
+		if err := DecodeAndValidate(r, v, body); err != nil {
+			WriteError(w, err)
+			return
+		}
+		h(w, r, body)
+	})
+}
+
+// A problem is one of the errors in a response.
+type problem struct {
+	Field string `json:"field,omitempty"`
+	Rule  string `json:"rule,omitempty"`
+	Error string `json:"error"`
+}
+
+// WriteError responds to a request with err, as described in the package
+// documentation. Errors other than *Error are answered with
+// 500 Internal Server Error, without their details. The errors found by
+// DecodeAndValidate, which validates with the request's context, are
+// in the request's locale if the context carries a validate.Catalog.
+func WriteError(w http.ResponseWriter, err error) {
+	var e *Error
+	if !errors.As(err, &e) {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	var ps []problem
+	eachProblem(e, func(field, rule, msg string) {
+		ps = append(ps, problem{field, rule, msg})
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.Status)
+	json.NewEncoder(w).Encode(struct {
+		Errors []problem `json:"errors"`
+	}{ps})
+}
+
+// eachProblem calls fn for each of the errors in e, with the field
+// and rule of the errors for invalid fields.
+func eachProblem(e *Error, fn func(field, rule, msg string)) {
+	var errs validate.Errors
+	if !errors.As(e.Err, &errs) {
+		fn("", "", e.Err.Error())
+		return
+	}
+	for _, err := range errs {
+		var bf validate.BadField
+		if errors.As(err, &bf) {
+			fn(bf.Field, bf.Rule, bf.Err.Error())
+		} else {
+			fn("", "", err.Error())
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package httpvalidate
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mccoy.space/g/validate"
+)
+
+type user struct {
+	Name  string `json:"name" validate:"nonempty"`
+	Email string `json:"email" validate:"email"`
+}
+
+func TestHandler(t *testing.T) {
+	var got *user
+	h := Handler(validate.Builtin(), func(w http.ResponseWriter, r *http.Request, u *user) {
+		got = u
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	tests := []struct {
+		body   string
+		status int
+		resp   string
+	}{
+		{`{"name": "Ann", "email": "ann@example.com"}`, http.StatusCreated, ""},
+		{`{"name": "", "email": "x"}`, http.StatusUnprocessableEntity,
+			`{"errors":[{"field":"name","rule":"nonempty","error":"should not be empty"},` +
+				`{"field":"email","rule":"email","error":"\"x\" is not a valid email address"}]}` + "\n"},
+		{`{"name": `, http.StatusBadRequest, `{"errors":[{"error":"unexpected EOF"}]}` + "\n"},
+	}
+	for _, test := range tests {
+		got = nil
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("POST", "/users", strings.NewReader(test.body)))
+		if rec.Code != test.status || rec.Body.String() != test.resp {
+			t.Fatalf("wrong response to %s: %d %s, wanted %d %s", test.body, rec.Code, rec.Body, test.status, test.resp)
+		}
+		if (got != nil) != (test.status == http.StatusCreated) {
+			t.Fatalf("handler called wrongly for %s: %v", test.body, got)
+		}
+	}
+}
+
+func TestDecodeAndValidate(t *testing.T) {
+	var u user
+	r := httptest.NewRequest("POST", "/users", strings.NewReader(`{"name": "Ann", "email": "x"}`))
+	err := DecodeAndValidate(r, validate.Builtin(), &u)
+	var e *Error
+	if !errors.As(err, &e) || e.Status != http.StatusUnprocessableEntity {
+		t.Fatalf("wrong error: %v", err)
+	}
+	var bf validate.BadField
+	if !errors.As(err, &bf) || bf.Field != "email" || !errors.Is(err, validate.ErrBadFormat) {
+		t.Fatalf("invalid field is not reachable: %v", err)
+	}
+	if u.Name != "Ann" {
+		t.Fatalf("body not decoded: %+v", u)
+	}
+
+	rec := httptest.NewRecorder()
+	WriteError(rec, errors.New("secret"))
+	if rec.Code != http.StatusInternalServerError || strings.Contains(rec.Body.String(), "secret") {
+		t.Fatalf("wrong response to another error: %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestWriteError_localized(t *testing.T) {
+	c := validate.Catalog{"fr": {"should not be empty": "ne doit pas être vide"}}
+	h := c.Localize("en", Handler(validate.Builtin(), func(w http.ResponseWriter, r *http.Request, u *user) {}))
+
+	r := httptest.NewRequest("POST", "/users", strings.NewReader(`{"name": "", "email": "a@b.c"}`))
+	r.Header.Set("Accept-Language", "fr-CH")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	want := `{"errors":[{"field":"name","rule":"nonempty","error":"ne doit pas être vide"}]}` + "\n"
+	if rec.Code != http.StatusUnprocessableEntity || rec.Body.String() != want {
+		t.Fatalf("wrong response: %d %s, wanted %s", rec.Code, rec.Body, want)
+	}
+}
@@ -0,0 +1,80 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package httpvalidate
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"mccoy.space/g/validate"
+)
+
+// Problem is an RFC 7807 problem details document,
+// served as application/problem+json.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// InvalidParams lists the invalid fields of a request.
+	InvalidParams []InvalidParam `json:"invalid-params,omitempty"`
+}
+
+// InvalidParam describes an invalid field of a request in a Problem.
+type InvalidParam struct {
+	Field     string `json:"field"`
+	Reason    string `json:"reason"`
+	Validator string `json:"validator,omitempty"`
+}
+
+// NewProblem returns a Problem, with status 422 Unprocessable Entity,
+// describing errs, as returned by validate.V's Validate.
+// Errors that are not validate.BadFields are given in its detail.
+func NewProblem(errs []error) *Problem {
+	return newProblem(&Error{http.StatusUnprocessableEntity, validate.Errors(errs)})
+}
+
+func newProblem(e *Error) *Problem {
+	p := &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(e.Status),
+		Status: e.Status,
+	}
+	eachProblem(e, func(field, rule, msg string) {
+		if field == "" {
+			if p.Detail != "" {
+				p.Detail += "; "
+			}
+			p.Detail += msg
+			return
+		}
+		p.InvalidParams = append(p.InvalidParams, InvalidParam{field, msg, rule})
+	})
+	return p
+}
+
+// Write responds to a request with p.
+func (p *Problem) Write(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// WriteProblem behaves like WriteError, but responds with a Problem
+// describing err.
+func WriteProblem(w http.ResponseWriter, err error) {
+	var e *Error
+	if !errors.As(err, &e) {
+		p := &Problem{
+			Type:   "about:blank",
+			Title:  http.StatusText(http.StatusInternalServerError),
+			Status: http.StatusInternalServerError,
+		}
+		p.Write(w)
+		return
+	}
+	newProblem(e).Write(w)
+}
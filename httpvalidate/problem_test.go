@@ -0,0 +1,50 @@
+package httpvalidate
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mccoy.space/g/validate"
+)
+
+func TestWriteProblem(t *testing.T) {
+	tests := []struct {
+		body string
+		resp string
+	}{
+		{`{"name": "", "email": "x"}`, `{"type":"about:blank","title":"Unprocessable Entity","status":422,"invalid-params":[` +
+			`{"field":"name","reason":"should not be empty","validator":"nonempty"},` +
+			`{"field":"email","reason":"\"x\" is not a valid email address","validator":"email"}]}` + "\n"},
+		{`[]`, `{"type":"about:blank","title":"Bad Request","status":400,` +
+			`"detail":"json: cannot unmarshal array into Go value of type httpvalidate.user"}` + "\n"},
+	}
+	for _, test := range tests {
+		var u user
+		r := httptest.NewRequest("POST", "/users", strings.NewReader(test.body))
+		rec := httptest.NewRecorder()
+		WriteProblem(rec, DecodeAndValidate(r, validate.Builtin(), &u))
+		if rec.Body.String() != test.resp || rec.Header().Get("Content-Type") != "application/problem+json" {
+			t.Fatalf("wrong response to %s: %s, wanted %s", test.body, rec.Body, test.resp)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	WriteProblem(rec, errors.New("secret"))
+	if rec.Code != 500 || strings.Contains(rec.Body.String(), "secret") {
+		t.Fatalf("wrong response to another error: %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestNewProblem(t *testing.T) {
+	errs := []error{
+		validate.BadField{Field: "a", Rule: "odd", Err: errors.New("is even")},
+		errors.New("validation canceled"),
+	}
+	p := NewProblem(errs)
+	if p.Status != 422 || p.Detail != "validation canceled" || len(p.InvalidParams) != 1 ||
+		p.InvalidParams[0] != (InvalidParam{"a", "is even", "odd"}) {
+		t.Fatalf("wrong problem: %+v", p)
+	}
+}
@@ -0,0 +1,71 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+var (
+	uuidRE     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	ulidRE     = regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Za-hjkmnp-tv-z]{25}$`)
+	objectIDRE = regexp.MustCompile(`^[0-9a-fA-F]{24}$`)
+)
+
+// uuid is the "uuid" builtin, which reports strings that are not UUIDs
+// of the versions given as its parameter, or of any version if it has
+// none.
+func uuid(i interface{}, versions string) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a UUID", i)
+	}
+	if !uuidRE.MatchString(s) {
+		return errorf(ErrBadFormat, "%q is not a valid UUID", s)
+	}
+	if versions == "" {
+		return nil
+	}
+	v := strings.ToLower(s[14:15])
+	for _, want := range strings.Fields(versions) {
+		if v == strings.ToLower(want) {
+			return nil
+		}
+	}
+	return errorf(ErrNotAllowed, "%q is a version %s UUID, not version %s", s, v, strings.Join(strings.Fields(versions), " or "))
+}
+
+// uuidParam is the parameter check of "uuid".
+func uuidParam(t reflect.Type, versions string) error {
+	for _, v := range strings.Fields(versions) {
+		if len(v) != 1 || !strings.Contains("0123456789abcdefABCDEF", v) {
+			return fmt.Errorf("parameter %q is not a list of UUID versions, as in \"4 7\"", versions)
+		}
+	}
+	return stringType(t)
+}
+
+func ulid(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a ULID", i)
+	}
+	if !ulidRE.MatchString(s) {
+		return errorf(ErrBadFormat, "%q is not a valid ULID", s)
+	}
+	return nil
+}
+
+func objectID(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not an ObjectID", i)
+	}
+	if !objectIDRE.MatchString(s) {
+		return errorf(ErrBadFormat, "%q is not a valid ObjectID", s)
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxEnum is the most distinct values a field may have
+// to be proposed as an enumeration.
+const maxEnum = 8
+
+// FieldStats summarizes the values a field held across a set of samples.
+type FieldStats struct {
+	Field string // the field's path, with names joined by dots
+	Key   string // the path of its key in JSON documents, or "" if it has none
+	Kind  reflect.Kind
+	Count int // the number of samples
+	Zeros int // the number of samples in which the field was its zero value
+
+	// For strings, slices, arrays, and maps, MinLen and MaxLen are the
+	// shortest and longest lengths seen; strings are measured in runes.
+	MinLen, MaxLen int
+
+	// For numbers, Min and Max are the least and greatest values seen.
+	Min, Max float64
+
+	// Values holds the distinct non-empty strings and integers seen,
+	// sorted, unless there were more than a few of them.
+	Values []string
+	many   bool
+}
+
+// Tag proposes a validate tag describing the values in f:
+// "nonzero" if the field was never its zero value,
+// "minlen=" and "maxlen=" for the range of lengths,
+// "gte=" and "lte=" for the range of numbers,
+// and "oneof=" if only a few distinct values were seen, each more than once
+// on average. It is a starting point, to be reviewed by a person.
+func (f FieldStats) Tag() string {
+	var rules []string
+	if f.Count > 0 && f.Zeros == 0 {
+		rules = append(rules, "nonzero")
+	}
+	switch f.Kind {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		rules = append(rules, fmt.Sprintf("minlen=%d", f.MinLen), fmt.Sprintf("maxlen=%d", f.MaxLen))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		rules = append(rules, "gte="+formatFloat(f.Min), "lte="+formatFloat(f.Max))
+	}
+	if !f.many && len(f.Values) > 0 && f.Count >= 2*len(f.Values) {
+		rules = append(rules, "oneof="+strings.Join(f.Values, " "))
+	}
+	return strings.Join(rules, ",")
+}
+
+func formatFloat(x float64) string {
+	return strconv.FormatFloat(x, 'g', -1, 64)
+}
+
+// Infer summarizes the fields of samples, which must be a slice or array
+// of structs (or pointers to them) known to be valid, so that rules can be
+// proposed for a type that has none. The exported fields of nested structs
+// are included, with their names joined by dots. Fields are listed in the
+// order they are declared.
+func Infer(samples interface{}) ([]FieldStats, error) {
+	val := reflect.ValueOf(samples)
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return nil, fmt.Errorf("cannot infer rules from %T, which is not a slice", samples)
+	}
+
+	et := val.Type().Elem()
+	if et.Kind() == reflect.Ptr {
+		et = et.Elem()
+	}
+	if et.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot infer rules for %v, which is not a struct", et)
+	}
+
+	var stats []*FieldStats
+	index := make(map[string]*FieldStats)
+	for i := 0; i < val.Len(); i++ {
+		s := val.Index(i)
+		if s.Kind() == reflect.Ptr {
+			if s.IsNil() {
+				continue
+			}
+			s = s.Elem()
+		}
+		observe(s, "", "", true, index, &stats)
+	}
+
+	fs := make([]FieldStats, len(stats))
+	for i, s := range stats {
+		fs[i] = *s
+	}
+	return fs, nil
+}
+
+// InferManifest proposes a tag, as for FieldStats.Tag, for each field of
+// samples, as described for Infer. The result is a manifest for the
+// validate command, so the fields are named by the paths of their keys in
+// JSON documents, as encoding/json names them, and those it ignores are
+// omitted.
+func InferManifest(samples interface{}) (map[string]string, error) {
+	stats, err := Infer(samples)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]string, len(stats))
+	for _, s := range stats {
+		if tag := s.Tag(); tag != "" && s.Key != "" {
+			m[s.Key] = tag
+		}
+	}
+	return m, nil
+}
+
+// observe adds the fields of s to the stats, under the path prefix and,
+// if keyed, the path of s's key in JSON documents, keyPrefix.
+func observe(s reflect.Value, prefix, keyPrefix string, keyed bool, index map[string]*FieldStats, stats *[]*FieldStats) {
+	t := s.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := f.Name
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		fv := s.Field(i)
+
+		// The fields of an embedded struct without a JSON name are
+		// promoted to the struct embedding it.
+		tag := f.Tag.Get("json")
+		key, fkeyed := TagName(tag), keyed && tag != "-"
+		switch {
+		case key == "" && f.Anonymous && fv.Kind() == reflect.Struct:
+			key = keyPrefix
+		default:
+			if key == "" {
+				key = f.Name
+			}
+			if keyPrefix != "" {
+				key = keyPrefix + "." + key
+			}
+		}
+
+		if fv.Kind() == reflect.Struct {
+			observe(fv, name, key, fkeyed, index, stats)
+			continue
+		}
+
+		st := index[name]
+		if st == nil {
+			st = &FieldStats{Field: name, Kind: fv.Kind()}
+			if fkeyed {
+				st.Key = key
+			}
+			index[name] = st
+			*stats = append(*stats, st)
+		}
+		st.add(fv)
+	}
+}
+
+func (f *FieldStats) add(v reflect.Value) {
+	first := f.Count == 0
+	f.Count++
+	if v.IsZero() {
+		f.Zeros++
+	}
+
+	n, hasLen := 0, true
+	switch v.Kind() {
+	case reflect.String:
+		n = utf8.RuneCountInString(v.String())
+	case reflect.Slice, reflect.Array, reflect.Map:
+		n = v.Len()
+	default:
+		hasLen = false
+	}
+	if hasLen {
+		if first || n < f.MinLen {
+			f.MinLen = n
+		}
+		if first || n > f.MaxLen {
+			f.MaxLen = n
+		}
+	}
+
+	x, isNum := 0.0, true
+	var s string
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x = float64(v.Int())
+		s = strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		x = float64(v.Uint())
+		s = strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		x = v.Float()
+	case reflect.String:
+		isNum = false
+		s = v.String()
+	default:
+		isNum = false
+	}
+	if isNum {
+		if first || x < f.Min {
+			f.Min = x
+		}
+		if first || x > f.Max {
+			f.Max = x
+		}
+	}
+
+	if s == "" || f.many {
+		return
+	}
+	i := sort.SearchStrings(f.Values, s)
+	if i < len(f.Values) && f.Values[i] == s {
+		return
+	}
+	// Values that could not be written in a tag are not enumerated.
+	if len(f.Values) == maxEnum || strings.ContainsAny(s, " ,|=") {
+		f.many, f.Values = true, nil
+		return
+	}
+	f.Values = append(f.Values, "")
+	copy(f.Values[i+1:], f.Values[i:])
+	f.Values[i] = s
+}
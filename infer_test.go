@@ -0,0 +1,63 @@
+package validate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInferManifest(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+	type Audit struct {
+		By string `json:"by"`
+	}
+	type X struct {
+		Name  string `json:"name"`
+		Color string
+		Age   int
+		Score float64 `json:"score,omitempty"`
+		Tags  []string
+		Home  Address `json:"home"`
+		Audit
+		Secret  string `json:"-"`
+		Op      string
+		private int
+	}
+
+	samples := []*X{
+		{Name: "Zoë", Color: "red", Age: 30, Score: 1.5, Home: Address{"Paris"}, Audit: Audit{"a"}, Secret: "s", Op: "a|b"},
+		{Name: "Al", Color: "blue", Age: 41, Score: 0, Tags: []string{"a"}, Home: Address{"Oslo"}, Audit: Audit{"a"}, Secret: "s", Op: "a=b"},
+		{Name: "Bea", Color: "red", Age: 18, Score: -2, Tags: []string{"a", "b"}, Home: Address{"Rome"}, Audit: Audit{"a"}, Secret: "s", Op: "a|b"},
+		nil,
+		{Name: "Cy", Color: "blue", Age: 18, Score: 3, Home: Address{"Lima"}, Audit: Audit{"a"}, Secret: "s", Op: "a=b"},
+	}
+
+	m, err := InferManifest(samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"name":      "nonzero,minlen=2,maxlen=3",
+		"Color":     "nonzero,minlen=3,maxlen=4,oneof=blue red",
+		"Age":       "nonzero,gte=18,lte=41",
+		"score":     "gte=-2,lte=3",
+		"Tags":      "minlen=0,maxlen=2",
+		"home.city": "nonzero,minlen=4,maxlen=5",
+		"by":        "nonzero,minlen=1,maxlen=1,oneof=a",
+		"Op":        "nonzero,minlen=3,maxlen=3",
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Fatalf("wrong manifest:\n%v\nwanted:\n%v", m, want)
+	}
+}
+
+func TestInfer_errors(t *testing.T) {
+	if _, err := Infer(7); err == nil {
+		t.Fatal("no error for a non-slice")
+	}
+	if _, err := Infer([]int{7}); err == nil {
+		t.Fatal("no error for a slice of non-structs")
+	}
+}
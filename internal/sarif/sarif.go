@@ -0,0 +1,104 @@
+// © 2013 Steve McCoy under the MIT license.
+
+// Package sarif holds the subset of SARIF 2.1.0 that the commands need to
+// report findings to code scanning tools.
+// See https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+package sarif
+
+import "sort"
+
+// Log is a SARIF log.
+type Log struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+// Run is the results of a single run of a tool.
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+type Driver struct {
+	Name           string `json:"name"`
+	InformationURI string `json:"informationUri"`
+	Rules          []Rule `json:"rules"`
+}
+
+type Rule struct {
+	ID string `json:"id"`
+}
+
+// Result is a single finding, of the rule with ID RuleID.
+type Result struct {
+	RuleID    string     `json:"ruleId"`
+	Level     string     `json:"level"`
+	Message   Message    `json:"message"`
+	Locations []Location `json:"locations"`
+}
+
+type Message struct {
+	Text string `json:"text"`
+}
+
+// Location is where a result was found: in a file, and in it,
+// optionally, at a line and column or in a named part.
+type Location struct {
+	PhysicalLocation PhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []LogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           *Region          `json:"region,omitempty"`
+}
+
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type Region struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+type LogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// New returns a log of a single run of the named tool, with the given results.
+// The run's rules are those of the results, sorted by ID.
+func New(tool string, results []Result) Log {
+	ids := make(map[string]bool)
+	rules := make([]Rule, 0)
+	for _, r := range results {
+		if !ids[r.RuleID] {
+			ids[r.RuleID] = true
+			rules = append(rules, Rule{r.RuleID})
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID < rules[j].ID
+	})
+
+	if results == nil {
+		results = make([]Result, 0)
+	}
+	return Log{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []Run{{
+			Tool: Tool{Driver{
+				Name:           tool,
+				InformationURI: "https://mccoy.space/g/validate",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+}
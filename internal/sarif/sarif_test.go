@@ -0,0 +1,34 @@
+package sarif
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	log := New("tool", []Result{
+		{RuleID: "b", Level: "error", Message: Message{"one"}},
+		{RuleID: "a", Level: "error", Message: Message{"two"}},
+		{RuleID: "b", Level: "error", Message: Message{"three"}},
+	})
+	rules := log.Runs[0].Tool.Driver.Rules
+	if len(rules) != 2 || rules[0].ID != "a" || rules[1].ID != "b" {
+		t.Fatalf("wrong rules: %+v", rules)
+	}
+	if log.Runs[0].Tool.Driver.Name != "tool" || len(log.Runs[0].Results) != 3 {
+		t.Fatalf("wrong run: %+v", log.Runs[0])
+	}
+
+	b, _ := json.Marshal(New("tool", nil))
+	want := `{"$schema":"https://json.schemastore.org/sarif-2.1.0.json","version":"2.1.0","runs":[{"tool":{"driver":{"name":"tool","informationUri":"https://mccoy.space/g/validate","rules":[]}},"results":[]}]}`
+	if string(b) != want {
+		t.Fatalf("wrong empty log:\n%s\nwanted:\n%s", b, want)
+	}
+}
+
+func TestLocation(t *testing.T) {
+	b, _ := json.Marshal(Location{PhysicalLocation: PhysicalLocation{ArtifactLocation: ArtifactLocation{"a.go"}}})
+	if want := `{"physicalLocation":{"artifactLocation":{"uri":"a.go"}}}`; string(b) != want {
+		t.Fatalf("wrong location without a region:\n%s\nwanted:\n%s", b, want)
+	}
+}
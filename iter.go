@@ -0,0 +1,27 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"iter"
+	"reflect"
+)
+
+// Iter returns a sequence of the errors Validate would return for s,
+// finding each only as the sequence is consumed. Breaking out of the loop
+// stops validation, so callers that need only the first few errors,
+// or whether there are any, avoid validating the rest of s:
+//
+//	for bf := range v.Iter(x) {
+//		return bf
+//	}
+func (v V) Iter(s interface{}, opts ...Option) iter.Seq[BadField] {
+	return func(yield func(BadField) bool) {
+		w := walker{v: v}
+		for _, o := range opts {
+			o(&w)
+		}
+		w.yield = yield
+		w.validate(reflect.ValueOf(s), nil)
+	}
+}
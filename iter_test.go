@@ -0,0 +1,43 @@
+package validate
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestV_Iter(t *testing.T) {
+	type X struct {
+		A int `validate:"odd"`
+		B int `validate:"odd,odd"`
+		C int `validate:"odd"`
+	}
+
+	calls := 0
+	vd := make(V)
+	vd["odd"] = func(i interface{}) error {
+		calls++
+		return fmt.Errorf("not odd")
+	}
+
+	var fields []string
+	for bf := range vd.Iter(X{}) {
+		fields = append(fields, bf.Field)
+		if len(fields) == 2 {
+			break
+		}
+	}
+	if fmt.Sprint(fields) != "[A B]" {
+		t.Fatalf("wrong errors from Iter: %v", fields)
+	}
+	if calls != 2 {
+		t.Fatalf("validation continued after break: %d calls", calls)
+	}
+
+	n := 0
+	for range vd.Iter(X{}, NameTags("json")) {
+		n++
+	}
+	if n != 4 {
+		t.Fatalf("wrong number of errors from Iter: %d", n)
+	}
+}
@@ -0,0 +1,780 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// JSONSchema is the subset of JSON Schema understood by this package.
+type JSONSchema struct {
+	Schema string `json:"$schema,omitempty"`
+	Ref    string `json:"$ref,omitempty"`
+
+	Type                 interface{}            `json:"type,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	PropertyNames        *JSONSchema            `json:"propertyNames,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+	AnyOf                []*JSONSchema          `json:"anyOf,omitempty"`
+
+	MinLength *int   `json:"minLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+	Format    string `json:"format,omitempty"`
+
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+
+	MinItems    *int `json:"minItems,omitempty"`
+	MaxItems    *int `json:"maxItems,omitempty"`
+	UniqueItems bool `json:"uniqueItems,omitempty"`
+
+	Enum []interface{} `json:"enum,omitempty"`
+}
+
+// UnmarshalJSON decodes a JSON Schema into s. It accepts exclusiveMinimum
+// and exclusiveMaximum as booleans, as in OpenAPI 3.0, where they make
+// minimum and maximum exclusive, and as numbers, as in later drafts.
+func (s *JSONSchema) UnmarshalJSON(data []byte) error {
+	type schema JSONSchema
+	var aux struct {
+		*schema
+		ExclusiveMinimum json.RawMessage `json:"exclusiveMinimum"`
+		ExclusiveMaximum json.RawMessage `json:"exclusiveMaximum"`
+	}
+	aux.schema = (*schema)(s)
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	var err error
+	s.ExclusiveMinimum, s.Minimum, err = exclusiveBound(aux.ExclusiveMinimum, s.Minimum)
+	if err != nil {
+		return fmt.Errorf("exclusiveMinimum: %v", err)
+	}
+	s.ExclusiveMaximum, s.Maximum, err = exclusiveBound(aux.ExclusiveMaximum, s.Maximum)
+	if err != nil {
+		return fmt.Errorf("exclusiveMaximum: %v", err)
+	}
+	return nil
+}
+
+// exclusiveBound returns the exclusive and inclusive bounds given by raw,
+// an exclusiveMinimum or exclusiveMaximum, and bound, the corresponding
+// minimum or maximum.
+func exclusiveBound(raw json.RawMessage, bound *float64) (*float64, *float64, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, bound, nil
+	}
+	var exclusive bool
+	if err := json.Unmarshal(raw, &exclusive); err == nil {
+		if exclusive {
+			return bound, nil, nil
+		}
+		return nil, bound, nil
+	}
+	var n float64
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return nil, nil, err
+	}
+	return &n, bound, nil
+}
+
+// ImportSchema reads a JSON Schema for an object, such as an OpenAPI
+// component schema, and returns validators enforcing the constraints on its
+// properties, with the rules binding them to the fields of a struct by their
+// JSON names. They are used together with the Rules and NameTags options:
+//
+//	sv, rules, err := validate.ImportSchema(spec)
+//	…
+//	errs := vd.WithOverlay(sv).ValidateOpts(x, validate.NameTags("json"), validate.Rules(rules))
+//
+// The constraints understood are required, minLength, maxLength, pattern,
+// minimum, maximum, exclusiveMinimum, exclusiveMaximum, minItems, maxItems,
+// and enum, and the properties of nested objects. The boolean
+// exclusiveMinimum and exclusiveMaximum of OpenAPI 3.0 are understood as
+// well as the numbers of later drafts. References are not followed.
+//
+// A required property is given the reserved "present" rule, so it may
+// hold a zero value, such as 0, but must not be absent: its field must not
+// be a nil pointer, interface, slice, or map, or an Optional holding no
+// value, and is reported even when nil pointers are skipped. Fields of
+// other types, such as int, are never absent, so required properties
+// should be bound to pointers or Optionals. The other constraints are
+// checked for every value present, including zero values, so optional
+// properties should be pointers or Optionals as well.
+//
+// Each property's other constraints are checked by a single validator
+// named "schema." followed by the property's path, in which the
+// characters of the tag syntax, ",", "|", "=", and ":", are escaped as
+// in URLs, as "%2C" and the like.
+func ImportSchema(data []byte) (V, map[string]string, error) {
+	var root JSONSchema
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, nil, err
+	}
+	if root.Properties == nil {
+		return nil, nil, errors.New("schema has no properties")
+	}
+
+	v := make(V)
+	rules := make(map[string]string)
+	if err := importProperties(&root, "", v, rules); err != nil {
+		return nil, nil, err
+	}
+	return v, rules, nil
+}
+
+func importProperties(s *JSONSchema, prefix string, v V, rules map[string]string) error {
+	required := make(map[string]bool, len(s.Required))
+	for _, name := range s.Required {
+		required[name] = true
+	}
+
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		p := s.Properties[name]
+		path := prefix + name
+		if p.Pattern != "" {
+			if _, err := Regexps.Compile(p.Pattern); err != nil {
+				return fmt.Errorf("property %s: %v", path, err)
+			}
+		}
+
+		rule := "schema." + ruleEscaper.Replace(path)
+		v[rule] = schemaValidator(p)
+		rules[path] = rule
+		if required[name] {
+			rules[path] = "present," + rule
+		}
+		if p.Properties != nil {
+			rules[path] += ",struct"
+			if err := importProperties(p, path+".", v, rules); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ruleEscaper escapes the characters of the tag syntax in the names of
+// the rules made by ImportSchema.
+var ruleEscaper = strings.NewReplacer("%", "%25", ",", "%2C", "|", "%7C", "=", "%3D", ":", "%3A")
+
+// schemaValidator returns a validator checking the constraints of s,
+// other than required, for values that are present: not nil.
+func schemaValidator(s *JSONSchema) func(interface{}) error {
+	return func(i interface{}) error {
+		val := reflect.ValueOf(i)
+		for val.Kind() == reflect.Ptr && !val.IsNil() {
+			val = val.Elem()
+		}
+		switch val.Kind() {
+		case reflect.Invalid:
+			return nil
+		case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+			if val.IsNil() {
+				return nil
+			}
+		}
+
+		switch val.Kind() {
+		case reflect.String:
+			str := val.String()
+			n := utf8.RuneCountInString(str)
+			if s.MinLength != nil && n < *s.MinLength {
+				return errorf(ErrTooShort, "length %d is less than %d", n, *s.MinLength)
+			}
+			if s.MaxLength != nil && n > *s.MaxLength {
+				return errorf(ErrTooLong, "length %d is greater than %d", n, *s.MaxLength)
+			}
+			if s.Pattern != "" {
+				re, _ := Regexps.Compile(s.Pattern)
+				if !re.MatchString(str) {
+					return errorf(ErrBadFormat, "%q does not match %q", str, s.Pattern)
+				}
+			}
+		case reflect.Slice, reflect.Array:
+			n := val.Len()
+			if s.MinItems != nil && n < *s.MinItems {
+				return errorf(ErrTooShort, "has %d items, fewer than %d", n, *s.MinItems)
+			}
+			if s.MaxItems != nil && n > *s.MaxItems {
+				return errorf(ErrTooLong, "has %d items, more than %d", n, *s.MaxItems)
+			}
+		}
+
+		if x, ok := toFloat(val); ok {
+			switch {
+			case s.Minimum != nil && x < *s.Minimum:
+				return errorf(ErrOutOfRange, "%v is less than %v", x, *s.Minimum)
+			case s.Maximum != nil && x > *s.Maximum:
+				return errorf(ErrOutOfRange, "%v is greater than %v", x, *s.Maximum)
+			case s.ExclusiveMinimum != nil && x <= *s.ExclusiveMinimum:
+				return errorf(ErrOutOfRange, "%v is not greater than %v", x, *s.ExclusiveMinimum)
+			case s.ExclusiveMaximum != nil && x >= *s.ExclusiveMaximum:
+				return errorf(ErrOutOfRange, "%v is not less than %v", x, *s.ExclusiveMaximum)
+			}
+		}
+
+		if len(s.Enum) > 0 {
+			for _, e := range s.Enum {
+				if jsonEqual(val, e) {
+					return nil
+				}
+			}
+			return errorf(ErrNotAllowed, "%v is not one of %v", val, s.Enum)
+		}
+		return nil
+	}
+}
+
+// SchemaRules maps the names of validators to functions adding the
+// constraints they enforce to the JSON Schema of a value, for SchemaFor and
+// OpenAPISchema. The schema's type is set before the function is called.
+// It holds the validators of Builtin that JSON Schema can express,
+// and users may add their own, in an init function:
+//
+//	func init() {
+//		validate.SchemaRules["min"] = func(r validate.Rule, s *validate.JSONSchema) {
+//			min, _ := strconv.ParseFloat(r.Param, 64)
+//			s.Minimum = &min
+//		}
+//	}
+//
+// SchemaRules is read without synchronization, so it must not be changed
+// once schemas may be made, as Vs must not be changed while in use.
+var SchemaRules = map[string]func(r Rule, s *JSONSchema){
+	"nonzero":  nonemptySchema,
+	"nonempty": nonemptySchema,
+	"email":    formatSchema("email"),
+	"url":      formatSchema("uri"),
+	"uuid":     formatSchema("uuid"),
+	"password": formatSchema("password"),
+	"ulid":     patternSchema(ulidRE.String()),
+	"objectid": patternSchema(objectIDRE.String()),
+	"currency": patternSchema(`^[A-Z]{3}$`),
+	"country":  patternSchema(`^[A-Z]{2}$`),
+	"hex":      patternSchema(`^([0-9a-fA-F]{2})*$`),
+	"alpha":    patternSchema(`^\p{L}+$`),
+	"numeric":  patternSchema(numericRE.String()),
+	"unique": func(r Rule, s *JSONSchema) {
+		s.UniqueItems = true
+	},
+	"gt":  boundSchema(func(s *JSONSchema, n *float64) { s.ExclusiveMinimum = n }),
+	"gte": boundSchema(func(s *JSONSchema, n *float64) { s.Minimum = n }),
+	"lt":  boundSchema(func(s *JSONSchema, n *float64) { s.ExclusiveMaximum = n }),
+	"lte": boundSchema(func(s *JSONSchema, n *float64) { s.Maximum = n }),
+	"len": func(r Rule, s *JSONSchema) {
+		lengthSchema(r, s, true, true)
+	},
+	"minlen": func(r Rule, s *JSONSchema) {
+		lengthSchema(r, s, true, false)
+	},
+	"maxlen": func(r Rule, s *JSONSchema) {
+		lengthSchema(r, s, false, true)
+	},
+	"oneof": enumSchema,
+	"dateformat": func(r Rule, s *JSONSchema) {
+		switch r.Param {
+		case time.RFC3339:
+			s.Format = "date-time"
+		case time.DateOnly:
+			s.Format = "date"
+		case time.TimeOnly:
+			s.Format = "time"
+		}
+	},
+	"regexp": func(r Rule, s *JSONSchema) {
+		s.Pattern = r.Param
+	},
+	"between": func(r Rule, s *JSONSchema) {
+		p := r.Params()
+		if len(p) != 2 {
+			return
+		}
+		lo, loErr := strconv.ParseFloat(p[0], 64)
+		hi, hiErr := strconv.ParseFloat(p[1], 64)
+		if loErr == nil && hiErr == nil {
+			s.Minimum, s.Maximum = &lo, &hi
+		}
+	},
+}
+
+// lengthSchema sets the minimum, maximum, or both lengths of s to the
+// length given to r, unless it is a length in bytes, which JSON Schema
+// cannot express.
+func lengthSchema(r Rule, s *JSONSchema, min, max bool) {
+	p := r.Params()
+	if len(p) == 0 || len(p) > 2 || len(p) == 2 && p[1] != "runes" {
+		return
+	}
+	n, err := strconv.Atoi(p[0])
+	if err != nil {
+		return
+	}
+	switch s.Type {
+	case "string":
+		if min {
+			s.MinLength = &n
+		}
+		if max {
+			s.MaxLength = &n
+		}
+	case "array":
+		if min {
+			s.MinItems = &n
+		}
+		if max {
+			s.MaxItems = &n
+		}
+	}
+}
+
+// boundSchema returns a function passing set the number given to a rule,
+// if it is one, rather than a duration.
+func boundSchema(set func(s *JSONSchema, n *float64)) func(Rule, *JSONSchema) {
+	return func(r Rule, s *JSONSchema) {
+		if n, err := strconv.ParseFloat(r.Param, 64); err == nil {
+			set(s, &n)
+		}
+	}
+}
+
+func nonemptySchema(r Rule, s *JSONSchema) {
+	one := 1
+	switch s.Type {
+	case "string":
+		s.MinLength = &one
+	case "array":
+		s.MinItems = &one
+	}
+}
+
+func formatSchema(format string) func(Rule, *JSONSchema) {
+	return func(r Rule, s *JSONSchema) {
+		s.Format = format
+	}
+}
+
+func patternSchema(pattern string) func(Rule, *JSONSchema) {
+	return func(r Rule, s *JSONSchema) {
+		s.Pattern = pattern
+	}
+}
+
+// SchemaFor returns a JSON Schema (draft 2020-12) describing the struct x,
+// or the struct it points to, as it is validated by v, so that the rules in
+// its tags can be published to the clients of an API. Properties are named
+// by the fields' json tags, and fields tagged json:"-" are left out. The
+// fields of embedded structs are promoted, with encoding/json's rules for
+// tags and for fields of the same name, and their rules are described if
+// the embedded structs have "struct" rules.
+//
+// The schema reflects the rules of SchemaRules, "required", alternatives,
+// and the elements, keys, and values of collections. The fields of structs
+// are described for "struct" rules, with a reference to the enclosing
+// schema for a struct within itself. Pointers that are not required may
+// be null, and the zero values of strings, numbers, booleans, and times
+// are allowed in place of the constraints of rules following "omitempty".
+// Aliases in v are expanded, but other rules cannot be expressed and are
+// left out, as are those scoped to groups.
+func SchemaFor(x interface{}, v V) (*JSONSchema, error) {
+	s, err := describeStruct(x, v, "#")
+	if err != nil {
+		return nil, err
+	}
+	s.Schema = "https://json-schema.org/draft/2020-12/schema"
+	return s, nil
+}
+
+// describeStruct returns the schema of the struct x, which is at base.
+func describeStruct(x interface{}, v V, base string) (*JSONSchema, error) {
+	t := reflect.TypeOf(x)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot describe %T, which is not a struct", x)
+	}
+
+	d := describer{
+		w:     &walker{v: v, nameTags: []string{"json"}},
+		outer: make(map[reflect.Type]string),
+	}
+	return d.object(t, base)
+}
+
+// A describer builds JSON Schemas for SchemaFor.
+type describer struct {
+	w     *walker
+	outer map[reflect.Type]string // the JSON pointers to the structs being described
+}
+
+// object returns the schema of the struct type t, which is at ptr.
+func (d *describer) object(t reflect.Type, ptr string) (*JSONSchema, error) {
+	if p, ok := d.outer[t]; ok {
+		return &JSONSchema{Ref: p}, nil
+	}
+	d.outer[t] = ptr
+	defer delete(d.outer, t)
+
+	fields, err := d.jsonFields(t)
+	if err != nil {
+		return nil, err
+	}
+	s := &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema)}
+	for _, jf := range fields {
+		name, fi := jf.name, jf.fieldInfo
+		var rules []Rule
+		if jf.validated {
+			rules, err = fi.rules, fi.err
+			if err == nil {
+				rules, err = d.w.expand(rules, nil)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", name, err)
+		}
+		p, required, err := d.describe(fi.field.Type, rules, ptr+"/properties/"+pointerEscaper.Replace(name))
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", name, err)
+		}
+		s.Properties[name] = p
+		if required {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return s, nil
+}
+
+// A jsonField is a field of a struct type as encoding/json sees it.
+type jsonField struct {
+	fieldInfo
+	name      string
+	tagged    bool  // whether name is given by a json tag
+	index     []int // the field's index sequence, as for FieldByIndex
+	validated bool  // whether Validate applies the field's rules
+}
+
+// jsonFields returns the fields of the struct type t that encoding/json
+// encodes, in the same order. Like encoding/json, it promotes the fields
+// of embedded structs that are not named by json tags, and of the fields
+// of the same name keeps only the least deeply embedded, or the only one
+// of those tagged with the name; if there is no such field, it keeps
+// none of them. The rules of promoted fields apply only if the structs
+// embedding them have "struct" rules.
+func (d *describer) jsonFields(t reflect.Type) ([]jsonField, error) {
+	type embedded struct {
+		t         reflect.Type
+		index     []int
+		validated bool
+	}
+	var fields []jsonField
+	visited := map[reflect.Type]bool{t: true}
+	next := []embedded{{t, nil, true}}
+	for len(next) > 0 {
+		current := next
+		next = nil
+		count := make(map[reflect.Type]int)
+		for _, e := range current {
+			count[e.t]++
+		}
+		for _, e := range current {
+			infos := make(map[int]fieldInfo)
+			for _, fi := range d.w.fields(e.t) {
+				infos[fi.index] = fi
+			}
+			for i := 0; i < e.t.NumField(); i++ {
+				f := e.t.Field(i)
+				tag := f.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				index := append(e.index[:len(e.index):len(e.index)], i)
+				ft := f.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				name := TagName(tag)
+				if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+					fi := infos[i]
+					rules, err := fi.rules, fi.err
+					if err == nil {
+						rules, err = d.w.expand(rules, nil)
+					}
+					if err != nil && e.validated {
+						return nil, fmt.Errorf("field %s: %v", f.Name, err)
+					}
+					validated := false
+					for _, r := range rules {
+						validated = validated || r.Name == "struct"
+					}
+					if !visited[ft] {
+						visited[ft] = true
+						next = append(next, embedded{ft, index, e.validated && validated})
+					}
+					continue
+				}
+				fi, ok := infos[i]
+				if !ok {
+					continue
+				}
+				jf := jsonField{fi, d.w.fieldName(f), name != "", index, e.validated}
+				fields = append(fields, jf)
+				if count[e.t] > 1 {
+					// A struct embedded more than once at the same depth
+					// has fields of ambiguous names, which are all dropped.
+					fields = append(fields, jf)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		a, b := fields[i], fields[j]
+		if a.name != b.name {
+			return a.name < b.name
+		}
+		if len(a.index) != len(b.index) {
+			return len(a.index) < len(b.index)
+		}
+		return a.tagged && !b.tagged
+	})
+	kept := fields[:0]
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		// fields[i] dominates unless another as deep is tagged as well.
+		if j == i+1 || len(fields[i+1].index) > len(fields[i].index) || fields[i].tagged && !fields[i+1].tagged {
+			kept = append(kept, fields[i])
+		}
+		i = j
+	}
+	sort.Slice(kept, func(i, j int) bool {
+		return slices.Compare(kept[i].index, kept[j].index) < 0
+	})
+	return kept, nil
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// describe returns the schema of a value of type t checked against rules,
+// which is at ptr, and whether the value is required.
+//
+// A pointer that may be nil, not being required, may be null.
+// Rules following "omitempty"
+// are not applied to the zero values of strings, numbers, booleans, and
+// times, which are allowed as an alternative to their constraints.
+func (d *describer) describe(t reflect.Type, rules []Rule, ptr string) (*JSONSchema, bool, error) {
+	nullable := t.Kind() == reflect.Ptr
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	s := typeSchema(t)
+	defer func() {
+		switch {
+		case !nullable:
+		case s.Ref != "":
+			*s = JSONSchema{AnyOf: []*JSONSchema{{Ref: s.Ref, Type: s.Type}, {Type: "null"}}}
+		default:
+			if s.Type != nil {
+				s.Type = []interface{}{s.Type, "null"}
+			}
+			if len(s.Enum) > 0 {
+				s.Enum = append(s.Enum, nil)
+			}
+			if len(s.AnyOf) > 0 {
+				s.AnyOf = append(s.AnyOf, &JSONSchema{Type: "null"})
+			}
+		}
+	}()
+	required, active := false, true
+	for k := 0; k < len(rules); k++ {
+		r := rules[k]
+		if r.Or {
+			continue
+		}
+		n := 1
+		for k+n < len(rules) && rules[k+n].Or {
+			n++
+		}
+
+		switch {
+		case r.Name == "on":
+			active = len(r.Params()) == 0
+		case !active:
+		case n > 1:
+			// An alternative that cannot be expressed may accept
+			// anything, so the others constrain nothing.
+			var alts []*JSONSchema
+			for _, a := range rules[k : k+n] {
+				fn := SchemaRules[a.Name]
+				if fn == nil {
+					alts = nil
+					break
+				}
+				as := &JSONSchema{Type: s.Type}
+				fn(a, as)
+				as.Type = nil
+				alts = append(alts, as)
+			}
+			s.AnyOf = append(s.AnyOf, alts...)
+		case r.Name == "required" || r.Name == "present":
+			required, nullable = true, false
+		case r.Name == "omitempty":
+			zero, ok := zeroJSON(t)
+			if nullable || !ok {
+				break
+			}
+			rest, _, err := d.describe(t, rules[k+1:], ptr)
+			if err != nil {
+				return nil, false, err
+			}
+			rest.Type = nil
+			if !reflect.ValueOf(*rest).IsZero() && len(s.AnyOf) == 0 {
+				s.AnyOf = []*JSONSchema{{Enum: []interface{}{zero}}, rest}
+			}
+			return s, required, nil
+		case r.Name == "struct":
+			if t.Kind() != reflect.Struct {
+				break
+			}
+			o, err := d.object(t, ptr)
+			if err != nil {
+				return nil, false, err
+			}
+			s.Ref, s.Properties, s.Required = o.Ref, o.Properties, o.Required
+		case r.Name == "each" || r.Name == "keys" || r.Name == "values":
+			erules, err := rules[k+1:], error(nil)
+			if r.Param != "" {
+				erules, err = ParseTag(r.Param)
+			}
+			if err == nil {
+				erules, err = d.w.expand(erules, nil)
+			}
+			if err != nil {
+				return nil, false, err
+			}
+			var es **JSONSchema
+			var et reflect.Type
+			key := ""
+			switch {
+			case r.Name == "each" && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array):
+				es, et, key = &s.Items, t.Elem(), "items"
+			case r.Name == "keys" && t.Kind() == reflect.Map:
+				es, et, key = &s.PropertyNames, t.Key(), "propertyNames"
+			case r.Name == "values" && t.Kind() == reflect.Map:
+				es, et, key = &s.AdditionalProperties, t.Elem(), "additionalProperties"
+			}
+			if es != nil {
+				if *es, _, err = d.describe(et, erules, ptr+"/"+key); err != nil {
+					return nil, false, err
+				}
+			}
+			if r.Param == "" {
+				k = len(rules)
+			}
+		default:
+			if fn := SchemaRules[r.Name]; fn != nil {
+				fn(r, s)
+			}
+		}
+		k += n - 1
+	}
+	return s, required, nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// zeroJSON returns the JSON encoding of the zero value of t, if it is
+// a string, number, or boolean.
+func zeroJSON(t reflect.Type) (interface{}, bool) {
+	if t == timeType {
+		return time.Time{}.Format(time.RFC3339Nano), true
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "", true
+	case reflect.Bool:
+		return false, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return 0, true
+	}
+	return nil, false
+}
+
+// typeSchema returns the schema of the JSON encoding of values of type t,
+// with no constraints.
+func typeSchema(t reflect.Type) *JSONSchema {
+	switch {
+	case t == timeType:
+		return &JSONSchema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return &JSONSchema{Type: "string"}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &JSONSchema{Type: "array"}
+	case reflect.Map, reflect.Struct:
+		return &JSONSchema{Type: "object"}
+	}
+	return &JSONSchema{}
+}
+
+// toFloat returns the value of a number as a float64.
+func toFloat(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// jsonEqual reports whether v equals the decoded JSON value j.
+func jsonEqual(v reflect.Value, j interface{}) bool {
+	switch j := j.(type) {
+	case string:
+		return v.Kind() == reflect.String && v.String() == j
+	case float64:
+		x, ok := toFloat(v)
+		return ok && x == j
+	case bool:
+		return v.Kind() == reflect.Bool && v.Bool() == j
+	}
+	return false
+}
@@ -0,0 +1,337 @@
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+const petSchema = `{
+	"type": "object",
+	"required": ["name", "owner"],
+	"properties": {
+		"name": {"type": "string", "minLength": 2, "maxLength": 10, "pattern": "^[A-Z]"},
+		"kind": {"type": "string", "enum": ["cat", "dog"]},
+		"age": {"type": "integer", "minimum": 0, "exclusiveMaximum": 40},
+		"tags": {"type": "array", "maxItems": 2},
+		"owner": {
+			"type": "object",
+			"required": ["email"],
+			"properties": {
+				"email": {"type": "string"}
+			}
+		}
+	}
+}`
+
+func TestImportSchema(t *testing.T) {
+	type Owner struct {
+		Email *string `json:"email"`
+	}
+	type Pet struct {
+		Name  string   `json:"name"`
+		Kind  *string  `json:"kind,omitempty"`
+		Age   *int     `json:"age"`
+		Tags  []string `json:"tags"`
+		Owner *Owner   `json:"owner"`
+	}
+
+	sv, rules, err := ImportSchema([]byte(petSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules["owner"] != "present,schema.owner,struct" || rules["owner.email"] != "present,schema.owner.email" {
+		t.Fatalf("wrong rules: %v", rules)
+	}
+
+	validate := func(p Pet) []error {
+		return sv.ValidateOpts(p, NameTags("json"), Rules(rules))
+	}
+
+	age, dog, eel, email := 3, "dog", "eel", ""
+	owner := &Owner{&email}
+	if errs := validate(Pet{Name: "Rex", Kind: &dog, Age: &age, Owner: owner}); errs != nil {
+		t.Fatalf("unexpected errors for a valid pet: %v", errs)
+	}
+
+	age = 40
+	tests := []struct {
+		pet   Pet
+		field string
+		kind  error
+	}{
+		{Pet{Name: "Rex"}, "owner", ErrRequired},
+		{Pet{Name: "Rex", Owner: &Owner{}}, "owner.email", ErrRequired},
+		{Pet{Name: "R", Owner: owner}, "name", ErrTooShort},
+		{Pet{Name: "rex", Owner: owner}, "name", ErrBadFormat},
+		{Pet{Name: "Rex", Kind: &eel, Owner: owner}, "kind", ErrNotAllowed},
+		{Pet{Name: "Rex", Age: &age, Owner: owner}, "age", ErrOutOfRange},
+		{Pet{Name: "Rex", Tags: []string{"a", "b", "c"}, Owner: owner}, "tags", ErrTooLong},
+	}
+	for _, test := range tests {
+		errs := validate(test.pet)
+		if len(errs) == 0 {
+			t.Fatalf("no errors for %+v", test.pet)
+		}
+		bf := errs[0].(BadField)
+		if bf.Field != test.field || !errors.Is(bf.Err, test.kind) {
+			t.Fatalf("wrong error for %+v: %v", test.pet, bf)
+		}
+	}
+}
+
+func TestImportSchema_zero(t *testing.T) {
+	type X struct {
+		Count *int   `json:"count"`
+		Level int    `json:"level"`
+		Name  string `json:"name"`
+	}
+
+	sv, rules, err := ImportSchema([]byte(`{
+		"required": ["count"],
+		"properties": {
+			"count": {"type": "integer", "minimum": 1},
+			"level": {"type": "integer", "minimum": 1},
+			"name": {"type": "string", "enum": ["a", "b"]}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	zero := 0
+	for _, x := range []X{{}, {Count: &zero, Level: 1, Name: "a"}} {
+		for _, err := range sv.ValidateOpts(x, NameTags("json"), Rules(rules)) {
+			got = append(got, err.Error())
+		}
+	}
+	want := []string{
+		"field count is invalid: is required",
+		"field level is invalid: 0 is less than 1",
+		`field name is invalid:  is not one of [a b]`,
+		"field count is invalid: 0 is less than 1",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("wrong errors:\n%q\nwanted:\n%q", got, want)
+	}
+}
+
+func TestImportSchema_openAPI(t *testing.T) {
+	type X struct {
+		Count int            `json:"count"`
+		Ratio *float64       `json:"ratio"`
+		Odd   map[string]int `json:"b|c=d:e"`
+	}
+
+	sv, rules, err := ImportSchema([]byte(`{
+		"required": ["count", "b|c=d:e"],
+		"properties": {
+			"count": {"type": "integer", "minimum": 0, "exclusiveMaximum": 10},
+			"ratio": {"type": "number", "minimum": 0, "exclusiveMinimum": true, "maximum": 1, "exclusiveMaximum": false},
+			"a,b": {"type": "string"},
+			"b|c=d:e": {"type": "object"}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule := rules["a,b"]; rule != "schema.a%2Cb" || sv[rule] == nil {
+		t.Fatalf("wrong rule for a property with a comma: %q", rule)
+	}
+	if rule := rules["b|c=d:e"]; rule != "present,schema.b%7Cc%3Dd%3Ae" {
+		t.Fatalf("wrong rule for a property with the characters of the tag syntax: %q", rule)
+	}
+
+	var got []string
+	zero, one := 0.0, 1.0
+	for _, x := range []X{{Ratio: &one, Odd: map[string]int{}}, {Count: 10, Ratio: &zero}} {
+		for _, err := range sv.ValidateOpts(x, NameTags("json"), Rules(rules)) {
+			got = append(got, err.Error())
+		}
+	}
+	want := []string{
+		"field count is invalid: 10 is not less than 10",
+		"field ratio is invalid: 0 is not greater than 0",
+		"field b|c=d:e is invalid: is required",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("wrong errors:\n%q\nwanted:\n%q", got, want)
+	}
+}
+
+func TestImportSchema_errors(t *testing.T) {
+	for _, s := range []string{
+		`[]`,
+		`{"type": "string"}`,
+		`{"properties": {"a": {"pattern": "("}}}`,
+	} {
+		if _, _, err := ImportSchema([]byte(s)); err == nil {
+			t.Fatalf("no error importing %s", s)
+		}
+	}
+}
+
+func TestV_ValidateOpts_rules(t *testing.T) {
+	type X struct {
+		A int `validate:"odd"`
+		B int
+	}
+
+	vd := make(V)
+	vd["odd"] = func(i interface{}) error {
+		return fmt.Errorf("not odd")
+	}
+	vd["even"] = func(i interface{}) error {
+		return fmt.Errorf("not even")
+	}
+
+	errs := vd.ValidateOpts(X{}, Rules(map[string]string{"A": "even", "B": "odd"}))
+	if fmt.Sprint(errs) != "[field A is invalid: not odd field A is invalid: not even field B is invalid: not odd]" {
+		t.Fatalf("wrong errors with extra rules: %v", errs)
+	}
+}
+
+func TestSchemaFor(t *testing.T) {
+	type Owner struct {
+		Email string `json:"email" validate:"required,email|url"`
+	}
+	type Pet struct {
+		Name    string            `json:"name" validate:"required,petname"`
+		Owner   *Owner            `json:"owner,omitempty" validate:"struct"`
+		Tags    []string          `json:"tags" validate:"unique,each,nonempty,alpha"`
+		Labels  map[string]string `json:"labels" validate:"keys=numeric,values=odd"`
+		Born    time.Time         `json:"born" validate:"on=create,required"`
+		Parent  *testNode         `json:"parent" validate:"struct"`
+		Secret  string            `json:"-" validate:"required"`
+		Comment string
+		Age     int    `json:"age" validate:"gte=0,lt=30"`
+		Weight  int    `json:"weight" validate:"between=1 90"`
+		Nick    string `json:"nick" validate:"minlen=2,maxlen=10 bytes"`
+		Color   string `json:"color" validate:"oneof=black white"`
+		Legs    int    `json:"legs" validate:"oneof=2 4"`
+	}
+
+	vd := Builtin()
+	vd.Alias("petname", "nonempty")
+	s, err := SchemaFor(&Pet{}, vd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(s)
+	want := `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object","properties":{` +
+		`"Comment":{"type":"string"},` +
+		`"age":{"type":"integer","minimum":0,"exclusiveMaximum":30},` +
+		`"born":{"type":"string","format":"date-time"},` +
+		`"color":{"type":"string","enum":["black","white"]},` +
+		`"labels":{"type":"object","propertyNames":{"type":"string","pattern":"` + jsonString(numericRE.String()) + `"},"additionalProperties":{"type":"string"}},` +
+		`"legs":{"type":"integer","enum":[2,4]},` +
+		`"name":{"type":"string","minLength":1},` +
+		`"nick":{"type":"string","minLength":2},` +
+		`"owner":{"type":["object","null"],"properties":{"email":{"type":"string","anyOf":[{"format":"email"},{"format":"uri"}]}},"required":["email"]},` +
+		`"parent":{"type":["object","null"],"properties":{"Next":{"anyOf":[{"$ref":"#/properties/parent","type":"object"},{"type":"null"}]},"Value":{"type":"integer"}}},` +
+		`"tags":{"type":"array","items":{"type":"string","minLength":1,"pattern":"^\\p{L}+$"},"uniqueItems":true},` +
+		`"weight":{"type":"integer","minimum":1,"maximum":90}` +
+		`},"required":["name"]}`
+	if string(b) != want {
+		t.Fatalf("wrong schema:\n%s\nwanted:\n%s", b, want)
+	}
+
+	if _, err := SchemaFor(1, vd); err == nil {
+		t.Fatal("no error for a schema of an int")
+	}
+}
+
+func TestSchemaFor_embedded(t *testing.T) {
+	type Base struct {
+		ID    string `json:"id" validate:"nonempty"`
+		Label string `json:"Type"`
+		Rev   int
+		Note  string `json:"note"`
+	}
+	type Meta struct {
+		Type    string
+		Rev     int
+		Version int `json:"version" validate:"gte=1"`
+	}
+	type hidden struct {
+		Secret string `json:"secret" validate:"nonempty"`
+	}
+	type Named struct {
+		X string `json:"x"`
+	}
+	type Doc struct {
+		Base `validate:"struct"`
+		*Meta
+		hidden
+		Named `json:"named"`
+		Note  string `json:"note" validate:"maxlen=5"`
+	}
+
+	s, err := SchemaFor(Doc{}, Builtin())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(s)
+	// Base's Label, being tagged, wins over Meta's Type; their Revs are
+	// ambiguous, and Doc's Note shadows Base's. Only Base is validated.
+	want := `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object","properties":{` +
+		`"Type":{"type":"string"},` +
+		`"id":{"type":"string","minLength":1},` +
+		`"named":{"type":"object"},` +
+		`"note":{"type":"string","maxLength":5},` +
+		`"secret":{"type":"string"},` +
+		`"version":{"type":"integer"}` +
+		`}}`
+	if string(b) != want {
+		t.Fatalf("wrong schema:\n%s\nwanted:\n%s", b, want)
+	}
+
+	b, _ = json.Marshal(Doc{Meta: &Meta{}})
+	var encoded map[string]interface{}
+	json.Unmarshal(b, &encoded)
+	for name := range s.Properties {
+		if _, ok := encoded[name]; !ok {
+			t.Errorf("property %q is not encoded by encoding/json", name)
+		}
+	}
+	if len(encoded) != len(s.Properties) {
+		t.Errorf("encoding/json encodes %s, but the schema describes %d properties", b, len(s.Properties))
+	}
+}
+
+func TestSchemaFor_optional(t *testing.T) {
+	type X struct {
+		Code  string  `json:"code" validate:"omitempty,minlen=4,alpha"`
+		Count int     `json:"count" validate:"gte=1,omitempty,lte=9"`
+		Note  string  `json:"note" validate:"omitempty"`
+		Color *string `json:"color" validate:"oneof=red blue"`
+		Link  *string `json:"link" validate:"email|url"`
+		Must  *int    `json:"must" validate:"required"`
+		Tags  *[]int  `json:"tags" validate:"omitempty,minlen=1"`
+	}
+
+	s, err := SchemaFor(X{}, Builtin())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(s)
+	want := `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object","properties":{` +
+		`"code":{"type":"string","anyOf":[{"enum":[""]},{"minLength":4,"pattern":"^\\p{L}+$"}]},` +
+		`"color":{"type":["string","null"],"enum":["red","blue",null]},` +
+		`"count":{"type":"integer","anyOf":[{"enum":[0]},{"maximum":9}],"minimum":1},` +
+		`"link":{"type":["string","null"],"anyOf":[{"format":"email"},{"format":"uri"},{"type":"null"}]},` +
+		`"must":{"type":"integer"},` +
+		`"note":{"type":"string"},` +
+		`"tags":{"type":["array","null"],"minItems":1}` +
+		`},"required":["must"]}`
+	if string(b) != want {
+		t.Fatalf("wrong schema:\n%s\nwanted:\n%s", b, want)
+	}
+}
+
+func jsonString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b[1 : len(b)-1])
+}
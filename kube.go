@@ -0,0 +1,136 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// These validators check the names and values used by Kubernetes, for
+// controllers and tools that validate structs modeled on its resources.
+// Each reports a value whose kind is not string with an error wrapping
+// ErrWrongType, a string that is too long with one wrapping ErrTooLong,
+// and any other invalid string with one wrapping ErrBadFormat.
+
+var (
+	dnsLabelRE  = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	labelNameRE = regexp.MustCompile(`^([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]$`)
+	quantityRE  = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)([eE][+-]?[0-9]+|[KMGTPE]i|[numkMGTPE])?$`)
+)
+
+// DNSLabel validates a DNS label as defined by RFC 1123: at most 63
+// lowercase letters, digits, and hyphens, beginning and ending with a
+// letter or digit.
+func DNSLabel(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a DNS label", i)
+	}
+	return dnsLabel(s, "DNS label")
+}
+
+func dnsLabel(s, what string) error {
+	if len(s) > 63 {
+		return errorf(ErrTooLong, "%q is not a valid %s: longer than 63 characters", s, what)
+	}
+	if !dnsLabelRE.MatchString(s) {
+		return errorf(ErrBadFormat, "%q is not a valid %s: want lowercase letters, digits, and hyphens, "+
+			"beginning and ending with a letter or digit", s, what)
+	}
+	return nil
+}
+
+// DNSSubdomain validates a DNS subdomain as defined by RFC 1123: at most
+// 253 characters in DNS labels separated by dots.
+func DNSSubdomain(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a DNS subdomain", i)
+	}
+	return dnsSubdomain(s, "DNS subdomain")
+}
+
+func dnsSubdomain(s, what string) error {
+	if len(s) > 253 {
+		return errorf(ErrTooLong, "%q is not a valid %s: longer than 253 characters", s, what)
+	}
+	for _, label := range strings.Split(s, ".") {
+		if !dnsLabelRE.MatchString(label) {
+			return errorf(ErrBadFormat, "%q is not a valid %s: %q is not a DNS label", s, what, label)
+		}
+	}
+	return nil
+}
+
+// ResourceName validates the name of a Kubernetes resource. Most kinds of
+// resource are named by DNS subdomains, so ResourceName accepts the same
+// names as DNSSubdomain; use DNSLabel for the kinds, such as namespaces and
+// services, that are named by DNS labels.
+func ResourceName(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a resource name", i)
+	}
+	return dnsSubdomain(s, "resource name")
+}
+
+// LabelKey validates the key of a Kubernetes label or annotation:
+// a name of at most 63 letters, digits, hyphens, underscores, and dots,
+// beginning and ending with a letter or digit, optionally prefixed by
+// a DNS subdomain and a slash, as in "app.kubernetes.io/name".
+func LabelKey(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a label key", i)
+	}
+	name := s
+	if prefix, n, ok := strings.Cut(s, "/"); ok {
+		if err := dnsSubdomain(prefix, "label key prefix"); err != nil {
+			return errorf(errors.Unwrap(err), "%q is not a valid label key: %v", s, err)
+		}
+		name = n
+	}
+	if len(name) > 63 {
+		return errorf(ErrTooLong, "%q is not a valid label key: name longer than 63 characters", s)
+	}
+	if !labelNameRE.MatchString(name) {
+		return errorf(ErrBadFormat, "%q is not a valid label key: want letters, digits, hyphens, underscores, "+
+			"and dots, beginning and ending with a letter or digit", s)
+	}
+	return nil
+}
+
+// LabelValue validates the value of a Kubernetes label: empty, or at most
+// 63 letters, digits, hyphens, underscores, and dots, beginning and ending
+// with a letter or digit.
+func LabelValue(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a label value", i)
+	}
+	if len(s) > 63 {
+		return errorf(ErrTooLong, "%q is not a valid label value: longer than 63 characters", s)
+	}
+	if s != "" && !labelNameRE.MatchString(s) {
+		return errorf(ErrBadFormat, "%q is not a valid label value: want letters, digits, hyphens, underscores, "+
+			"and dots, beginning and ending with a letter or digit", s)
+	}
+	return nil
+}
+
+// Quantity validates a Kubernetes resource quantity: a decimal number
+// followed by an optional binary suffix (Ki, Mi, Gi, Ti, Pi, Ei), decimal
+// suffix (n, u, m, k, M, G, T, P, E), or exponent, as in "500Mi", "2",
+// "250m", or "1e3".
+func Quantity(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a quantity", i)
+	}
+	if !quantityRE.MatchString(s) {
+		return errorf(ErrBadFormat, "%q is not a valid quantity", s)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDNSLabel(t *testing.T) {
+	testFormat(t, "DNSLabel", DNSLabel, map[interface{}]error{
+		"web":                   nil,
+		"web-1":                 nil,
+		"1a":                    nil,
+		strings.Repeat("a", 64): ErrTooLong,
+		"Web":                   ErrBadFormat,
+		"-web":                  ErrBadFormat,
+		"web.example":           ErrBadFormat,
+		"":                      ErrBadFormat,
+		3:                       ErrWrongType,
+	})
+}
+
+func TestDNSSubdomain(t *testing.T) {
+	testFormat(t, "DNSSubdomain", DNSSubdomain, map[interface{}]error{
+		"example.com":             nil,
+		"a.b-c.d":                 nil,
+		strings.Repeat("a.", 127): ErrTooLong,
+		"example..com":            ErrBadFormat,
+		"example.com.":            ErrBadFormat,
+		"ex_ample.com":            ErrBadFormat,
+		nil:                       ErrWrongType,
+	})
+	testFormat(t, "ResourceName", ResourceName, map[interface{}]error{
+		"my-app.v1": nil,
+		"My-App":    ErrBadFormat,
+	})
+}
+
+func TestLabelKey(t *testing.T) {
+	testFormat(t, "LabelKey", LabelKey, map[interface{}]error{
+		"app":                                    nil,
+		"app.kubernetes.io/name":                 nil,
+		"Some_Key.x":                             nil,
+		"example.com/" + strings.Repeat("a", 64): ErrTooLong,
+		"Example.com/name":                       ErrBadFormat,
+		"example.com/":                           ErrBadFormat,
+		"_app":                                   ErrBadFormat,
+		"a/b/c":                                  ErrBadFormat,
+		1:                                        ErrWrongType,
+	})
+}
+
+func TestLabelValue(t *testing.T) {
+	testFormat(t, "LabelValue", LabelValue, map[interface{}]error{
+		"":                      nil,
+		"v1.2_3-rc":             nil,
+		strings.Repeat("a", 64): ErrTooLong,
+		"v1/2":                  ErrBadFormat,
+		"-v1":                   ErrBadFormat,
+		true:                    ErrWrongType,
+	})
+}
+
+func TestQuantity(t *testing.T) {
+	testFormat(t, "Quantity", Quantity, map[interface{}]error{
+		"500Mi": nil,
+		"2":     nil,
+		"250m":  nil,
+		"1.5Gi": nil,
+		"1e3":   nil,
+		"-.5":   nil,
+		"500MB": ErrBadFormat,
+		"Mi":    ErrBadFormat,
+		"1 Gi":  ErrBadFormat,
+		"":      ErrBadFormat,
+		500:     ErrWrongType,
+	})
+}
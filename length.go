@@ -0,0 +1,75 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+func exactLen(i interface{}, param string) error {
+	n, want, unit, err := length(i, param)
+	switch {
+	case err != nil:
+		return err
+	case n < want:
+		return errorf(ErrTooShort, "length %d%s is not %d", n, unit, want)
+	case n > want:
+		return errorf(ErrTooLong, "length %d%s is not %d", n, unit, want)
+	}
+	return nil
+}
+
+func minLen(i interface{}, param string) error {
+	n, min, unit, err := length(i, param)
+	if err == nil && n < min {
+		err = errorf(ErrTooShort, "length %d%s is less than %d", n, unit, min)
+	}
+	return err
+}
+
+func maxLen(i interface{}, param string) error {
+	n, max, unit, err := length(i, param)
+	if err == nil && n > max {
+		err = errorf(ErrTooLong, "length %d%s is greater than %d", n, unit, max)
+	}
+	return err
+}
+
+// lengthParam is the parameter check of "len", "minlen", and "maxlen".
+func lengthParam(t reflect.Type, param string) error {
+	_, _, _, err := length(zeroOf(t, ""), param)
+	return err
+}
+
+// length returns the length of i, a string, slice, array, map, or channel,
+// and the length given by param, which may be followed by "runes" or
+// "bytes" to say how strings are measured. Strings are measured in runes
+// unless param says otherwise, and unit is " bytes" when they are not.
+func length(i interface{}, param string) (n, want int, unit string, err error) {
+	fields, measure := strings.Fields(param), ""
+	if len(fields) == 2 {
+		measure = fields[1]
+	}
+	if len(fields) > 0 {
+		want, err = strconv.Atoi(fields[0])
+	}
+	if len(fields) == 0 || len(fields) > 2 || err != nil || want < 0 || measure != "" && measure != "runes" && measure != "bytes" {
+		return 0, 0, "", fmt.Errorf("parameter %q is not a length, as in \"10\" or \"10 bytes\"", param)
+	}
+
+	val := reflect.ValueOf(i)
+	switch val.Kind() {
+	case reflect.String:
+		if measure == "bytes" {
+			return val.Len(), want, " bytes", nil
+		}
+		return utf8.RuneCountInString(val.String()), want, "", nil
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		return val.Len(), want, "", nil
+	}
+	return 0, 0, "", errorf(ErrWrongType, "cannot check the length of %T", i)
+}
@@ -0,0 +1,70 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLength(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		param string
+		n     int
+		unit  string
+	}{
+		{"héllo", "5", 5, ""},
+		{"héllo", "5 runes", 5, ""},
+		{"héllo", "5 bytes", 6, " bytes"},
+		{"日本語", "3", 3, ""},
+		{"日本語", "9 bytes", 9, " bytes"},
+		{"👍🏽", "2", 2, ""},
+		{"👍🏽", "8 bytes", 8, " bytes"},
+		{testName("ab"), "2", 2, ""},
+		{[]string{"日本語"}, "1 bytes", 1, ""},
+		{map[int]int{1: 1}, "1", 1, ""},
+		{[3]int{}, "3", 3, ""},
+	}
+	for _, test := range tests {
+		n, _, unit, err := length(test.value, test.param)
+		if err != nil || n != test.n || unit != test.unit {
+			t.Errorf("length(%#v, %q) = %d, %q, %v; wanted %d, %q", test.value, test.param, n, unit, err, test.n, test.unit)
+		}
+	}
+
+	for _, param := range []string{"", "-1", "ten", "10 words", "1 2 3"} {
+		if _, _, _, err := length("", param); err == nil {
+			t.Errorf("no error for parameter %q", param)
+		}
+	}
+	if _, _, _, err := length(1, "1"); !errors.Is(err, ErrWrongType) {
+		t.Error("wrong error for an int:", err)
+	}
+}
+
+func TestV_Validate_length(t *testing.T) {
+	type X struct {
+		Name  string   `validate:"minlen=2,maxlen=3"`
+		Code  string   `validate:"len=2 bytes"`
+		Bio   string   `validate:"maxlen=4 bytes"`
+		Tags  []string `validate:"maxlen=1"`
+		Short string   `validate:"len=3"`
+	}
+
+	errs := Builtin().Validate(X{Name: "日本語", Code: "é", Bio: "日本", Tags: []string{"a", "b"}, Short: "ab"})
+	want := []string{
+		"field Bio is invalid: length 6 bytes is greater than 4",
+		"field Tags is invalid: length 2 is greater than 1",
+		"field Short is invalid: length 2 is not 3",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+	for i, err := range errs {
+		if err.Error() != want[i] {
+			t.Errorf("error %d is %q, wanted %q", i, err, want[i])
+		}
+	}
+	if !errors.Is(errs[0], ErrTooLong) || !errors.Is(errs[2], ErrTooShort) {
+		t.Error("lengths out of range not reported with ErrTooLong and ErrTooShort")
+	}
+}
@@ -0,0 +1,143 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type localeKey struct{}
+
+// WithLocale returns a copy of ctx carrying locale, a language tag such as
+// "en-US", for use by validators that produce localized messages.
+// HTTP middleware can set it once per request, and it reaches validators
+// through ValidateContext without being passed to every call.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFrom returns the locale carried by ctx, or "" if it has none.
+func LocaleFrom(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	l, _ := ctx.Value(localeKey{}).(string)
+	return l
+}
+
+type catalogKey struct{}
+
+// WithCatalog returns a copy of ctx carrying c. The errors found by
+// validating with a context carrying a Catalog, as with ValidateContext
+// or the Context option, are translated by it into the context's locale,
+// as by Catalog.Translate, as they are found.
+func WithCatalog(ctx context.Context, c Catalog) context.Context {
+	return context.WithValue(ctx, catalogKey{}, c)
+}
+
+// CatalogFrom returns the Catalog carried by ctx, or nil if it has none.
+func CatalogFrom(ctx context.Context) Catalog {
+	if ctx == nil {
+		return nil
+	}
+	c, _ := ctx.Value(catalogKey{}).(Catalog)
+	return c
+}
+
+// MatchLocale chooses the best of the available locales for an HTTP
+// Accept-Language header, such as "fr-CH, fr;q=0.9, en;q=0.8".
+// Languages are tried in order of preference, each matching an available
+// locale exactly (ignoring case) or, failing that, by its primary language
+// subtag, so "fr-CH" matches "fr". If nothing matches, MatchLocale returns
+// fallback.
+func MatchLocale(header string, available []string, fallback string) string {
+	for _, lang := range parseAcceptLanguage(header) {
+		if lang == "*" {
+			break
+		}
+		for _, a := range available {
+			if strings.EqualFold(a, lang) {
+				return a
+			}
+		}
+		base, _, _ := strings.Cut(lang, "-")
+		for _, a := range available {
+			if strings.EqualFold(a, base) {
+				return a
+			}
+		}
+	}
+	return fallback
+}
+
+// parseAcceptLanguage returns the languages of an Accept-Language header
+// in order of decreasing quality, omitting those with quality 0.
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		lang string
+		q    float64
+	}
+	var ws []weighted
+	for _, part := range strings.Split(header, ",") {
+		lang, params, _ := strings.Cut(part, ";")
+		lang = strings.TrimSpace(lang)
+		if lang == "" {
+			continue
+		}
+		q := 1.0
+		for _, p := range strings.Split(params, ";") {
+			k, v, _ := strings.Cut(strings.TrimSpace(p), "=")
+			if k == "q" {
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					q = f
+				}
+			}
+		}
+		if q > 0 {
+			ws = append(ws, weighted{lang, q})
+		}
+	}
+	sort.SliceStable(ws, func(i, j int) bool {
+		return ws[i].q > ws[j].q
+	})
+
+	langs := make([]string, len(ws))
+	for i, w := range ws {
+		langs[i] = w.lang
+	}
+	return langs
+}
+
+// Localize returns a handler that sets the locale of each request's context,
+// as for WithLocale, to the best match for its Accept-Language header among
+// the available locales, or to fallback, and then calls h. Errors from
+// validating with the request's context are then translated into that
+// locale if the context also carries a Catalog; see Catalog.Localize.
+func Localize(available []string, fallback string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l := MatchLocale(r.Header.Get("Accept-Language"), available, fallback)
+		h.ServeHTTP(w, r.WithContext(WithLocale(r.Context(), l)))
+	})
+}
+
+// Localize returns a handler that sets the locale of each request's context
+// as the package's Localize does, choosing among the locales c translates
+// into, and sets its catalog to c, as for WithCatalog, and then calls h.
+// Errors from validating with the request's context, such as those
+// httpvalidate writes, are then translated into its locale:
+//
+//	http.Handle("/users", catalog.Localize("en", httpvalidate.Handler(vd, createUser)))
+func (c Catalog) Localize(fallback string, h http.Handler) http.Handler {
+	locales := make([]string, 0, len(c))
+	for l := range c {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	return Localize(locales, fallback, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(WithCatalog(r.Context(), c)))
+	}))
+}
@@ -0,0 +1,123 @@
+package validate
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLocale(t *testing.T) {
+	type X struct {
+		A string `validate:"nonempty"`
+	}
+
+	messages := map[string]string{
+		"":   "is empty",
+		"fr": "est vide",
+	}
+
+	vd := make(V)
+	vd.RegisterField("nonempty", func(f Field) error {
+		if f.Value.String() == "" {
+			return errors.New(messages[LocaleFrom(f.Context)])
+		}
+		return nil
+	})
+
+	errs := vd.Validate(X{})
+	if len(errs) != 1 || errs[0].Error() != "field A is invalid: is empty" {
+		t.Fatalf("wrong errors without a locale: %v", errs)
+	}
+
+	ctx := WithLocale(context.Background(), "fr")
+	errs = vd.ValidateContext(ctx, X{})
+	if len(errs) != 1 || errs[0].Error() != "field A is invalid: est vide" {
+		t.Fatalf("wrong errors with a locale: %v", errs)
+	}
+}
+
+func TestMatchLocale(t *testing.T) {
+	available := []string{"en", "fr", "pt-BR"}
+
+	tests := []struct {
+		header, locale string
+	}{
+		{"", "en"},
+		{"fr", "fr"},
+		{"fr-CH, fr;q=0.9, en;q=0.8", "fr"},
+		{"de, en;q=0.5, fr;q=0.7", "fr"},
+		{"pt-br", "pt-BR"},
+		{"pt-PT", "en"},
+		{"fr;q=0, de", "en"},
+		{"*", "en"},
+	}
+
+	for _, test := range tests {
+		if l := MatchLocale(test.header, available, "en"); l != test.locale {
+			t.Fatalf("wrong locale for %q: %q", test.header, l)
+		}
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	var locale string
+	h := Localize([]string{"en", "fr"}, "en", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale = LocaleFrom(r.Context())
+	}))
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Language", "fr-FR")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	if locale != "fr" {
+		t.Fatalf("wrong locale in request context: %q", locale)
+	}
+}
+
+func TestWithCatalog(t *testing.T) {
+	type X struct {
+		A string `validate:"nonempty"`
+	}
+	c := Catalog{"fr": {"should not be empty": "ne doit pas être vide"}}
+
+	ctx := WithCatalog(WithLocale(context.Background(), "fr-CH"), c)
+	if CatalogFrom(ctx) == nil || CatalogFrom(context.Background()) != nil {
+		t.Fatal("wrong catalog in context")
+	}
+	errs := Builtin().ValidateContext(ctx, X{})
+	if len(errs) != 1 || errs[0].Error() != "field A is invalid: ne doit pas être vide" {
+		t.Fatalf("wrong errors with a catalog: %v", errs)
+	}
+	if !errors.Is(errs[0], ErrRequired) {
+		t.Fatalf("translated error does not wrap the original: %v", errs[0])
+	}
+
+	errs = Builtin().ValidateContext(WithCatalog(context.Background(), c), X{})
+	if len(errs) != 1 || errs[0].Error() != "field A is invalid: should not be empty" {
+		t.Fatalf("wrong errors with a catalog but no locale: %v", errs)
+	}
+}
+
+func TestCatalog_Localize(t *testing.T) {
+	type X struct {
+		A string `validate:"nonempty"`
+	}
+	c := Catalog{"en": {}, "fr": {"should not be empty": "ne doit pas être vide"}}
+
+	var errs []error
+	h := c.Localize("en", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		errs = Builtin().ValidateContext(r.Context(), X{})
+	}))
+	for header, want := range map[string]string{
+		"fr-FR, en;q=0.5": "field A is invalid: ne doit pas être vide",
+		"de":              "field A is invalid: should not be empty",
+	} {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Accept-Language", header)
+		h.ServeHTTP(httptest.NewRecorder(), r)
+		if len(errs) != 1 || errs[0].Error() != want {
+			t.Errorf("wrong errors for %q: %v", header, errs)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ErrDuplicateValidator is wrapped by the errors Merge and Mount report
+// for rules that both V define.
+var ErrDuplicateValidator = errors.New("validator defined twice")
+
+// Merge adds the validators, extended validators, and aliases in other to
+// v, so that a shared library of rules can be combined with a service's
+// own. A rule defined by both, in any form, is a collision; if there are
+// any, v is left unchanged, and Merge returns an error wrapping
+// ErrDuplicateValidator for each.
+func (v V) Merge(other V) error {
+	return v.mount("", other)
+}
+
+// Mount behaves like Merge, but adds the rules in other under names
+// starting with prefix, so that libraries that name rules alike can be
+// used together:
+//
+//	err := vd.Mount("str.", stringValidators)
+//
+//	type X struct {
+//		Name string `validate:"str.trimmed"`
+//	}
+//
+// The rules named by other's aliases are renamed to match, except for
+// those in the parameters of reserved rules such as "each". Other's
+// fallback, if any, is not mounted, and its validators for structs are
+// mounted under their own names.
+func (v V) Mount(prefix string, other V) error {
+	return v.mount(prefix, other)
+}
+
+func (v V) mount(prefix string, other V) error {
+	flat := v.flatten()
+	other = other.flatten()
+	vm, om := flat.meta(), other.meta()
+	if om == nil {
+		om = &meta{}
+	}
+
+	defined := make(map[string]bool)
+	for key := range flat {
+		defined[ruleName(key)] = true
+	}
+	if vm != nil {
+		for name := range vm.aliases {
+			defined[name] = true
+		}
+	}
+	var dups []string
+	for key := range other {
+		name := ruleName(key)
+		if key == metaKey || prefix != "" && name == "" {
+			continue
+		}
+		if defined[prefix+name] {
+			dups = append(dups, prefix+name)
+		}
+	}
+	for name := range om.aliases {
+		if defined[prefix+name] {
+			dups = append(dups, prefix+name)
+		}
+	}
+	for t := range om.structs {
+		if vm != nil && vm.structs[t] != nil {
+			dups = append(dups, t.String()+"{}")
+		}
+	}
+	if len(dups) > 0 {
+		sort.Strings(dups)
+		errs := make([]error, len(dups))
+		for i, name := range dups {
+			errs[i] = fmt.Errorf("%w: %q", ErrDuplicateValidator, name)
+		}
+		return errors.Join(errs...)
+	}
+
+	for key, fn := range other {
+		if key == metaKey || prefix != "" && ruleName(key) == "" {
+			continue
+		}
+		v[prefix+key] = fn
+	}
+	if len(om.aliases) == 0 && len(om.accepts) == 0 && len(om.params) == 0 && len(om.structs) == 0 {
+		return nil
+	}
+	m := v.editMeta()
+	for name, tag := range om.aliases {
+		if prefix != "" {
+			tag = renameRules(tag, prefix, other, om)
+		}
+		m.aliases = union(m.aliases, map[string]string{prefix + name: tag})
+	}
+	for name, a := range om.accepts {
+		m.accepts = union(m.accepts, map[string]accepts{prefix + name: a})
+	}
+	for name, check := range om.params {
+		m.params = union(m.params, map[string]func(reflect.Type, string) error{prefix + name: check})
+	}
+	m.structs = union(m.structs, om.structs)
+	return nil
+}
+
+// ruleName returns the name of the rule whose validator is stored in a V
+// under key, without the suffix marking an extended validator.
+func ruleName(key string) string {
+	return strings.TrimSuffix(key, "=")
+}
+
+// renameRules returns tag, with the names of the rules defined by other,
+// whose meta is om, starting with prefix.
+func renameRules(tag, prefix string, other V, om *meta) string {
+	rules, err := ParseTag(tag)
+	if err != nil {
+		return tag
+	}
+	var b strings.Builder
+	for i, r := range rules {
+		switch {
+		case r.Or:
+			b.WriteByte('|')
+		case i > 0:
+			b.WriteByte(',')
+		}
+		if !Reserved(r.Name) && (other[r.Name] != nil || other[r.Name+"="] != nil || om.aliases[r.Name] != "") {
+			r.Name = prefix + r.Name
+		}
+		b.WriteString(ruleString(r))
+	}
+	return b.String()
+}
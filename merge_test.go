@@ -0,0 +1,76 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestV_Merge(t *testing.T) {
+	v := V{"a": nonzero, "b=": nonzero}
+	if err := v.Merge(V{"c": nonzero, "d,": nonzero, "c:": nonzero}); err != nil {
+		t.Fatal(err)
+	}
+	if len(v) != 5 || v["c"] == nil || v["d,"] == nil || v["c:"] == nil {
+		t.Fatalf("wrong merged validators: %v", len(v))
+	}
+
+	err := v.Merge(V{"a=": nonzero, "b": nonzero, "e": nonzero})
+	if !errors.Is(err, ErrDuplicateValidator) || err.Error() != "validator defined twice: \"a\"\nvalidator defined twice: \"b\"" {
+		t.Fatalf("wrong error for collisions: %v", err)
+	}
+	if v["e"] != nil {
+		t.Fatal("merged validators despite collisions")
+	}
+}
+
+func TestV_Mount(t *testing.T) {
+	type X struct {
+		A string `validate:"str.trimmed"`
+		B string `validate:"str.name"`
+		C string `validate:"trimmed"`
+	}
+
+	strs := make(V)
+	strs["trimmed"] = func(interface{}) error { return errors.New("not trimmed") }
+	strs["nonempty"] = nonempty
+	strs.Alias("name", "trimmed|nonempty,struct")
+	strs.RegisterFallback(func(Field) error { return nil })
+
+	vd := V{"trimmed": nonzero}
+	if err := vd.Mount("str.", strs); err != nil {
+		t.Fatal(err)
+	}
+	if vd["str.="] != nil {
+		t.Fatal("mounted a fallback")
+	}
+	if tag, _ := (&walker{v: vd}).alias("str.name"); tag != "str.trimmed|str.nonempty,struct" {
+		t.Fatalf("wrong alias: %q", tag)
+	}
+
+	errs := vd.Validate(X{})
+	if len(errs) != 3 || errs[0].Error() != "field A is invalid: not trimmed" ||
+		errs[1].(BadField).Rule != "str.trimmed|str.nonempty" || errs[2].Error() != "field C is invalid: should be nonzero" {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+
+	if err := vd.Mount("str.", V{"trimmed": nonzero}); !errors.Is(err, ErrDuplicateValidator) {
+		t.Fatalf("wrong error for a collision: %v", err)
+	}
+}
+
+func TestV_Mount_struct(t *testing.T) {
+	type X struct{}
+
+	lib := make(V)
+	lib.RegisterStruct(X{}, func(interface{}) error { return errors.New("bad") })
+	vd := make(V)
+	if err := vd.Mount("lib.", lib); err != nil {
+		t.Fatal(err)
+	}
+	if errs := vd.Validate(X{}); len(errs) != 1 {
+		t.Fatalf("struct validator not mounted under its own name: %v", errs)
+	}
+	if err := vd.Mount("other.", lib); !errors.Is(err, ErrDuplicateValidator) {
+		t.Fatalf("wrong error for a collision: %v", err)
+	}
+}
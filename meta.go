@@ -0,0 +1,189 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"maps"
+	"reflect"
+)
+
+// meta holds what a V knows about its rules besides their validators:
+// the aliases added with Alias, the values accepted by the validators
+// added with Register or RegisterTyped, the parameter checks added with
+// RegisterParamCheck, the validators for structs added with
+// RegisterStruct, and the V it overlays, if it was made by WithOverlay.
+//
+// It is held in the V itself, under metaKey, so that copying the map
+// copies it. It is never modified once stored: editMeta stores a new
+// one, so that a change to one copy of a V is not seen by the others.
+type meta struct {
+	aliases map[string]string
+	accepts map[string]accepts
+	params  map[string]func(reflect.Type, string) error
+	structs map[reflect.Type]func(interface{}) error
+	base    V
+}
+
+// metaKey is the key of a V's meta. No tag can name it, since rule names
+// cannot contain commas.
+const metaKey = ",meta"
+
+// Error makes a meta the error returned by its holder in a V, which is
+// not a validator.
+func (m *meta) Error() string {
+	return "validate: not a validator, but the metadata of a V"
+}
+
+// meta returns the meta of v, or nil if it has none.
+// It must not be modified.
+func (v V) meta() *meta {
+	if hold := v[metaKey]; hold != nil {
+		m, _ := hold(nil).(*meta)
+		return m
+	}
+	return nil
+}
+
+// editMeta stores a copy of the meta of v, or a new one if v has none,
+// and returns it to be modified. Like assigning to a nil map, editing the
+// meta of a nil V panics.
+func (v V) editMeta() *meta {
+	if v == nil {
+		panic("validate: metadata added to a nil V")
+	}
+	m := new(meta)
+	if old := v.meta(); old != nil {
+		*m = meta{
+			aliases: maps.Clone(old.aliases),
+			accepts: maps.Clone(old.accepts),
+			params:  maps.Clone(old.params),
+			structs: maps.Clone(old.structs),
+			base:    old.base,
+		}
+	}
+	v[metaKey] = func(interface{}) error { return m }
+	return m
+}
+
+// forget removes the alias, accepted values, and parameter check of the
+// rule name from the meta of v.
+func (v V) forget(name string) {
+	m := v.meta()
+	if m == nil {
+		return
+	}
+	_, alias := m.aliases[name]
+	_, acc := m.accepts[name]
+	if alias || acc || m.params[name] != nil {
+		m = v.editMeta()
+		delete(m.aliases, name)
+		delete(m.accepts, name)
+		delete(m.params, name)
+	}
+}
+
+// copyMeta adds the aliases, accepted values, parameter checks, and
+// struct validators of from to v, replacing any of v's of the same names
+// or types.
+func (v V) copyMeta(from V) {
+	fm := from.meta()
+	if fm == nil {
+		return
+	}
+	m := v.editMeta()
+	m.aliases = union(m.aliases, fm.aliases)
+	m.accepts = union(m.accepts, fm.accepts)
+	m.params = union(m.params, fm.params)
+	m.structs = union(m.structs, fm.structs)
+}
+
+// union returns a, with the entries of b added, allocating it if needed.
+func union[K comparable, E any](a, b map[K]E) map[K]E {
+	if len(b) == 0 {
+		return a
+	}
+	if a == nil {
+		a = make(map[K]E, len(b))
+	}
+	maps.Copy(a, b)
+	return a
+}
+
+// A layer is a V in which rules are looked up, with its meta.
+type layer struct {
+	v V
+	m *meta
+}
+
+// layers returns v and the Vs it overlays, in the order rules are looked
+// up in them.
+func (v V) layers() []layer {
+	var ls []layer
+	for v != nil {
+		m := v.meta()
+		ls = append(ls, layer{v, m})
+		if m == nil {
+			break
+		}
+		v = m.base
+	}
+	return ls
+}
+
+// flatten returns a V defining the rules that v defines, with those of
+// the Vs it overlays, holding no overlays of its own.
+func (v V) flatten() V {
+	ls := v.layers()
+	o := make(V)
+	for i := len(ls) - 1; i >= 0; i-- {
+		// A rule defined by a layer hides its definitions below,
+		// in any form.
+		var names []string
+		for key := range ls[i].v {
+			if key != metaKey {
+				names = append(names, ruleName(key))
+			}
+		}
+		if m := ls[i].m; m != nil {
+			for name := range m.aliases {
+				names = append(names, name)
+			}
+		}
+		for _, name := range names {
+			delete(o, name)
+			delete(o, name+"=")
+			o.forget(name)
+		}
+		for key, fn := range ls[i].v {
+			if key != metaKey {
+				o[key] = fn
+			}
+		}
+		o.copyMeta(ls[i].v)
+	}
+	return o
+}
+
+// clone returns a copy of v, overlaying what v overlays.
+func (v V) clone() V {
+	o := maps.Clone(v)
+	if o == nil {
+		o = make(V)
+	}
+	o.copyMeta(v)
+	if m := v.meta(); m != nil && m.base != nil {
+		o.editMeta().base = m.base
+	}
+	return o
+}
+
+// layers returns the layers of the walker's validators, followed by
+// those of its defaults, and the number of the former. They are found
+// once per walker.
+func (w *walker) layers() ([]layer, int) {
+	if w.layerList == nil {
+		w.layerList = append(w.v.layers(), w.defaults.layers()...)
+		w.ownLayers = len(w.layerList) - len(w.defaults.layers())
+	}
+	return w.layerList, w.ownLayers
+}
@@ -0,0 +1,58 @@
+package validate
+
+import (
+	"errors"
+	"maps"
+	"testing"
+)
+
+func TestV_meta(t *testing.T) {
+	type X struct {
+		A string `validate:"word"`
+	}
+
+	vd := V{"nonzero": nonzero}
+	vd.Alias("word", "nonzero")
+	Register(vd, "short", func(s string) error { return nil })
+	vd.RegisterStruct(X{}, func(interface{}) error { return errors.New("bad") })
+
+	copied := make(V)
+	for name, f := range vd {
+		copied[name] = f
+	}
+	for name, c := range map[string]V{
+		"WithOverlay": vd.WithOverlay(nil),
+		"maps.Clone":  maps.Clone(vd),
+		"range":       copied,
+	} {
+		if errs := c.Validate(X{}); len(errs) != 2 {
+			t.Errorf("%s copy lost the alias or struct validator: %v", name, errs)
+		}
+		if errs := c.Check(struct {
+			N int `validate:"short"`
+		}{}); len(errs) != 1 || !errors.Is(errs[0], ErrWrongType) {
+			t.Errorf("%s copy lost the accepted type: %v", name, errs)
+		}
+		c.Alias("word", "short")
+		if tag := vd.meta().aliases["word"]; tag != "nonzero" {
+			t.Errorf("changing the %s copy changed the original's alias to %q", name, tag)
+		}
+	}
+
+	if errs := vd.Check(struct {
+		N int `validate:",meta"`
+	}{}); len(errs) != 1 {
+		t.Errorf("a tag named the metadata: %v", errs)
+	}
+	if cov := new(Coverage).Validators(vd); len(cov) != 2 {
+		t.Errorf("the metadata is counted as a validator: %v", cov)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("no panic adding an alias to a nil V")
+		}
+	}()
+	var nilV V
+	nilV.Alias("word", "nonzero")
+}
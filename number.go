@@ -0,0 +1,122 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func greater(i interface{}, param string) error {
+	c, err := compareNumber(i, param)
+	if err == nil && c <= 0 {
+		err = errorf(ErrOutOfRange, "%v is not greater than %v", i, param)
+	}
+	return err
+}
+
+func atLeast(i interface{}, param string) error {
+	c, err := compareNumber(i, param)
+	if err == nil && c < 0 {
+		err = errorf(ErrOutOfRange, "%v is less than %v", i, param)
+	}
+	return err
+}
+
+func less(i interface{}, param string) error {
+	c, err := compareNumber(i, param)
+	if err == nil && c >= 0 {
+		err = errorf(ErrOutOfRange, "%v is not less than %v", i, param)
+	}
+	return err
+}
+
+func atMost(i interface{}, param string) error {
+	c, err := compareNumber(i, param)
+	if err == nil && c > 0 {
+		err = errorf(ErrOutOfRange, "%v is greater than %v", i, param)
+	}
+	return err
+}
+
+func between(i interface{}, param string) error {
+	bounds := strings.Fields(param)
+	if len(bounds) != 2 {
+		return fmt.Errorf("parameter %q is not two numbers, as in \"between=1 10\"", param)
+	}
+	lo, err := compareNumber(i, bounds[0])
+	if err != nil {
+		return err
+	}
+	hi, err := compareNumber(i, bounds[1])
+	if err != nil {
+		return err
+	}
+	if lo < 0 || hi > 0 {
+		return errorf(ErrOutOfRange, "%v is not between %v and %v", i, bounds[0], bounds[1])
+	}
+	return nil
+}
+
+// numberParam is the parameter check of "gt", "gte", "lt", and "lte".
+func numberParam(t reflect.Type, param string) error {
+	_, err := compareNumber(zeroOf(t, 0.0), param)
+	return err
+}
+
+// betweenParam is the parameter check of "between".
+func betweenParam(t reflect.Type, param string) error {
+	bounds := strings.Fields(param)
+	if len(bounds) != 2 {
+		return fmt.Errorf("parameter %q is not two numbers, as in \"between=1 10\"", param)
+	}
+	for _, b := range bounds {
+		if err := numberParam(t, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// compareNumber returns -1, 0, or +1 as i, a number of any kind, is less
+// than, equal to, or greater than the number param. Integers are compared
+// exactly with integer parameters, and time.Durations may be compared
+// with durations, as in "1m30s". NaN is out of the range of every number.
+func compareNumber(i interface{}, param string) (int, error) {
+	val := reflect.ValueOf(i)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, err := strconv.ParseInt(param, 10, 64); err == nil {
+			return cmp.Compare(val.Int(), n), nil
+		}
+		if val.Type() == durationType {
+			if d, err := time.ParseDuration(param); err == nil {
+				return cmp.Compare(val.Int(), int64(d)), nil
+			}
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if n, err := strconv.ParseUint(param, 10, 64); err == nil {
+			return cmp.Compare(val.Uint(), n), nil
+		}
+	case reflect.Float32, reflect.Float64:
+	default:
+		return 0, errorf(ErrWrongType, "%T is not a number", i)
+	}
+
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil || math.IsNaN(n) {
+		return 0, fmt.Errorf("parameter %q is not a number", param)
+	}
+	x, _ := toFloat(val)
+	if math.IsNaN(x) {
+		return 0, errorf(ErrOutOfRange, "%v cannot be compared with %v", x, param)
+	}
+	return cmp.Compare(x, n), nil
+}
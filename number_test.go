@@ -0,0 +1,98 @@
+package validate
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCompareNumber(t *testing.T) {
+	type level uint8
+
+	tests := []struct {
+		value interface{}
+		param string
+		want  int
+	}{
+		{3, "2", 1},
+		{int8(-3), "-3", 0},
+		{level(7), "10", -1},
+		{uint64(math.MaxUint64), "18446744073709551614", 1},
+		{int64(math.MaxInt64), "9223372036854775806", 1},
+		{uint(1), "-1", 1},
+		{2, "2.5", -1},
+		{float32(2.5), "2.5", 0},
+		{math.Inf(1), "1e308", 1},
+		{90 * time.Second, "1m30s", 0},
+		{time.Second, "1000000000", 0},
+	}
+	for _, test := range tests {
+		got, err := compareNumber(test.value, test.param)
+		if err != nil || got != test.want {
+			t.Errorf("compareNumber(%v, %q) = %d, %v; wanted %d", test.value, test.param, got, err, test.want)
+		}
+	}
+
+	if _, err := compareNumber("1", "0"); !errors.Is(err, ErrWrongType) {
+		t.Error("wrong error for a string:", err)
+	}
+	if _, err := compareNumber(1, "one"); err == nil || errors.Is(err, ErrWrongType) {
+		t.Error("wrong error for a bad parameter:", err)
+	}
+	if _, err := compareNumber(1, "NaN"); err == nil || errors.Is(err, ErrWrongType) {
+		t.Error("wrong error for a NaN parameter:", err)
+	}
+	for _, rule := range []func(interface{}, string) error{greater, atLeast, less, atMost} {
+		if err := rule(math.NaN(), "100"); !errors.Is(err, ErrOutOfRange) {
+			t.Error("wrong error for NaN:", err)
+		}
+	}
+	if err := between(float32(math.NaN()), "0 100"); !errors.Is(err, ErrOutOfRange) {
+		t.Error("wrong error for NaN between bounds:", err)
+	}
+	if _, err := compareNumber(1, "1s"); err == nil {
+		t.Error("compared an int with a duration")
+	}
+}
+
+func TestV_Validate_numbers(t *testing.T) {
+	type X struct {
+		Percent float64       `validate:"gte=0,lte=100"`
+		Count   *uint         `validate:"gt=0,lt=10"`
+		Timeout time.Duration `validate:"between=1s 1m"`
+		Bad     int           `validate:"between=1"`
+	}
+
+	n := uint(10)
+	errs := Builtin().Validate(X{Percent: 100.5, Count: &n, Timeout: time.Minute})
+	want := []string{
+		"field Percent is invalid: 100.5 is greater than 100",
+		"field Count is invalid: 10 is not less than 10",
+		`field Bad is invalid: parameter "1" is not two numbers, as in "between=1 10"`,
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+	for i, err := range errs {
+		if err.Error() != want[i] {
+			t.Errorf("error %d is %q, wanted %q", i, err, want[i])
+		}
+	}
+	if !errors.Is(errs[0], ErrOutOfRange) {
+		t.Error("out of range number not reported with ErrOutOfRange")
+	}
+}
+
+func TestV_Validate_NaN(t *testing.T) {
+	type X struct {
+		Percent float64 `validate:"lte=100"`
+	}
+	errs := Builtin().Validate(X{Percent: math.NaN()})
+	if len(errs) != 1 || !errors.Is(errs[0], ErrOutOfRange) {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+	if want := "field Percent is invalid: NaN cannot be compared with 100"; errs[0].Error() != want {
+		t.Errorf("error is %q, wanted %q", errs[0], want)
+	}
+}
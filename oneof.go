@@ -0,0 +1,61 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// oneOf reports values that are not equal, as decided by c, to one of
+// the space-separated values given as param, parsed as values of
+// their type.
+func oneOf(c Comparers, i interface{}, param string) error {
+	values := strings.Fields(param)
+	for _, s := range values {
+		x, err := parseValue(reflect.TypeOf(i), s)
+		if errors.Is(err, ErrWrongType) {
+			return err
+		}
+		if err == nil && c.Equal(i, x) {
+			return nil
+		}
+	}
+	return errorf(ErrNotAllowed, "%v is not one of %v", i, values)
+}
+
+// oneOfParam is the parameter check of "oneof", which needs values of
+// the field's type.
+func oneOfParam(t reflect.Type, param string) error {
+	values := strings.Fields(param)
+	if len(values) == 0 {
+		return fmt.Errorf("parameter %q is not a list of values, as in \"red green\"", param)
+	}
+	if t == nil {
+		return nil
+	}
+	for _, s := range values {
+		if _, err := parseValue(t, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enumSchema sets the values allowed by s to those given to r, as numbers
+// if s describes numbers.
+func enumSchema(r Rule, s *JSONSchema) {
+	s.Enum = nil
+	for _, v := range r.Params() {
+		if s.Type != "number" && s.Type != "integer" {
+			s.Enum = append(s.Enum, v)
+			continue
+		}
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			s.Enum = append(s.Enum, n)
+		}
+	}
+}
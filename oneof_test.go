@@ -0,0 +1,45 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestV_Validate_oneof(t *testing.T) {
+	type color string
+	type X struct {
+		Color color   `validate:"oneof=red green blue"`
+		Size  uint8   `validate:"oneof=1 2 3"`
+		Ratio float64 `validate:"oneof=0.5 1"`
+		Plan  string  `validate:"omitempty,oneof=free pro"`
+	}
+
+	errs := Builtin().Validate(X{Color: "purple", Size: 4, Ratio: 0.5})
+	want := []string{
+		"field Color is invalid: purple is not one of [red green blue]",
+		"field Size is invalid: 4 is not one of [1 2 3]",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+	for i, err := range errs {
+		if err.Error() != want[i] || !errors.Is(err, ErrNotAllowed) {
+			t.Errorf("error %d is %q, wanted %q", i, err, want[i])
+		}
+	}
+
+	if errs := Builtin().Validate(X{Color: "red", Size: 3, Ratio: 1, Plan: "pro"}); len(errs) != 0 {
+		t.Fatalf("wrong errors for valid values: %v", errs)
+	}
+}
+
+func ExampleBuiltin_oneof() {
+	type Shirt struct {
+		Size string `validate:"oneof=S M L XL"`
+	}
+
+	fmt.Println(Builtin().Validate(Shirt{"XXL"}))
+
+	// Output: [field Size is invalid: XXL is not one of [S M L XL]]
+}
@@ -0,0 +1,54 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import "sort"
+
+// OpenAPISchema returns an OpenAPI 3.1 Schema Object describing the struct x,
+// or the struct it points to, as it is validated by v, for use in the
+// components or request bodies of an API's description. The schema is
+// that of SchemaFor, less its "$schema".
+//
+// The schema is to be placed in the document at base, a JSON pointer such
+// as "#/components/schemas/Pet", to which the references within recursive
+// structs are made relative.
+func OpenAPISchema(x interface{}, v V, base string) (*JSONSchema, error) {
+	return describeStruct(x, v, base)
+}
+
+// OpenAPIParameter is an OpenAPI 3.1 Parameter Object.
+type OpenAPIParameter struct {
+	Name     string      `json:"name"`
+	In       string      `json:"in"`
+	Required bool        `json:"required,omitempty"`
+	Schema   *JSONSchema `json:"schema"`
+}
+
+// OpenAPIParameters returns the parameters of an operation, located by in,
+// such as "query" or "header", described by the fields of the struct x as
+// for OpenAPISchema, in the order of their names. Parameters in "path"
+// are always required, as OpenAPI demands.
+func OpenAPIParameters(x interface{}, v V, in string) ([]OpenAPIParameter, error) {
+	s, err := OpenAPISchema(x, v, "#")
+	if err != nil {
+		return nil, err
+	}
+
+	required := make(map[string]bool, len(s.Required))
+	for _, name := range s.Required {
+		required[name] = true
+	}
+	ps := make([]OpenAPIParameter, 0, len(s.Properties))
+	for name, p := range s.Properties {
+		ps = append(ps, OpenAPIParameter{
+			Name:     name,
+			In:       in,
+			Required: required[name] || in == "path",
+			Schema:   p,
+		})
+	}
+	sort.Slice(ps, func(i, j int) bool {
+		return ps[i].Name < ps[j].Name
+	})
+	return ps, nil
+}
@@ -0,0 +1,45 @@
+package validate
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOpenAPISchema(t *testing.T) {
+	s, err := OpenAPISchema(testNode{}, V{}, "#/components/schemas/Node")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(s)
+	want := `{"type":"object","properties":{"Next":{"anyOf":[{"$ref":"#/components/schemas/Node","type":"object"},{"type":"null"}]},"Value":{"type":"integer"}}}`
+	if string(b) != want {
+		t.Fatalf("wrong schema: %s, wanted %s", b, want)
+	}
+}
+
+func TestOpenAPIParameters(t *testing.T) {
+	type Query struct {
+		ID    string   `json:"id"`
+		Limit int      `json:"limit" validate:"required"`
+		Sort  []string `json:"sort" validate:"each,alpha"`
+	}
+
+	ps, err := OpenAPIParameters(Query{}, Builtin(), "query")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(ps)
+	want := `[{"name":"id","in":"query","schema":{"type":"string"}},` +
+		`{"name":"limit","in":"query","required":true,"schema":{"type":"integer"}},` +
+		`{"name":"sort","in":"query","schema":{"type":"array","items":{"type":"string","pattern":"^\\p{L}+$"}}}]`
+	if string(b) != want {
+		t.Fatalf("wrong parameters: %s, wanted %s", b, want)
+	}
+
+	ps, _ = OpenAPIParameters(Query{}, Builtin(), "path")
+	for _, p := range ps {
+		if !p.Required {
+			t.Fatalf("path parameter %s is not required", p.Name)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import "reflect"
+
+// Optional is implemented by wrappers that may or may not hold a value,
+// such as generic Optional[T] or Maybe[T] types, to expose it to Validate.
+// A field whose type implements Optional is validated as though it were
+// the value it holds. If it holds no value, it fails "required",
+// and its other rules are skipped.
+//
+//	func (o Optional[T]) OptionalValue() (interface{}, bool) {
+//		return o.v, o.ok
+//	}
+//
+// The value a wrapper holds is not settable, even if the struct
+// containing it was passed by pointer.
+type Optional interface {
+	OptionalValue() (value interface{}, ok bool)
+}
+
+var optionalType = reflect.TypeOf((*Optional)(nil)).Elem()
+
+// unwrap returns the value held by fv, if it is an Optional.
+// The result is invalid if fv holds no value.
+func unwrap(fv reflect.Value) reflect.Value {
+	var o Optional
+	switch {
+	case (fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface) && fv.IsNil():
+		return fv
+	case fv.Type().Implements(optionalType):
+		o = fv.Interface().(Optional)
+	case fv.CanAddr() && fv.Addr().Type().Implements(optionalType):
+		o = fv.Addr().Interface().(Optional)
+	default:
+		return fv
+	}
+	v, ok := o.OptionalValue()
+	if !ok {
+		return reflect.Value{}
+	}
+	return reflect.ValueOf(v)
+}
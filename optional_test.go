@@ -0,0 +1,49 @@
+package validate
+
+import (
+	"fmt"
+	"testing"
+)
+
+type testOptional[T any] struct {
+	v  T
+	ok bool
+}
+
+func (o testOptional[T]) OptionalValue() (interface{}, bool) {
+	return o.v, o.ok
+}
+
+func TestV_Validate_optional(t *testing.T) {
+	type X struct {
+		A testOptional[int]  `validate:"odd"`
+		B testOptional[int]  `validate:"odd"`
+		C testOptional[Span] `validate:"method=Check"`
+		D testOptional[int]  `validate:"odd"`
+	}
+
+	vd := make(V)
+	vd["odd"] = func(i interface{}) error {
+		if i.(int)%2 == 0 {
+			return fmt.Errorf("%d should be odd", i)
+		}
+		return nil
+	}
+
+	x := X{
+		A: testOptional[int]{1, true},
+		B: testOptional[int]{2, true},
+		C: testOptional[Span]{Span{2, 1}, true},
+		D: testOptional[int]{2, false},
+	}
+	errs := vd.Validate(x)
+	if len(errs) != 2 {
+		t.Fatalf("wrong number of errors: %v", errs)
+	}
+	if bf := errs[0].(BadField); bf.Field != "B" || bf.Value != 2 {
+		t.Fatalf("wrong error for a wrapped value: %+v", bf)
+	}
+	if bf := errs[1].(BadField); bf.Field != "C" {
+		t.Fatalf("wrong error for a wrapped method: %+v", bf)
+	}
+}
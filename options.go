@@ -0,0 +1,259 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"context"
+	"reflect"
+	"strings"
+)
+
+// Option configures a validation done by ValidateOpts.
+type Option func(*walker)
+
+// ValidateOpts behaves like Validate, as configured by opts.
+func (v V) ValidateOpts(s interface{}, opts ...Option) []error {
+	w := walker{v: v}
+	for _, o := range opts {
+		o(&w)
+	}
+	return w.validate(reflect.ValueOf(s), nil)
+}
+
+// NameTags reports fields by the names in the given tags,
+// as described for ValidateAndTag.
+func NameTags(tags ...string) Option {
+	return func(w *walker) {
+		w.nameTags = tags
+	}
+}
+
+// TagNameFunc sets the function that extracts a field's name from the value
+// of one of its name tags, for conventions that differ from TagName's.
+// If fn returns "", the next name tag is tried.
+func TagNameFunc(fn func(value string) string) Option {
+	return func(w *walker) {
+		w.tagName = fn
+	}
+}
+
+// FieldNameFunc sets a function naming fields in errors, for names that
+// are derived from Go's, such as snake_case ones, or kept apart from the
+// struct. If fn returns "", the field is named by the name tags, if any,
+// or its Go name. Like the names from NameTags, the names from fn are
+// used in the paths of the Rules option.
+func FieldNameFunc(fn func(f reflect.StructField) string) Option {
+	return func(w *walker) {
+		w.nameFunc = fn
+	}
+}
+
+// TagName extracts a field name from the value of a name tag in the manner
+// of encoding/json: options after the first comma are removed,
+// and the value "-" names no field.
+//
+//	TagName("height,omitempty") == "height"
+//	TagName(",omitempty") == ""
+//	TagName("-") == ""
+//	TagName("-,") == "-"
+func TagName(value string) string {
+	if value == "-" {
+		return ""
+	}
+	name, _, _ := strings.Cut(value, ",")
+	return name
+}
+
+// Context checks ctx during validation, as described for ValidateContext,
+// and passes it to extended validators.
+func Context(ctx context.Context) Option {
+	return func(w *walker) {
+		w.ctx = ctx
+	}
+}
+
+// TagKey reads the rules of fields from the tags with the given key,
+// rather than "validate", so that structs already tagged for other
+// packages, such as with "binding", can be validated without being
+// tagged again. The rules are written in the same syntax, and
+// fields tagged "-" are skipped as usual.
+func TagKey(key string) Option {
+	return func(w *walker) {
+		w.tagKey = key
+	}
+}
+
+// Rules adds rules to fields as though they were appended to the fields'
+// validate tags, so that rules kept apart from a type's declaration, such as
+// those read from a manifest, can be applied to it. The keys of rules are
+// the paths of fields: their names, as chosen by NameTags, joined by dots,
+// regardless of Root and PathFormat. A field reached only through rules
+// must be reached through "struct" rules as usual.
+func Rules(rules map[string]string) Option {
+	return func(w *walker) {
+		w.rules = rules
+	}
+}
+
+// Defaults supplies the validators for rules that are not defined in the V
+// being used, so that a nil or partly populated V can still rely on a common
+// set of validators. The V's own validators take precedence over d's,
+// and d's take precedence over the V's fallback. d's fallback is not used.
+func Defaults(d V) Option {
+	return func(w *walker) {
+		w.defaults = d
+	}
+}
+
+// Groups chooses the groups whose rules apply, in addition to the rules
+// scoped to no groups, as described in the package documentation.
+func Groups(groups ...string) Option {
+	return func(w *walker) {
+		w.groups = groups
+	}
+}
+
+// FailFast stops validation at the first invalid field, so that at most
+// one error is reported. It suits callers that only need to know whether
+// a value is valid, and spares them checking the rest of a large struct.
+func FailFast() Option {
+	return func(w *walker) {
+		w.failFast = true
+	}
+}
+
+// Deep validates the structs nested within a struct as though their fields
+// were tagged "struct", so that deeply nested types need not be tagged at
+// every level. It descends into struct fields, pointers to structs, and the
+// elements of slices, arrays, and maps of them, reporting the fields of
+// elements with their indexes or keys, as the "each" and "values" rules do.
+// Fields already reached by "struct" rules are not validated twice, and
+// fields tagged "omitempty" are not descended into when they hold
+// the zero value.
+func Deep() Option {
+	return func(w *walker) {
+		w.deep = true
+	}
+}
+
+// MaxDepth limits the depth to which structs nested within a struct are
+// validated, through "struct" rules or the Deep option, to n levels.
+// A struct nested more deeply is not validated, but reported with an error
+// wrapping ErrMaxDepth. A struct within itself, reached again through
+// a pointer in a cycle, is never validated twice, regardless of depth.
+func MaxDepth(n int) Option {
+	return func(w *walker) {
+		w.maxDepth = n
+	}
+}
+
+// NoRecover lets panics in validators propagate to the caller, rather than
+// be reported as errors wrapping ErrValidatorPanicked, for programs that
+// would rather crash, as during development.
+func NoRecover() Option {
+	return func(w *walker) {
+		w.noRecover = true
+	}
+}
+
+// NilPolicy decides how validators treat fields holding nil pointers.
+// It does not affect the reserved rules, such as "required".
+type NilPolicy int
+
+const (
+	NilSkip    NilPolicy = iota // validators are not called
+	NilInvalid                  // the field is reported, with an error wrapping ErrRequired
+	NilPass                     // validators are passed the nil pointer
+)
+
+// Workers sets the number of goroutines with which ValidateStream
+// validates items, which is GOMAXPROCS if n is less than 1.
+func Workers(n int) Option {
+	return func(w *walker) {
+		w.workers = n
+	}
+}
+
+// NoValidatable makes Validate skip the methods of values implementing
+// Validatable or FieldsValidatable, so that they can validate themselves
+// with Validate.
+func NoValidatable() Option {
+	return func(w *walker) {
+		w.noValidatable = true
+	}
+}
+
+// NilPointers sets how validators treat fields holding nil pointers.
+// The default is NilSkip.
+func NilPointers(p NilPolicy) Option {
+	return func(w *walker) {
+		w.nils = p
+	}
+}
+
+// PathFormat sets the function that joins the names along the path to a
+// nested field into the name reported in errors. The default is DotPath.
+// The elements of slices and arrays checked by "each" appear in paths
+// as their indexes in brackets, such as "[3]".
+func PathFormat(fn func(path []string) string) Option {
+	return func(w *walker) {
+		w.format = fn
+	}
+}
+
+// Root adds name to the start of the path of every field,
+// such as the name of the request parameter holding the struct.
+func Root(name string) Option {
+	return func(w *walker) {
+		w.root = name
+	}
+}
+
+// DotPath joins a path with dots, as in "a.b.c".
+// Indexes are appended to the names before them, as in "a[3].b".
+func DotPath(path []string) string {
+	var b strings.Builder
+	for i, p := range path {
+		if i > 0 && !isIndex(p) {
+			b.WriteByte('.')
+		}
+		b.WriteString(p)
+	}
+	return b.String()
+}
+
+// SlashPath joins a path with slashes, as in "a/b/c".
+// Indexes are written without brackets, as in "a/3/b".
+func SlashPath(path []string) string {
+	parts := make([]string, len(path))
+	for i, p := range path {
+		if isIndex(p) {
+			p = p[1 : len(p)-1]
+		}
+		parts[i] = p
+	}
+	return strings.Join(parts, "/")
+}
+
+// BracketPath joins a path in the style of HTML form parameters,
+// as in "a[b][c]". Indexes are written as they are, as in "a[3][b]".
+func BracketPath(path []string) string {
+	if len(path) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(path[0])
+	for _, p := range path[1:] {
+		if !isIndex(p) {
+			p = "[" + p + "]"
+		}
+		b.WriteString(p)
+	}
+	return b.String()
+}
+
+// isIndex reports whether the path segment p is the index of an element,
+// such as "[3]".
+func isIndex(p string) bool {
+	return strings.HasPrefix(p, "[") && strings.HasSuffix(p, "]")
+}
@@ -0,0 +1,386 @@
+package validate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestTagName(t *testing.T) {
+	tests := map[string]string{
+		"":                 "",
+		"height":           "height",
+		"height,omitempty": "height",
+		",omitempty":       "",
+		"-":                "",
+		"-,":               "-",
+	}
+	for value, name := range tests {
+		if n := TagName(value); n != name {
+			t.Fatalf("TagName(%q) = %q, wanted %q", value, n, name)
+		}
+	}
+}
+
+func TestV_ValidateOpts_names(t *testing.T) {
+	type X struct {
+		A int `validate:"odd" json:"a,omitempty"`
+		B int `validate:"odd" json:",omitempty"`
+		C int `validate:"odd" protobuf:"varint,3,opt,name=see"`
+	}
+
+	vd := make(V)
+	vd["odd"] = func(i interface{}) error {
+		return fmt.Errorf("not odd")
+	}
+
+	names := func(errs []error) string {
+		var fields []string
+		for _, err := range errs {
+			fields = append(fields, err.(BadField).Field)
+		}
+		return fmt.Sprint(fields)
+	}
+
+	if n := names(vd.ValidateAndTag(X{}, "json")); n != "[a B C]" {
+		t.Fatalf("wrong names from json tags: %s", n)
+	}
+
+	protoName := func(value string) string {
+		for _, opt := range strings.Split(value, ",") {
+			if strings.HasPrefix(opt, "name=") {
+				return strings.TrimPrefix(opt, "name=")
+			}
+		}
+		return ""
+	}
+	errs := vd.ValidateOpts(X{}, NameTags("protobuf"), TagNameFunc(protoName))
+	if n := names(errs); n != "[A B see]" {
+		t.Fatalf("wrong names from protobuf tags: %s", n)
+	}
+}
+
+func TestV_ValidateOpts_paths(t *testing.T) {
+	type X struct {
+		A int `validate:"odd"`
+	}
+	type Y struct {
+		X X   `validate:"struct"`
+		B int `validate:"odd"`
+	}
+
+	vd := make(V)
+	vd["odd"] = func(i interface{}) error {
+		return fmt.Errorf("not odd")
+	}
+
+	tests := []struct {
+		opts  []Option
+		names string
+	}{
+		{nil, "[X.A B]"},
+		{[]Option{PathFormat(SlashPath)}, "[X/A B]"},
+		{[]Option{PathFormat(BracketPath)}, "[X[A] B]"},
+		{[]Option{Root("y")}, "[y.X.A y.B]"},
+		{[]Option{Root("y"), PathFormat(BracketPath)}, "[y[X][A] y[B]]"},
+	}
+
+	for _, test := range tests {
+		var fields []string
+		for _, err := range vd.ValidateOpts(Y{}, test.opts...) {
+			fields = append(fields, err.(BadField).Field)
+		}
+		if n := fmt.Sprint(fields); n != test.names {
+			t.Fatalf("wrong names: %s, wanted %s", n, test.names)
+		}
+	}
+}
+
+func TestV_ValidateOpts_defaults(t *testing.T) {
+	type Y struct {
+		A int `validate:"odd"`
+	}
+	type X struct {
+		Y `validate:"struct"`
+		B int `validate:"-"`
+		C int `validate:"odd"`
+	}
+
+	defaults := V{
+		"odd": func(i interface{}) error {
+			if i.(int)%2 == 0 {
+				return fmt.Errorf("%d should be odd", i)
+			}
+			return nil
+		},
+	}
+
+	var vd V
+	if errs := vd.ValidateOpts(X{}, Rules(map[string]string{"B": "odd"})); len(errs) != 2 {
+		t.Fatalf("wrong number of errors for a nil V: %v", errs)
+	}
+	errs := vd.ValidateOpts(X{Y{1}, 2, 2}, Defaults(defaults), Rules(map[string]string{"B": "odd"}))
+	if len(errs) != 1 || errs[0].(BadField).Field != "C" {
+		t.Fatalf("wrong errors with defaults: %v", errs)
+	}
+
+	vd = V{"odd": func(i interface{}) error { return nil }}
+	if errs := vd.ValidateOpts(X{}, Defaults(defaults)); len(errs) != 0 {
+		t.Fatalf("defaults took precedence: %v", errs)
+	}
+}
+
+func TestPathFormats_index(t *testing.T) {
+	path := []string{"a", "[3]", "b"}
+	if s := DotPath(path); s != "a[3].b" {
+		t.Fatalf("wrong DotPath: %s", s)
+	}
+	if s := SlashPath(path); s != "a/3/b" {
+		t.Fatalf("wrong SlashPath: %s", s)
+	}
+	if s := BracketPath(path); s != "a[3][b]" {
+		t.Fatalf("wrong BracketPath: %s", s)
+	}
+}
+
+func TestV_ValidateOpts_nilPointers(t *testing.T) {
+	type X struct {
+		A *int  `validate:"odd"`
+		B *int  `validate:"odd,odd"`
+		C **int `validate:"odd"`
+		D *int  `validate:"required,odd"`
+	}
+
+	vd := V{
+		"odd": func(i interface{}) error {
+			n, ok := i.(int)
+			if !ok {
+				return fmt.Errorf("%T is not an int", i)
+			}
+			if n%2 == 0 {
+				return fmt.Errorf("%d should be odd", n)
+			}
+			return nil
+		},
+	}
+
+	two := 2
+	p := &two
+	errs := vd.Validate(X{A: &two, C: &p})
+	if fmt.Sprint(errs) != "[field A is invalid: 2 should be odd field C is invalid: 2 should be odd field D is invalid: is required]" {
+		t.Fatalf("wrong errors with NilSkip: %v", errs)
+	}
+	if errs[0].(BadField).Value != 2 {
+		t.Fatalf("wrong value for a pointer: %v", errs[0].(BadField).Value)
+	}
+
+	errs = vd.ValidateOpts(X{A: &two, C: &p}, NilPointers(NilInvalid))
+	if len(errs) != 5 || errs[1].(BadField).Field != "B" || !errors.Is(errs[1].(BadField).Err, ErrRequired) {
+		t.Fatalf("wrong errors with NilInvalid: %v", errs)
+	}
+
+	errs = vd.ValidateOpts(X{A: &two, C: &p}, NilPointers(NilPass))
+	if len(errs) != 6 || errs[1].Error() != "field B is invalid: *int is not an int" {
+		t.Fatalf("wrong errors with NilPass: %v", errs)
+	}
+}
+
+func TestV_ValidateOpts_failFast(t *testing.T) {
+	type Y struct {
+		A int `validate:"odd"`
+		B int `validate:"odd"`
+	}
+	type X struct {
+		Y Y   `validate:"struct"`
+		C int `validate:"odd"`
+	}
+
+	calls := 0
+	vd := V{
+		"odd": func(i interface{}) error {
+			calls++
+			if i.(int)%2 == 0 {
+				return fmt.Errorf("%d should be odd", i)
+			}
+			return nil
+		},
+	}
+
+	errs := vd.ValidateOpts(X{Y{1, 2}, 4}, FailFast())
+	if len(errs) != 1 || errs[0].(BadField).Field != "Y.B" || calls != 2 {
+		t.Fatalf("wrong errors %v after %d calls", errs, calls)
+	}
+	if errs := vd.ValidateOpts(X{Y{1, 1}, 1}, FailFast()); errs != nil {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+}
+
+func TestContext(t *testing.T) {
+	type X struct {
+		A string `validate:"locale"`
+	}
+
+	vd := make(V)
+	vd.RegisterField("locale", func(f Field) error {
+		return fmt.Errorf("in %s", LocaleFrom(f.Context))
+	})
+	ctx := WithLocale(context.Background(), "fr")
+	errs := vd.ValidateOpts(X{}, Context(ctx), NameTags("json"))
+	if len(errs) != 1 || errs[0].(BadField).Err.Error() != "in fr" {
+		t.Fatalf("context did not reach the validator: %v", errs)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	errs = vd.ValidateOpts(X{}, Context(ctx))
+	if len(errs) != 1 || !errors.Is(errs[0], ErrValidationCanceled) {
+		t.Fatalf("validation was not canceled: %v", errs)
+	}
+}
+
+func TestDeep(t *testing.T) {
+	type Leaf struct {
+		N int `validate:"nonzero"`
+	}
+	type Branch struct {
+		Leaf   Leaf
+		Ptr    *Leaf
+		Leaves []Leaf
+		ByName map[string]*Leaf
+		Any    interface{}
+		Tagged Leaf   `validate:"struct"`
+		Each   []Leaf `validate:"each,struct"`
+		Opt    Leaf   `validate:"omitempty"`
+	}
+	type Tree struct {
+		Branch Branch
+	}
+
+	tree := Tree{Branch{
+		Ptr:    &Leaf{},
+		Leaves: []Leaf{{1}, {}},
+		ByName: map[string]*Leaf{"a": {}, "b": nil},
+		Any:    Leaf{},
+		Each:   []Leaf{{}},
+	}}
+	var fields []string
+	for _, err := range Builtin().ValidateOpts(tree, Deep()) {
+		fields = append(fields, err.(BadField).Field)
+	}
+	want := "[Branch.Leaf.N Branch.Ptr.N Branch.Leaves[1].N Branch.ByName[a].N Branch.Any.N Branch.Tagged.N Branch.Each[0].N]"
+	if fmt.Sprint(fields) != want {
+		t.Fatalf("wrong invalid fields: %v, wanted %s", fields, want)
+	}
+
+	if errs := Builtin().Validate(tree); len(errs) != 0 {
+		t.Fatalf("nested structs validated without Deep: %v", errs)
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	vd := V{"odd": func(i interface{}) error {
+		if i.(int)%2 == 0 {
+			return errors.New("is even")
+		}
+		return nil
+	}}
+
+	a := &testNode{Value: 2}
+	b := &testNode{Value: 4, Next: a}
+	a.Next = b
+	var fields []string
+	for _, err := range vd.ValidateOpts(a, Deep()) {
+		fields = append(fields, err.(BadField).Field)
+	}
+	if fmt.Sprint(fields) != "[Value Next.Value]" {
+		t.Fatalf("wrong invalid fields in a cycle: %v", fields)
+	}
+
+	c := &testNode{Value: 1, Next: &testNode{Value: 3, Next: &testNode{Value: 6}}}
+	errs := vd.ValidateOpts(c, MaxDepth(1))
+	if len(errs) != 1 || !errors.Is(errs[0], ErrMaxDepth) || errs[0].(BadField).Field != "Next.Next" {
+		t.Fatalf("wrong errors beyond the maximum depth: %v", errs)
+	}
+	if errs := vd.ValidateOpts(c, MaxDepth(2)); len(errs) != 1 || errs[0].(BadField).Field != "Next.Next.Value" {
+		t.Fatalf("wrong errors within the maximum depth: %v", errs)
+	}
+}
+
+func TestFieldNameFunc(t *testing.T) {
+	type Y struct {
+		ServerPort int `validate:"odd"`
+	}
+	type X struct {
+		UserName string `validate:"odd" json:"user"`
+		Inner    Y      `validate:"struct" json:"inner"`
+		Skip     int    `validate:"odd" json:"skip"`
+	}
+
+	snake := func(f reflect.StructField) string {
+		if f.Name == "Skip" {
+			return ""
+		}
+		var b strings.Builder
+		for i, r := range f.Name {
+			if unicode.IsUpper(r) && i > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		}
+		return b.String()
+	}
+	vd := V{"odd": func(interface{}) error { return errors.New("is even") }}
+	var fields []string
+	for _, err := range vd.ValidateOpts(X{}, FieldNameFunc(snake), NameTags("json")) {
+		fields = append(fields, err.(BadField).Field)
+	}
+	if fmt.Sprint(fields) != "[user_name inner.server_port skip]" {
+		t.Fatalf("wrong field names: %v", fields)
+	}
+}
+
+func TestTagKey(t *testing.T) {
+	type Y struct {
+		B string `binding:"nonempty"`
+	}
+	type X struct {
+		A string `binding:"nonempty" validate:"email"`
+		Y Y      `binding:"struct"`
+		C string `binding:"-" validate:"nonempty"`
+	}
+
+	var fields []string
+	for _, err := range Builtin().ValidateOpts(X{}, TagKey("binding")) {
+		fields = append(fields, err.(BadField).Field+" "+err.(BadField).Rule)
+	}
+	if fmt.Sprint(fields) != "[A nonempty Y.B nonempty]" {
+		t.Fatalf("wrong errors for binding tags: %v", fields)
+	}
+
+	fields = nil
+	for _, err := range Builtin().Validate(X{}) {
+		fields = append(fields, err.(BadField).Field+" "+err.(BadField).Rule)
+	}
+	if fmt.Sprint(fields) != "[A email C nonempty]" {
+		t.Fatalf("wrong errors for validate tags: %v", fields)
+	}
+}
+
+func TestNoRecover(t *testing.T) {
+	type X struct {
+		A int `validate:"boom"`
+	}
+	vd := V{"boom": func(interface{}) error { panic("boom") }}
+
+	defer func() {
+		if p := recover(); p != "boom" {
+			t.Fatalf("wrong panic: %v", p)
+		}
+	}()
+	vd.ValidateOpts(X{}, NoRecover())
+	t.Fatal("did not panic")
+}
@@ -0,0 +1,101 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"sync"
+)
+
+// Parallel validates the fields of each struct with up to n goroutines,
+// for structs whose validators are slow, such as those making network
+// requests. The errors are the same, and in the same order, as those found
+// validating the fields one at a time, but the validators must be safe to
+// call concurrently, and those of a field may be called even when a failure
+// in an earlier field stops validation, as with FailFast. Fields are
+// validated one at a time if n is less than 2.
+func Parallel(n int) Option {
+	return func(w *walker) {
+		w.parallel = n
+	}
+}
+
+// parallelFields validates the fields of the struct in val, found at path,
+// as the walker's loop over them would, but with up to w.parallel fields at
+// a time, each with a walker of its own. Their failures are reported once
+// every field has been validated, in the order of the fields.
+func (w *walker) parallelFields(errs []error, val reflect.Value, path []string) []error {
+	type failure struct {
+		rec AuditRecord
+		bf  BadField
+	}
+	type result struct {
+		failed []failure
+		errs   []error // errors other than failures, such as cancellation
+		panic  interface{}
+	}
+
+	fields := w.fields(val.Type())
+	results := make([]result, 0, len(fields))
+	sem := make(chan struct{}, w.parallel)
+	var wg sync.WaitGroup
+	var canceled error
+	for _, fi := range fields {
+		if w.ctx != nil {
+			if canceled = w.ctx.Err(); canceled != nil {
+				break
+			}
+		}
+		results = append(results, result{})
+		r := &results[len(results)-1]
+
+		// The fields of nested structs are validated one at a time,
+		// so that there are never more than w.parallel goroutines.
+		c := *w
+		c.parallel = 0
+		c.visiting = maps.Clone(w.visiting)
+		var rec AuditRecord
+		c.audit = AuditFunc(func(ar AuditRecord) { rec = ar })
+		c.yield = func(bf BadField) bool {
+			r.failed = append(r.failed, failure{rec, bf})
+			return true
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				r.panic = recover()
+				<-sem
+				wg.Done()
+			}()
+			r.errs = c.field(nil, val, fi, path)
+		}()
+	}
+	wg.Wait()
+
+	for _, r := range results {
+		if r.panic != nil {
+			panic(r.panic)
+		}
+	}
+	for _, r := range results {
+		for _, f := range r.failed {
+			if w.done {
+				return errs
+			}
+			errs = w.report(errs, f.rec, f.bf)
+		}
+		if len(r.errs) > 0 {
+			w.done = true
+			return append(errs, r.errs...)
+		}
+	}
+	if canceled != nil && !w.done {
+		w.done = true
+		errs = append(errs, fmt.Errorf("%w: %v", ErrValidationCanceled, canceled))
+	}
+	return errs
+}
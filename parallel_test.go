@@ -0,0 +1,98 @@
+package validate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParallel(t *testing.T) {
+	type Y struct {
+		C int `validate:"slow"`
+	}
+	type X struct {
+		A int `validate:"slow"`
+		B int `validate:"slow,odd"`
+		Y Y   `validate:"struct"`
+		D int `validate:"slow"`
+	}
+
+	// Each call to slow waits for the others, which it can only do if
+	// they are called concurrently.
+	var wg sync.WaitGroup
+	wg.Add(4)
+	vd := V{
+		"slow": func(i interface{}) error {
+			wg.Done()
+			done := make(chan struct{})
+			go func() {
+				wg.Wait()
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				return errors.New("called one at a time")
+			}
+			return fmt.Errorf("%d is slow", i)
+		},
+		"odd": func(i interface{}) error {
+			return fmt.Errorf("%d is odd", i)
+		},
+	}
+
+	var audited []string
+	errs := vd.ValidateOpts(X{1, 2, Y{3}, 4}, Parallel(4), Audit(AuditFunc(func(r AuditRecord) {
+		audited = append(audited, r.Field)
+	})))
+	want := []string{
+		"field A is invalid: 1 is slow",
+		"field B is invalid: 2 is slow",
+		"field B is invalid: 2 is odd",
+		"field Y.C is invalid: 3 is slow",
+		"field D is invalid: 4 is slow",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+	for i, err := range errs {
+		if err.Error() != want[i] {
+			t.Errorf("error %d is %q, wanted %q", i, err, want[i])
+		}
+	}
+	if fmt.Sprint(audited) != "[A B B Y.C D]" {
+		t.Errorf("wrong audit order: %v", audited)
+	}
+
+	vd["slow"] = func(interface{}) error { return errors.New("slow") }
+	errs = vd.ValidateOpts(X{}, Parallel(2), FailFast())
+	if len(errs) != 1 || errs[0].Error() != "field A is invalid: slow" {
+		t.Fatalf("wrong errors failing fast: %v", errs)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	errs = vd.ValidateOpts(X{}, Parallel(2), Context(ctx))
+	if len(errs) != 1 || !errors.Is(errs[0], ErrValidationCanceled) {
+		t.Fatalf("wrong errors when canceled: %v", errs)
+	}
+}
+
+func TestParallel_noRecover(t *testing.T) {
+	type X struct {
+		A int `validate:"bad"`
+		B int `validate:"bad"`
+	}
+
+	defer func() {
+		if p := recover(); p != "oops" {
+			t.Fatalf("wrong panic: %v", p)
+		}
+	}()
+	vd := V{"bad": func(interface{}) error { panic("oops") }}
+	vd.ValidateOpts(X{}, Parallel(2), NoRecover())
+	t.Fatal("validator's panic was not propagated")
+}
@@ -0,0 +1,97 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordPolicy describes the passwords accepted by the "password"
+// validator added by RegisterPasswordPolicy. Lengths are counted in
+// characters, not bytes, and the classes of characters are those of
+// Unicode, so "Ä" is an uppercase letter and "٣" a digit.
+type PasswordPolicy struct {
+	// MinLength and MaxLength bound the length of a password.
+	// A MaxLength of 0 allows passwords of any length.
+	MinLength, MaxLength int
+
+	// Upper, Lower, Digit, and Symbol require a password to contain an
+	// uppercase letter, a lowercase letter, a decimal digit, or a symbol,
+	// which is any character that is not a letter or a digit, such as
+	// "!" or a space.
+	Upper, Lower, Digit, Symbol bool
+
+	// Banned, if it is not nil, reports whether a password is forbidden,
+	// such as one that is common or has appeared in a breach.
+	Banned func(password string) bool
+}
+
+// RegisterPasswordPolicy adds a validator named "password" to v, replacing
+// any already present, that reports strings not permitted by p:
+//
+//	vd.RegisterPasswordPolicy(validate.PasswordPolicy{
+//		MinLength: 12,
+//		Digit:     true,
+//		Banned:    commonPasswords.Contains,
+//	})
+//
+// Passwords that are too short or too long are reported with errors
+// wrapping ErrTooShort or ErrTooLong, those lacking a required class of
+// character or holding invalid UTF-8 or control characters with errors
+// wrapping ErrBadFormat, banned ones with errors wrapping ErrNotAllowed,
+// and values whose kind is not string with errors wrapping ErrWrongType.
+// The errors do not include the password.
+func (v V) RegisterPasswordPolicy(p PasswordPolicy) {
+	v["password"] = func(i interface{}) error {
+		s, ok := asString(i)
+		if !ok {
+			return errorf(ErrWrongType, "%T is not a password", i)
+		}
+		return p.check(s)
+	}
+}
+
+func (p PasswordPolicy) check(s string) error {
+	if !utf8.ValidString(s) {
+		return errorf(ErrBadFormat, "password is not valid UTF-8")
+	}
+	n := utf8.RuneCountInString(s)
+	if n < p.MinLength {
+		return errorf(ErrTooShort, "password is shorter than %d characters", p.MinLength)
+	}
+	if p.MaxLength > 0 && n > p.MaxLength {
+		return errorf(ErrTooLong, "password is longer than %d characters", p.MaxLength)
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range s {
+		switch {
+		case unicode.IsControl(r):
+			return errorf(ErrBadFormat, "password has a control character")
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case !unicode.IsLetter(r) && !unicode.IsNumber(r):
+			symbol = true
+		}
+	}
+	switch {
+	case p.Upper && !upper:
+		return errorf(ErrBadFormat, "password has no uppercase letter")
+	case p.Lower && !lower:
+		return errorf(ErrBadFormat, "password has no lowercase letter")
+	case p.Digit && !digit:
+		return errorf(ErrBadFormat, "password has no digit")
+	case p.Symbol && !symbol:
+		return errorf(ErrBadFormat, "password has no symbol")
+	}
+
+	if p.Banned != nil && p.Banned(s) {
+		return errorf(ErrNotAllowed, "password is not allowed")
+	}
+	return nil
+}
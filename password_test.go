@@ -0,0 +1,48 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestV_RegisterPasswordPolicy(t *testing.T) {
+	vd := V{}
+	vd.RegisterPasswordPolicy(PasswordPolicy{
+		MinLength: 8,
+		MaxLength: 16,
+		Upper:     true,
+		Lower:     true,
+		Digit:     true,
+		Symbol:    true,
+		Banned: func(p string) bool {
+			return strings.EqualFold(p, "Passw0rd!")
+		},
+	})
+	testFormat(t, "password", vd["password"], map[interface{}]error{
+		"C0rrect horse":     nil,
+		"Äpfel-und-B1rnen":  nil,
+		"Ünïcödé٣!":         nil,
+		"Sh0rt!":            ErrTooShort,
+		"Äpfel!1":           ErrTooShort,
+		"Much-t00-long-for": ErrTooLong,
+		"c0rrect horse":     ErrBadFormat,
+		"C0RRECT HORSE":     ErrBadFormat,
+		"Correct horse":     ErrBadFormat,
+		"C0rrecthorse":      ErrBadFormat,
+		"C0rrect\thorse":    ErrBadFormat,
+		"C0rrect \xffhorse": ErrBadFormat,
+		"Passw0rd!":         ErrNotAllowed,
+		12345678:            ErrWrongType,
+	})
+
+	err := vd["password"]("hunter2")
+	if err == nil || strings.Contains(err.Error(), "hunter2") {
+		t.Errorf("wrong error %v", err)
+	}
+
+	vd.RegisterPasswordPolicy(PasswordPolicy{})
+	testFormat(t, "password", vd["password"], map[interface{}]error{
+		"":                        nil,
+		strings.Repeat("a", 1000): nil,
+	})
+}
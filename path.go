@@ -0,0 +1,31 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import "strings"
+
+// Path is the path to a field within the value passed to Validate:
+// the names of the fields leading to it, and the indexes or keys of the
+// elements of slices, arrays, and maps, in brackets, such as "[3]".
+type Path []string
+
+// String returns p as it appears in a BadField by default,
+// as for DotPath, such as "Items[2].Price".
+func (p Path) String() string {
+	return DotPath(p)
+}
+
+// JSONPointer returns p as an RFC 6901 JSON Pointer, such as
+// "/items/2/price", for locating fields in JSON documents when they are
+// named by their json tags. The empty path is the pointer "".
+func (p Path) JSONPointer() string {
+	var b strings.Builder
+	for _, s := range p {
+		if isIndex(s) {
+			s = s[1 : len(s)-1]
+		}
+		b.WriteByte('/')
+		pointerEscaper.WriteString(&b, s)
+	}
+	return b.String()
+}
@@ -0,0 +1,35 @@
+package validate
+
+import "testing"
+
+func TestPath(t *testing.T) {
+	tests := []struct {
+		path    Path
+		str     string
+		pointer string
+	}{
+		{nil, "", ""},
+		{Path{"items", "[2]", "price"}, "items[2].price", "/items/2/price"},
+		{Path{"tags", "[a/b~c]"}, "tags[a/b~c]", "/tags/a~1b~0c"},
+		{Path{"[0]"}, "[0]", "/0"},
+	}
+	for _, test := range tests {
+		if s := test.path.String(); s != test.str {
+			t.Fatalf("wrong string for %#v: %q, wanted %q", test.path, s, test.str)
+		}
+		if p := test.path.JSONPointer(); p != test.pointer {
+			t.Fatalf("wrong JSON pointer for %#v: %q, wanted %q", test.path, p, test.pointer)
+		}
+	}
+
+	type Item struct {
+		Price int `json:"price" validate:"nonzero"`
+	}
+	type Order struct {
+		Items []Item `json:"items" validate:"each,struct"`
+	}
+	errs := Builtin().ValidateAndTag(Order{[]Item{{1}, {1}, {0}}}, "json")
+	if len(errs) != 1 || errs[0].(BadField).Path.JSONPointer() != "/items/2/price" {
+		t.Fatalf("wrong path of invalid field: %v", errs)
+	}
+}
@@ -0,0 +1,59 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+)
+
+// RegisterPayload adds an extended validator to v under name for
+// json.RawMessage fields holding a payload whose type is chosen by a
+// sibling field, as in envelope designs:
+//
+//	type Envelope struct {
+//		Type    string
+//		Payload json.RawMessage `validate:"payload=Type"`
+//	}
+//
+//	vd.RegisterPayload("payload", map[string]interface{}{
+//		"order":  Order{},
+//		"refund": Refund{},
+//	})
+//
+// The rule's parameter names the sibling field, which must be a string.
+// Its value selects one of the samples in types, and the payload must
+// decode as JSON into a value of the sample's type and pass that type's
+// rules in v. A rule without a parameter uses the sample for "".
+// An empty or null payload is not checked.
+func (v V) RegisterPayload(name string, types map[string]interface{}) {
+	v.RegisterField(name, func(f Field) error {
+		raw, ok := f.Value.Interface().(json.RawMessage)
+		if !ok {
+			return errorf(ErrWrongType, "%v is not a json.RawMessage", f.Value.Type())
+		}
+		if len(raw) == 0 || string(raw) == "null" {
+			return nil
+		}
+
+		key := ""
+		if f.Rule.Param != "" {
+			kv := f.Parent.FieldByName(f.Rule.Param)
+			if !kv.IsValid() || kv.Kind() != reflect.String {
+				return errorf(ErrWrongType, "%v has no string field %s", f.Parent.Type(), f.Rule.Param)
+			}
+			key = kv.String()
+		}
+		sample, ok := types[key]
+		if !ok {
+			return errorf(ErrNotAllowed, "unknown payload type %q", key)
+		}
+
+		p := reflect.New(reflect.TypeOf(sample))
+		if err := json.Unmarshal(raw, p.Interface()); err != nil {
+			return errorf(ErrBadFormat, "payload is not a valid %v: %v", p.Elem().Type(), err)
+		}
+		return errors.Join(v.ValidateContext(f.Context, p.Interface())...)
+	})
+}
@@ -0,0 +1,68 @@
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type testOrder struct {
+	Item string `validate:"nonzero"`
+}
+
+type testRefund struct {
+	Amount int `validate:"nonzero"`
+}
+
+func TestV_RegisterPayload(t *testing.T) {
+	type Envelope struct {
+		Type    string
+		Payload json.RawMessage `validate:"payload=Type"`
+	}
+
+	vd := make(V)
+	vd["nonzero"] = func(i interface{}) error {
+		if reflect.ValueOf(i).IsZero() {
+			return fmt.Errorf("should be nonzero")
+		}
+		return nil
+	}
+	vd.RegisterPayload("payload", map[string]interface{}{
+		"order":  testOrder{},
+		"refund": testRefund{},
+	})
+
+	tests := []struct {
+		env  Envelope
+		err  error
+		want string
+	}{
+		{Envelope{"order", json.RawMessage(`{"Item": "hat"}`)}, nil, ""},
+		{Envelope{"refund", nil}, nil, ""},
+		{Envelope{"refund", json.RawMessage(`{"Amount": 0}`)}, nil, "field Amount is invalid: should be nonzero"},
+		{Envelope{"gift", json.RawMessage(`{}`)}, ErrNotAllowed, ""},
+		{Envelope{"order", json.RawMessage(`{"Item": 5}`)}, ErrBadFormat, ""},
+	}
+
+	for _, test := range tests {
+		errs := vd.Validate(test.env)
+		if test.err == nil && test.want == "" {
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors for %s: %v", test.env.Payload, errs)
+			}
+			continue
+		}
+		if len(errs) != 1 {
+			t.Fatalf("wrong number of errors for %s: %v", test.env.Payload, errs)
+		}
+		err := errs[0].(BadField).Err
+		if test.err != nil && !errors.Is(err, test.err) {
+			t.Fatalf("wrong error for %s: %v", test.env.Payload, err)
+		}
+		if test.want != "" && err.Error() != test.want {
+			t.Fatalf("wrong error for %s: %v", test.env.Payload, err)
+		}
+	}
+}
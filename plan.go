@@ -0,0 +1,38 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// PlannedCheck describes a validator that Validate would call.
+type PlannedCheck struct {
+	Field   string // the field name, as it would appear in a BadField
+	Rule    Rule   // the rule naming the validator
+	Defined bool   // whether the V has a validator for the rule
+}
+
+// Plan reports, in order, the validators that Validate would call for
+// sample, without calling any of them. Fields reached through a "struct"
+// tag are planned according to the values in sample, so a nil pointer
+// contributes no checks, just as it would contribute no errors.
+//
+// Plan returns an error if sample is not a struct or a pointer to one,
+// or if one of its tags cannot be parsed.
+func (v V) Plan(sample interface{}) ([]PlannedCheck, error) {
+	val := reflect.ValueOf(sample)
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot plan validation of %T", sample)
+	}
+
+	w := walker{v: v, planning: true}
+	if errs := w.validate(val, nil); len(errs) > 0 {
+		return w.plan, errs[0]
+	}
+	return w.plan, nil
+}
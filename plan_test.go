@@ -0,0 +1,60 @@
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestV_Plan(t *testing.T) {
+	type X struct {
+		A int `validate:"odd"`
+	}
+	type Y struct {
+		X  `validate:"struct,odd"`
+		P  *X `validate:"struct"`
+		B  string
+		C  string `validate:"long,missing"`
+		ok bool   `validate:"odd"`
+	}
+
+	called := false
+	vd := make(V)
+	vd["odd"] = func(i interface{}) error {
+		called = true
+		return fmt.Errorf("odd was called")
+	}
+	vd["long"] = vd["odd"]
+
+	plan, err := vd.Plan(&Y{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if called {
+		t.Fatal("Plan called a validator")
+	}
+
+	want := []PlannedCheck{
+		{"X.A", Rule{Name: "odd"}, true},
+		{"X", Rule{Name: "odd"}, true},
+		{"C", Rule{Name: "long"}, true},
+		{"C", Rule{Name: "missing"}, false},
+	}
+	if !reflect.DeepEqual(plan, want) {
+		t.Fatalf("wrong plan: %+v", plan)
+	}
+}
+
+func TestV_Plan_errors(t *testing.T) {
+	type X struct {
+		A int `validate:"odd,"`
+	}
+
+	vd := make(V)
+	if _, err := vd.Plan(7); err == nil {
+		t.Fatal("no error planning a non-struct")
+	}
+	if _, err := vd.Plan(X{}); err == nil {
+		t.Fatal("no error planning a malformed tag")
+	}
+}
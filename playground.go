@@ -0,0 +1,125 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// PlaygroundTags reads the rules of fields in the syntax of the tags of
+// github.com/go-playground/validator, so that structs tagged for it can be
+// validated while their tags are migrated. Its reserved rules are
+// translated: "dive" applies the rules following it to the elements of a
+// slice or array, or the values of a map, with those between "keys" and
+// "endkeys" applied to the keys, and "required" and "omitempty" are
+// understood as usual. In parameters, "0x2C" stands for a comma and
+// "0x7C" for "|". As in that package, nested structs are validated
+// without "struct" rules, as with the Deep option.
+//
+// Its "min", "max", and "len" rules, which measure lengths or values by
+// the type of the field, are translated to "minlen", "maxlen", and "len"
+// for strings, slices, arrays, and maps, and to "gte", "lte", and "eq"
+// for numbers. The other rules name validators in the V, as usual, and
+// are reported as undefined if it has none of their names.
+func PlaygroundTags() Option {
+	return func(w *walker) {
+		w.playground = true
+		w.deep = true
+	}
+}
+
+// parsePlayground parses tag, the go-playground/validator tag of a field
+// of type t, into the equivalent rules.
+func parsePlayground(tag string, t reflect.Type) ([]Rule, error) {
+	if tag == "" {
+		return nil, nil
+	}
+	var rules []Rule
+	parts := strings.Split(tag, ",")
+	for i := 0; i < len(parts); i++ {
+		part := parts[i]
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		if part == "dive" {
+			switch t.Kind() {
+			case reflect.Map:
+				if i+1 < len(parts) && parts[i+1] == "keys" {
+					end := i + 2
+					for end < len(parts) && parts[end] != "endkeys" {
+						end++
+					}
+					keys := make([]string, 0, end-i-2)
+					for _, k := range parts[i+2 : end] {
+						keys = append(keys, playgroundRule(k, t.Key()))
+					}
+					rules = append(rules, Rule{Name: "keys", Param: strings.Join(keys, ",")})
+					i = end
+				}
+				rules = append(rules, Rule{Name: "values"})
+			default:
+				rules = append(rules, Rule{Name: "each"})
+			}
+			if k := t.Kind(); k == reflect.Map || k == reflect.Slice || k == reflect.Array {
+				t = t.Elem()
+			}
+			continue
+		}
+
+		for j, alt := range strings.Split(part, "|") {
+			name, param, _ := strings.Cut(alt, "=")
+			if name == "" {
+				return nil, fmt.Errorf("empty rule at position %d in tag %q", len(rules)+1, tag)
+			}
+			name = playgroundName(name, t)
+			param = strings.NewReplacer("0x2C", ",", "0x7C", "|").Replace(param)
+			rules = append(rules, Rule{Name: name, Param: param, Or: j > 0})
+		}
+	}
+	return rules, nil
+}
+
+// playgroundNames are the names of the go-playground/validator rules that
+// measure lengths or values by the type of the field, with the names of
+// the equivalent rules for lengths and for numbers.
+var playgroundNames = map[string][2]string{
+	"min": {"minlen", "gte"},
+	"max": {"maxlen", "lte"},
+	"len": {"len", "eq"},
+}
+
+// playgroundName returns the name of the rule equivalent to the
+// go-playground/validator rule named name, for a field of type t.
+func playgroundName(name string, t reflect.Type) string {
+	names, ok := playgroundNames[name]
+	if !ok {
+		return name
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return names[0]
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return names[1]
+	}
+	return name
+}
+
+// playgroundRule returns part, a rule or alternatives of a
+// go-playground/validator tag, with the names of its rules translated
+// for a field of type t.
+func playgroundRule(part string, t reflect.Type) string {
+	alts := strings.Split(part, "|")
+	for i, alt := range alts {
+		name, param, hasParam := strings.Cut(alt, "=")
+		alts[i] = playgroundName(name, t)
+		if hasParam {
+			alts[i] += "=" + param
+		}
+	}
+	return strings.Join(alts, "|")
+}
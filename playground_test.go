@@ -0,0 +1,97 @@
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestParsePlayground(t *testing.T) {
+	tests := []struct {
+		tag  string
+		typ  interface{}
+		want []Rule
+	}{
+		{"", "", nil},
+		{"required,email", "", []Rule{{Name: "required"}, {Name: "email"}}},
+		{"omitempty,oneof=red green", "", []Rule{{Name: "omitempty"}, {Name: "oneof", Param: "red green"}}},
+		{"alpha|numeric", "", []Rule{{Name: "alpha"}, {Name: "numeric", Or: true}}},
+		{"excludesall=0x2C0x7C", "", []Rule{{Name: "excludesall", Param: ",|"}}},
+		{"required,dive,required", []string{}, []Rule{{Name: "required"}, {Name: "each"}, {Name: "required"}}},
+		{"dive,dive,alpha", &[][]string{}, []Rule{{Name: "each"}, {Name: "each"}, {Name: "alpha"}}},
+		{"dive,keys,alpha,nonempty,endkeys,required", map[string]int{},
+			[]Rule{{Name: "keys", Param: "alpha,nonempty"}, {Name: "values"}, {Name: "required"}}},
+		{"dive,numeric", map[int]string{}, []Rule{{Name: "values"}, {Name: "numeric"}}},
+		{"required,min=3,max=10", "", []Rule{{Name: "required"}, {Name: "minlen", Param: "3"}, {Name: "maxlen", Param: "10"}}},
+		{"min=1,max=5", []int{}, []Rule{{Name: "minlen", Param: "1"}, {Name: "maxlen", Param: "5"}}},
+		{"len=2|min=0", uint8(0), []Rule{{Name: "eq", Param: "2"}, {Name: "gte", Param: "0", Or: true}}},
+		{"max=1.5", new(float64), []Rule{{Name: "lte", Param: "1.5"}}},
+		{"dive,keys,len=2,endkeys,min=1", map[string]int{},
+			[]Rule{{Name: "keys", Param: "len=2"}, {Name: "values"}, {Name: "gte", Param: "1"}}},
+		{"min=1", struct{}{}, []Rule{{Name: "min", Param: "1"}}},
+	}
+	for _, test := range tests {
+		rules, err := parsePlayground(test.tag, reflect.TypeOf(test.typ))
+		if err != nil || !reflect.DeepEqual(rules, test.want) {
+			t.Fatalf("wrong rules for %q: %+v, %v, wanted %+v", test.tag, rules, err, test.want)
+		}
+	}
+
+	if _, err := parsePlayground("a,,b", reflect.TypeOf("")); err == nil {
+		t.Fatal("no error for an empty rule")
+	}
+}
+
+func TestPlaygroundTags(t *testing.T) {
+	type Address struct {
+		City string `validate:"required"`
+	}
+	type User struct {
+		Name    string            `validate:"required,alpha"`
+		Emails  []string          `validate:"required,dive,email"`
+		Labels  map[string]string `validate:"dive,keys,alpha,endkeys,required"`
+		Address Address
+		Age     int `validate:"gte=0"`
+	}
+
+	u := User{
+		Name:   "Ann",
+		Emails: []string{"x"},
+		Labels: map[string]string{"a1": "b", "c": ""},
+		Age:    -1,
+	}
+	var got []string
+	for _, err := range Builtin().ValidateOpts(u, PlaygroundTags()) {
+		got = append(got, err.(BadField).Field+" "+err.(BadField).Rule)
+	}
+	want := "[Emails[0] email Labels[a1] alpha Labels[c] required Address.City required Age gte]"
+	if fmt.Sprint(got) != want {
+		t.Fatalf("wrong errors: %v, wanted %s", got, want)
+	}
+}
+
+func TestPlaygroundTags_lengths(t *testing.T) {
+	type Signup struct {
+		Name   string         `validate:"required,min=3,max=10"`
+		PIN    string         `validate:"len=4,numeric"`
+		Age    uint8          `validate:"gte=13,lte=130"`
+		Score  float64        `validate:"min=0,max=1"`
+		Tags   []string       `validate:"min=1,max=3,dive,min=2"`
+		Counts map[string]int `validate:"dive,keys,len=2,endkeys,min=1"`
+	}
+
+	good := Signup{Name: "Ann", PIN: "1234", Age: 30, Score: 0.5, Tags: []string{"go"}, Counts: map[string]int{"ab": 1}}
+	if errs := Builtin().ValidateOpts(good, PlaygroundTags()); len(errs) != 0 {
+		t.Fatalf("errors for a valid struct: %v", errs)
+	}
+
+	bad := Signup{Name: "Al", PIN: "123", Age: 30, Score: 2, Tags: []string{"g"}, Counts: map[string]int{"abc": 0}}
+	var got []string
+	for _, err := range Builtin().ValidateOpts(bad, PlaygroundTags()) {
+		got = append(got, err.(BadField).Field+" "+err.(BadField).Rule)
+	}
+	want := "[Name minlen PIN len Score lte Tags[0] minlen Counts[abc] len Counts[abc] gte]"
+	if fmt.Sprint(got) != want {
+		t.Fatalf("wrong errors: %v, wanted %s", got, want)
+	}
+}
@@ -0,0 +1,97 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"container/list"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sync"
+)
+
+// Regexps is the cache used by this package's validators to compile
+// regular expressions. User validators may share it, too.
+var Regexps = NewRegexpCache(256)
+
+// RegexpCache compiles regular expressions, keeping at most a fixed number
+// of the most recently used results so that patterns named in tags
+// are not recompiled on every validation. It is safe for concurrent use.
+type RegexpCache struct {
+	mu      sync.Mutex
+	max     int
+	order   *list.List // of *regexpEntry, most recently used first
+	entries map[string]*list.Element
+}
+
+type regexpEntry struct {
+	pattern string
+	re      *regexp.Regexp
+	err     error
+}
+
+// NewRegexpCache returns a RegexpCache holding at most max patterns.
+func NewRegexpCache(max int) *RegexpCache {
+	return &RegexpCache{
+		max:     max,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Compile returns the result of regexp.Compile(pattern),
+// from the cache if possible.
+func (c *RegexpCache) Compile(pattern string) (*regexp.Regexp, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[pattern]; ok {
+		c.order.MoveToFront(el)
+		e := el.Value.(*regexpEntry)
+		return e.re, e.err
+	}
+
+	re, err := regexp.Compile(pattern)
+	if c.max <= 0 {
+		return re, err
+	}
+	c.entries[pattern] = c.order.PushFront(&regexpEntry{pattern, re, err})
+	if c.order.Len() > c.max {
+		el := c.order.Back()
+		c.order.Remove(el)
+		delete(c.entries, el.Value.(*regexpEntry).pattern)
+	}
+	return re, err
+}
+
+// Len returns the number of patterns in the cache.
+func (c *RegexpCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// matchRegexp is the "regexp" builtin, which reports strings not matching
+// the pattern given as its parameter. The pattern is compiled with Regexps.
+func matchRegexp(i interface{}, pattern string) error {
+	re, err := Regexps.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("parameter %q is not a regular expression: %v", pattern, err)
+	}
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a string", i)
+	}
+	if !re.MatchString(s) {
+		return errorf(ErrBadFormat, "%q does not match %q", s, pattern)
+	}
+	return nil
+}
+
+// regexpParam is the parameter check of "regexp".
+func regexpParam(t reflect.Type, pattern string) error {
+	if _, err := Regexps.Compile(pattern); err != nil {
+		return fmt.Errorf("parameter %q is not a regular expression: %v", pattern, err)
+	}
+	return stringType(t)
+}
@@ -0,0 +1,60 @@
+package validate
+
+import "testing"
+
+func TestRegexpCache(t *testing.T) {
+	c := NewRegexpCache(2)
+
+	a, err := c.Compile("^a+$")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := c.Compile("^a+$"); b != a {
+		t.Fatal("pattern was recompiled")
+	}
+
+	c.Compile("^b+$")
+	c.Compile("^a+$") // a is now the most recent
+	c.Compile("^c+$") // evicts b
+	if c.Len() != 2 {
+		t.Fatalf("wrong cache size: %d", c.Len())
+	}
+	if b, _ := c.Compile("^a+$"); b != a {
+		t.Fatal("recently used pattern was evicted")
+	}
+
+	if _, err := c.Compile("("); err == nil {
+		t.Fatal("no error for a bad pattern")
+	}
+	if _, err := c.Compile("("); err == nil {
+		t.Fatal("no error for a cached bad pattern")
+	}
+}
+
+func TestV_Validate_regexp(t *testing.T) {
+	type X struct {
+		Slug  string `validate:"regexp=^[a-z0-9-]+$"`
+		Code  string `validate:"nonzero,regexp:^[A-Z]{2,3}(-[0-9]+|)$"`
+		Bad   string `validate:"regexp=("`
+		Words string `validate:"regexp=\\w+"`
+	}
+
+	errs := Builtin().Validate(X{Slug: "Not a slug", Code: "AB-1x", Words: "-- ok --"})
+	want := []string{
+		`field Slug is invalid: "Not a slug" does not match "^[a-z0-9-]+$"`,
+		`field Code is invalid: "AB-1x" does not match "^[A-Z]{2,3}(-[0-9]+|)$"`,
+		"field Bad is invalid: parameter \"(\" is not a regular expression: error parsing regexp: missing closing ): `(`",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+	for i, err := range errs {
+		if err.Error() != want[i] {
+			t.Errorf("error %d is %q, wanted %q", i, err, want[i])
+		}
+	}
+
+	if errs := Builtin().Validate(X{Slug: "a-slug-1", Code: "ABC", Words: "w"}); len(errs) != 1 {
+		t.Fatalf("wrong errors for valid values: %v", errs)
+	}
+}
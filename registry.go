@@ -0,0 +1,82 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import "sync"
+
+// A Registry holds validators that may be added and removed while other
+// goroutines validate with them, which would be a data race for a V.
+// Changes are made to a copy of the validators, which replaces them once
+// complete, so validation never waits for them and never sees them half
+// done. A Registry must not be copied after first use.
+type Registry struct {
+	mu sync.RWMutex
+	v  V
+}
+
+// NewRegistry returns a Registry holding a copy of the validators in v,
+// including those of the Vs it overlays.
+func NewRegistry(v V) *Registry {
+	return &Registry{v: v.flatten()}
+}
+
+// V returns the validators in r. The V must not be modified, but may be
+// used freely, for example with ValidateGroup; later changes to r are
+// not reflected in it.
+func (r *Registry) V() V {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.v
+}
+
+// Clone returns a copy of the validators in r, which may be modified.
+func (r *Registry) Clone() V {
+	return r.V().clone()
+}
+
+// Update calls fn with a copy of the validators in r, which it may modify
+// with any of V's methods, and then puts the copy in their place:
+//
+//	reg.Update(func(v validate.V) {
+//		v.RegisterParam("min", min)
+//		v.Alias("port", "min=1")
+//	})
+//
+// Updates are made one at a time.
+func (r *Registry) Update(fn func(V)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	v := r.v.clone()
+	fn(v)
+	r.v = v
+}
+
+// Register adds fn to r as the validator named name,
+// replacing any already present.
+func (r *Registry) Register(name string, fn func(interface{}) error) {
+	notReserved(name)
+	r.Update(func(v V) {
+		v[name] = fn
+		v.clearAccepts(name)
+	})
+}
+
+// Unregister removes the validator named name from r, including
+// an extended validator or an alias of that name.
+func (r *Registry) Unregister(name string) {
+	r.Update(func(v V) {
+		delete(v, name)
+		delete(v, name+"=")
+		v.forget(name)
+	})
+}
+
+// Validate behaves like V.Validate, with the validators in r.
+func (r *Registry) Validate(s interface{}) []error {
+	return r.V().Validate(s)
+}
+
+// ValidateOpts behaves like V.ValidateOpts, with the validators in r.
+func (r *Registry) ValidateOpts(s interface{}, opts ...Option) []error {
+	return r.V().ValidateOpts(s, opts...)
+}
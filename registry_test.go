@@ -0,0 +1,63 @@
+package validate
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	type X struct {
+		A int `validate:"odd"`
+	}
+	odd := func(i interface{}) error {
+		if i.(int)%2 == 0 {
+			return errors.New("should be odd")
+		}
+		return nil
+	}
+
+	base := V{"nonzero": nonzero}
+	reg := NewRegistry(base)
+	reg.Register("odd", odd)
+	if base["odd"] != nil {
+		t.Fatal("registering changed the V the registry was made from")
+	}
+	if errs := reg.Validate(X{2}); len(errs) != 1 {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+
+	v := reg.V()
+	c := reg.Clone()
+	c["extra"] = nonzero
+	reg.Update(func(v V) {
+		v.Alias("odd", "nonzero")
+	})
+	reg.Unregister("odd")
+	if v["odd"] == nil || reg.V()["extra"] != nil {
+		t.Fatal("changes were shared between copies of the validators")
+	}
+	errs := reg.ValidateOpts(X{2})
+	if len(errs) != 1 || !errors.Is(errs[0], ErrUndefinedValidator) {
+		t.Fatalf("wrong errors after unregistering: %v", errs)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				reg.Validate(X{j})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				reg.Register("odd", odd)
+				reg.Unregister("odd")
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,123 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Warn marks err as a warning: a failure worth reporting that should not
+// make the value invalid. Validators, including fallbacks, can return
+// warnings for deprecated settings or rules they do not enforce.
+// Validate reports warnings like any other error, but Report separates
+// them from errors. Warn returns nil if err is nil.
+func Warn(err error) error {
+	if err == nil {
+		return nil
+	}
+	return warning{err}
+}
+
+// IsWarning reports whether err, or an error it wraps, was marked by Warn.
+func IsWarning(err error) bool {
+	var w warning
+	return errors.As(err, &w)
+}
+
+type warning struct {
+	err error
+}
+
+func (w warning) Error() string {
+	return w.err.Error()
+}
+
+func (w warning) Unwrap() error {
+	return w.err
+}
+
+// Report is the outcome of a validation, suitable for returning whole from
+// endpoints that check configuration. It encodes to JSON as an object:
+//
+//	{
+//		"type": "main.Config",
+//		"ok": false,
+//		"errors": [{"field": "Name", "rule": "nonzero", "message": "should be nonzero"}],
+//		"warnings": [],
+//		"rules": {"nonzero": 1},
+//		"duration": "18µs"
+//	}
+type Report struct {
+	Type     string         // the type of the value validated
+	Errors   []BadField     // the failures that make the value invalid
+	Warnings []BadField     // the failures marked by Warn
+	Rules    map[string]int // the number of failures of each rule
+	Duration time.Duration  // how long validation took
+}
+
+// Report validates s as configured by opts, like ValidateOpts,
+// and returns a Report of the outcome.
+func (v V) Report(s interface{}, opts ...Option) *Report {
+	start := time.Now()
+	errs := v.ValidateOpts(s, opts...)
+	r := &Report{
+		Type:     fmt.Sprintf("%T", s),
+		Errors:   []BadField{},
+		Warnings: []BadField{},
+		Rules:    make(map[string]int),
+		Duration: time.Since(start),
+	}
+	for _, err := range errs {
+		bf, ok := err.(BadField)
+		if !ok {
+			bf = BadField{Err: err}
+		}
+		if bf.Rule != "" {
+			r.Rules[bf.Rule]++
+		}
+		if IsWarning(bf.Err) {
+			r.Warnings = append(r.Warnings, bf)
+		} else {
+			r.Errors = append(r.Errors, bf)
+		}
+	}
+	return r
+}
+
+// OK reports whether the value validated has no errors.
+// It may have warnings.
+func (r *Report) OK() bool {
+	return len(r.Errors) == 0
+}
+
+// reportEntry is the JSON encoding of a failure in a Report.
+type reportEntry struct {
+	Field   string   `json:"field,omitempty"`
+	Rule    string   `json:"rule,omitempty"`
+	Params  []string `json:"params,omitempty"`
+	Message string   `json:"message"`
+}
+
+func reportEntries(bfs []BadField) []reportEntry {
+	es := make([]reportEntry, len(bfs))
+	for i, bf := range bfs {
+		es[i] = reportEntry{bf.Field, bf.Rule, bf.Params, bf.Err.Error()}
+	}
+	return es
+}
+
+// MarshalJSON encodes r as shown for Report.
+// The values of invalid fields are left out.
+func (r *Report) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type     string         `json:"type"`
+		OK       bool           `json:"ok"`
+		Errors   []reportEntry  `json:"errors"`
+		Warnings []reportEntry  `json:"warnings"`
+		Rules    map[string]int `json:"rules"`
+		Duration string         `json:"duration"`
+	}{r.Type, r.OK(), reportEntries(r.Errors), reportEntries(r.Warnings), r.Rules, r.Duration.String()})
+}
@@ -0,0 +1,73 @@
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestV_Report(t *testing.T) {
+	type X struct {
+		A int `validate:"odd"`
+		B int `validate:"odd"`
+		C int `validate:"legacy"`
+	}
+
+	vd := make(V)
+	vd["odd"] = func(i interface{}) error {
+		if i.(int)%2 == 0 {
+			return fmt.Errorf("%d should be odd", i)
+		}
+		return nil
+	}
+	deprecated := errors.New("deprecated")
+	vd.RegisterFallback(func(f Field) error {
+		return Warn(fmt.Errorf("rule %s is %w", f.Rule.Name, deprecated))
+	})
+
+	if r := vd.Report(X{1, 3, 3}); !r.OK() || len(r.Warnings) != 1 {
+		t.Fatalf("report with only warnings is not OK: %+v", r)
+	}
+
+	r := vd.Report(X{1, 2, 3})
+	if r.OK() || len(r.Errors) != 1 || len(r.Warnings) != 1 {
+		t.Fatalf("wrong report: %+v", r)
+	}
+	if !errors.Is(r.Warnings[0].Err, deprecated) {
+		t.Fatalf("warning does not wrap the validator's error: %v", r.Warnings[0].Err)
+	}
+	if r.Rules["odd"] != 1 || r.Rules["legacy"] != 1 {
+		t.Fatalf("wrong rule counts: %v", r.Rules)
+	}
+
+	r = vd.Report(&X{2, 2, 3})
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["type"] != "*validate.X" || got["ok"] != false || len(got["errors"].([]interface{})) != 2 {
+		t.Fatalf("wrong JSON: %s", b)
+	}
+	if e := got["errors"].([]interface{})[0].(map[string]interface{}); e["field"] != "A" || e["message"] != "2 should be odd" {
+		t.Fatalf("wrong JSON error: %s", b)
+	}
+}
+
+func TestWarn(t *testing.T) {
+	if Warn(nil) != nil {
+		t.Fatal("Warn(nil) is not nil")
+	}
+	err := fmt.Errorf("wrapped: %w", Warn(ErrBadFormat))
+	if !IsWarning(err) || !errors.Is(err, ErrBadFormat) {
+		t.Fatalf("wrong warning: %v", err)
+	}
+	if IsWarning(ErrBadFormat) {
+		t.Fatal("plain error is a warning")
+	}
+}
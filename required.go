@@ -0,0 +1,104 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// errRequired is reported for fields that fail the "required" or
+// "present" rule.
+var errRequired = errorf(ErrRequired, "is required")
+
+// requiredRule reports whether name is "required", one of its
+// conditional forms, or "present".
+func requiredRule(name string) bool {
+	switch name {
+	case "required", "required_if", "required_with", "required_without", "present":
+		return true
+	}
+	return false
+}
+
+// required returns the error to report if the field checked by r, which
+// must satisfy requiredRule, holds a zero value, or nil if r does not
+// require the field. parent is the struct containing the field.
+func required(parent reflect.Value, r Rule) error {
+	if r.Name == "required" || r.Name == "present" {
+		return errRequired
+	}
+	ps := r.Params()
+	if len(ps) == 0 || r.Name == "required_if" && len(ps) < 2 {
+		return fmt.Errorf("%s needs the name of a field", r.Name)
+	}
+
+	switch r.Name {
+	case "required_if":
+		s, _, err := sibling(parent, ps[0])
+		if err != nil {
+			return err
+		}
+		for _, want := range ps[1:] {
+			if s == want {
+				return errorf(ErrRequired, "is required when %s is %s", ps[0], want)
+			}
+		}
+	case "required_with":
+		for _, name := range ps {
+			_, set, err := sibling(parent, name)
+			if err != nil {
+				return err
+			}
+			if set {
+				return errorf(ErrRequired, "is required when %s is set", name)
+			}
+		}
+	case "required_without":
+		for _, name := range ps {
+			_, set, err := sibling(parent, name)
+			if err != nil {
+				return err
+			}
+			if !set {
+				return errorf(ErrRequired, "is required when %s is not set", name)
+			}
+		}
+	}
+	return nil
+}
+
+// absent reports whether v, the value of a field, is absent, as for the
+// "present" rule: a nil pointer, interface, slice, or map. Values of
+// other kinds are never absent.
+func absent(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		return v.IsNil()
+	}
+	return false
+}
+
+// sibling returns the value of the named field of parent, formatted as
+// by fmt.Sprint, and whether the field is set: not zero, or an Optional
+// holding a value. Pointers are followed to the values they point to.
+func sibling(parent reflect.Value, name string) (string, bool, error) {
+	f := parent.FieldByName(name)
+	if !f.IsValid() {
+		return "", false, fmt.Errorf("%v has no field %s", parent.Type(), name)
+	}
+	if f = unwrap(f); !f.IsValid() {
+		return "", false, nil
+	}
+	set := !f.IsZero()
+	for f.Kind() == reflect.Ptr {
+		if f.IsNil() {
+			return "", false, nil
+		}
+		f = f.Elem()
+	}
+	if !f.CanInterface() {
+		return "", false, fmt.Errorf("%v.%s is not exported", parent.Type(), name)
+	}
+	return fmt.Sprint(f.Interface()), set, nil
+}
@@ -0,0 +1,80 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestV_Validate_requiredConditional(t *testing.T) {
+	type Payment struct {
+		Type   string
+		Card   string            `validate:"required_if=Type card debit"`
+		Email  string            `validate:"required_without=Phone"`
+		Phone  *string           `validate:"required_without=Email"`
+		Expiry testOptional[int] `validate:"required_with=Card"`
+		Bad    string            `validate:"required_if=Missing x"`
+	}
+
+	phone := "555"
+	tests := []struct {
+		p    Payment
+		want string
+	}{
+		{Payment{Type: "cash", Phone: &phone}, "[Bad]"},
+		{Payment{Type: "card", Email: "a@b"}, "[Card Bad]"},
+		{Payment{Type: "debit", Card: "4111", Email: "a@b"}, "[Expiry Bad]"},
+		{Payment{Type: "cash", Card: "4111", Expiry: testOptional[int]{1, true}}, "[Email Phone Bad]"},
+	}
+	for _, test := range tests {
+		var fields []string
+		for _, err := range make(V).Validate(test.p) {
+			bf := err.(BadField)
+			if bf.Field != "Bad" && !errors.Is(bf.Err, ErrRequired) {
+				t.Fatalf("wrong error for %s: %v", bf.Field, bf.Err)
+			}
+			fields = append(fields, bf.Field)
+		}
+		if fmt.Sprint(fields) != test.want {
+			t.Fatalf("wrong invalid fields for %+v: %v, wanted %s", test.p, fields, test.want)
+		}
+	}
+
+	errs := make(V).Validate(Payment{Type: "card", Phone: &phone})
+	if errs[0].(BadField).Err.Error() != "is required when Type is card" {
+		t.Fatalf("wrong message: %v", errs[0])
+	}
+}
+
+func TestV_Validate_present(t *testing.T) {
+	type X struct {
+		Count *int              `validate:"present"`
+		Tags  []string          `validate:"present"`
+		Any   interface{}       `validate:"present"`
+		Level testOptional[int] `validate:"present"`
+		Zero  int               `validate:"present"`
+	}
+
+	var fields []string
+	for _, err := range make(V).Validate(X{}) {
+		bf := err.(BadField)
+		if !errors.Is(bf.Err, ErrRequired) || bf.Rule != "present" {
+			t.Fatalf("wrong error for %s: %v", bf.Field, bf.Err)
+		}
+		fields = append(fields, bf.Field)
+	}
+	if fmt.Sprint(fields) != "[Count Tags Any Level]" {
+		t.Fatalf("wrong absent fields: %v", fields)
+	}
+
+	zero := 0
+	if errs := make(V).Validate(X{Count: &zero, Tags: []string{}, Any: 0, Level: testOptional[int]{0, true}}); len(errs) != 0 {
+		t.Fatalf("errors for present zero values: %v", errs)
+	}
+
+	errs := make(V).Check(X{})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), `rule "present" applied to int, which is never absent`) {
+		t.Fatalf("wrong errors checking present: %v", errs)
+	}
+}
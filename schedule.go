@@ -0,0 +1,329 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// These validators check strings in the formats commonly used to schedule
+// and pace work in configuration files, so that a typo fails validation
+// rather than surfacing when the schedule is first used. Each reports a
+// value whose kind is not string with an error wrapping ErrWrongType, a string
+// that cannot be parsed with one wrapping ErrBadFormat, and a parsed value
+// whose parts are out of range with one wrapping ErrOutOfRange.
+
+// cronFields describes the fields of a cron expression, in order.
+var cronFields = []struct {
+	name     string
+	min, max int
+	names    []string
+}{
+	{"minute", 0, 59, nil},
+	{"hour", 0, 23, nil},
+	{"day of month", 1, 31, nil},
+	{"month", 1, 12, []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{"day of week", 0, 7, []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// Cron validates a cron expression of five fields: minute, hour, day of
+// month, month, and day of week. Each field is "*" or a comma-separated
+// list of numbers and ranges such as "1-5", any of which may be followed by
+// a step such as "/15". Months and days of the week may also be given by
+// their three-letter English names. The macros "@yearly", "@annually",
+// "@monthly", "@weekly", "@daily", "@midnight", and "@hourly" are accepted,
+// as is "@every" followed by a positive duration in the syntax of
+// time.ParseDuration.
+func Cron(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a cron expression", i)
+	}
+	switch s {
+	case "@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly":
+		return nil
+	}
+	if d, ok := strings.CutPrefix(s, "@every "); ok {
+		every, err := time.ParseDuration(strings.TrimSpace(d))
+		if err != nil {
+			return errorf(ErrBadFormat, "%q is not a valid cron expression: %v", s, err)
+		}
+		if every <= 0 {
+			return errorf(ErrOutOfRange, "%q is not a valid cron expression: interval must be positive", s)
+		}
+		return nil
+	}
+
+	fields := strings.Fields(s)
+	if len(fields) != len(cronFields) {
+		return errorf(ErrBadFormat, "%q is not a valid cron expression: want %d fields, have %d", s, len(cronFields), len(fields))
+	}
+	for i, f := range fields {
+		if err := cronField(f, i); err != nil {
+			return errorf(errors.Unwrap(err), "%q is not a valid cron expression: %v", s, err)
+		}
+	}
+	return nil
+}
+
+// cronField checks a field of a cron expression.
+func cronField(f string, i int) error {
+	spec := cronFields[i]
+	for _, item := range strings.Split(f, ",") {
+		rng, step, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			n, err := strconv.Atoi(step)
+			if err != nil {
+				return errorf(ErrBadFormat, "bad step %q in %s field", step, spec.name)
+			}
+			if n < 1 || n > spec.max {
+				return errorf(ErrOutOfRange, "step %d out of range in %s field", n, spec.name)
+			}
+		}
+		if rng == "*" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(rng, "-")
+		a, err := cronValue(lo, i)
+		if err != nil {
+			return err
+		}
+		if !isRange {
+			continue
+		}
+		b, err := cronValue(hi, i)
+		if err != nil {
+			return err
+		}
+		if b < a {
+			return errorf(ErrOutOfRange, "range %q is backwards in %s field", rng, spec.name)
+		}
+	}
+	return nil
+}
+
+// cronValue parses a single value in the ith field of a cron expression.
+func cronValue(s string, i int) (int, error) {
+	spec := cronFields[i]
+	for j, name := range spec.names {
+		if strings.EqualFold(s, name) {
+			return spec.min + j, nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errorf(ErrBadFormat, "bad value %q in %s field", s, spec.name)
+	}
+	if n < spec.min || n > spec.max {
+		return 0, errorf(ErrOutOfRange, "value %d out of range in %s field", n, spec.name)
+	}
+	return n, nil
+}
+
+// Interval validates a time interval in the ISO 8601 syntax used with
+// RFC 3339 timestamps: a start and an end, a start and a duration, or a
+// duration and an end, separated by a slash, as in
+// "2024-01-01T00:00:00Z/P1M". Durations are written as in "P1DT12H".
+// The interval must not end before it starts.
+func Interval(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not an interval", i)
+	}
+	a, b, ok := strings.Cut(s, "/")
+	if !ok {
+		return errorf(ErrBadFormat, "%q is not a valid interval: want two parts separated by /", s)
+	}
+
+	var start, end time.Time
+	var err error
+	switch {
+	case strings.HasPrefix(a, "P") && strings.HasPrefix(b, "P"):
+		return errorf(ErrBadFormat, "%q is not a valid interval: it has no start or end", s)
+	case strings.HasPrefix(a, "P"):
+		if end, err = time.Parse(time.RFC3339, b); err == nil {
+			start, err = addISODuration(end, a, -1)
+		}
+	case strings.HasPrefix(b, "P"):
+		if start, err = time.Parse(time.RFC3339, a); err == nil {
+			end, err = addISODuration(start, b, 1)
+		}
+	default:
+		if start, err = time.Parse(time.RFC3339, a); err == nil {
+			end, err = time.Parse(time.RFC3339, b)
+		}
+	}
+	if err != nil {
+		return errorf(ErrBadFormat, "%q is not a valid interval: %v", s, err)
+	}
+	if end.Before(start) {
+		return errorf(ErrOutOfRange, "%q is not a valid interval: it ends before it starts", s)
+	}
+	return nil
+}
+
+// addISODuration adds the ISO 8601 duration d to t, sign times.
+func addISODuration(t time.Time, d string, sign int) (time.Time, error) {
+	rest, ok := strings.CutPrefix(d, "P")
+	if !ok || rest == "" || rest == "T" || strings.HasSuffix(rest, "T") {
+		return t, errorf(ErrBadFormat, "bad duration %q", d)
+	}
+	date, clock, _ := strings.Cut(rest, "T")
+
+	var ymd [3]int
+	var weeks int
+	for date != "" {
+		n, unit, tail, err := isoComponent(date, false)
+		if err != nil {
+			return t, errorf(ErrBadFormat, "bad duration %q", d)
+		}
+		switch unit {
+		case 'Y':
+			ymd[0] = int(n)
+		case 'M':
+			ymd[1] = int(n)
+		case 'W':
+			weeks = int(n)
+		case 'D':
+			ymd[2] = int(n)
+		default:
+			return t, errorf(ErrBadFormat, "bad duration %q", d)
+		}
+		date = tail
+	}
+
+	var dur time.Duration
+	for clock != "" {
+		n, unit, tail, err := isoComponent(clock, true)
+		if err != nil {
+			return t, errorf(ErrBadFormat, "bad duration %q", d)
+		}
+		switch unit {
+		case 'H':
+			dur += time.Duration(n * float64(time.Hour))
+		case 'M':
+			dur += time.Duration(n * float64(time.Minute))
+		case 'S':
+			dur += time.Duration(n * float64(time.Second))
+		default:
+			return t, errorf(ErrBadFormat, "bad duration %q", d)
+		}
+		clock = tail
+	}
+
+	t = t.AddDate(sign*ymd[0], sign*ymd[1], sign*(ymd[2]+7*weeks))
+	return t.Add(time.Duration(sign) * dur), nil
+}
+
+// isoComponent parses a number and its unit from the start of s.
+func isoComponent(s string, fraction bool) (n float64, unit byte, rest string, err error) {
+	i := 0
+	for i < len(s) && (s[i] >= '0' && s[i] <= '9' || fraction && s[i] == '.') {
+		i++
+	}
+	if i == 0 || i == len(s) {
+		return 0, 0, "", errorf(ErrBadFormat, "bad duration component %q", s)
+	}
+	n, err = strconv.ParseFloat(s[:i], 64)
+	return n, s[i], s[i+1:], err
+}
+
+// Rate validates a rate written as a positive count of events per period,
+// as in "100/1m" or "5/s". The period is a positive duration in the syntax
+// of time.ParseDuration, or a bare unit meaning one of that unit.
+func Rate(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a rate", i)
+	}
+	count, per, ok := strings.Cut(s, "/")
+	if !ok {
+		return errorf(ErrBadFormat, "%q is not a valid rate: want count/period", s)
+	}
+	n, err := strconv.ParseUint(count, 10, 64)
+	if err != nil {
+		return errorf(ErrBadFormat, "%q is not a valid rate: bad count %q", s, count)
+	}
+	period, err := time.ParseDuration(per)
+	if err != nil {
+		period, err = time.ParseDuration("1" + per)
+	}
+	if err != nil {
+		return errorf(ErrBadFormat, "%q is not a valid rate: bad period %q", s, per)
+	}
+	if n == 0 || period <= 0 {
+		return errorf(ErrOutOfRange, "%q is not a valid rate: count and period must be positive", s)
+	}
+	return nil
+}
+
+// Backoff validates a retry backoff policy written as space-separated
+// settings, as in "initial=100ms max=30s factor=2 jitter=0.1 retries=5".
+// The positive initial delay is required. The maximum delay, if given,
+// must be at least the initial delay; the growth factor must be at least 1;
+// the jitter must be a fraction from 0 to 1; and the number of retries
+// must not be negative.
+func Backoff(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a backoff policy", i)
+	}
+	bad := func(kind error, format string, args ...interface{}) error {
+		return errorf(kind, "%q is not a valid backoff policy: "+format, append([]interface{}{s}, args...)...)
+	}
+
+	var initial, max time.Duration
+	seen := make(map[string]bool)
+	for _, setting := range strings.Fields(s) {
+		key, val, ok := strings.Cut(setting, "=")
+		if !ok {
+			return bad(ErrBadFormat, "want key=value, have %q", setting)
+		}
+		if seen[key] {
+			return bad(ErrBadFormat, "%s is given twice", key)
+		}
+		seen[key] = true
+
+		var err error
+		outOfRange := false
+		switch key {
+		case "initial":
+			initial, err = time.ParseDuration(val)
+			outOfRange = initial <= 0
+		case "max":
+			max, err = time.ParseDuration(val)
+			outOfRange = max <= 0
+		case "factor":
+			var f float64
+			f, err = strconv.ParseFloat(val, 64)
+			outOfRange = f < 1
+		case "jitter":
+			var f float64
+			f, err = strconv.ParseFloat(val, 64)
+			outOfRange = f < 0 || f > 1
+		case "retries":
+			var n int
+			n, err = strconv.Atoi(val)
+			outOfRange = n < 0
+		default:
+			return bad(ErrBadFormat, "unknown setting %q", key)
+		}
+		if err != nil {
+			return bad(ErrBadFormat, "bad %s %q", key, val)
+		}
+		if outOfRange {
+			return bad(ErrOutOfRange, "%s %s is out of range", key, val)
+		}
+	}
+
+	if !seen["initial"] {
+		return bad(ErrRequired, "initial is missing")
+	}
+	if seen["max"] && max < initial {
+		return bad(ErrOutOfRange, "max is less than initial")
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+)
+
+func testFormat(t *testing.T, name string, fn func(interface{}) error, tests map[interface{}]error) {
+	t.Helper()
+	for v, want := range tests {
+		err := fn(v)
+		if want == nil && err != nil {
+			t.Errorf("%s(%#v): unexpected error: %v", name, v, err)
+		}
+		if want != nil && !errors.Is(err, want) {
+			t.Errorf("%s(%#v): wrong error %v, wanted %v", name, v, err, want)
+		}
+	}
+}
+
+func TestCron(t *testing.T) {
+	testFormat(t, "Cron", Cron, map[interface{}]error{
+		"* * * * *":                     nil,
+		"*/15 0-6,18 1 jan-MAR mon-fri": nil,
+		"0 0 * * 7":                     nil,
+		"@daily":                        nil,
+		"@every 90s":                    nil,
+		"@every -1s":                    ErrOutOfRange,
+		"@every soon":                   ErrBadFormat,
+		"* * * *":                       ErrBadFormat,
+		"60 * * * *":                    ErrOutOfRange,
+		"* * 0 * *":                     ErrOutOfRange,
+		"5-1 * * * *":                   ErrOutOfRange,
+		"*/0 * * * *":                   ErrOutOfRange,
+		"x * * * *":                     ErrBadFormat,
+		"* * * foo *":                   ErrBadFormat,
+		5:                               ErrWrongType,
+	})
+}
+
+func TestInterval(t *testing.T) {
+	testFormat(t, "Interval", Interval, map[interface{}]error{
+		"2024-01-01T00:00:00Z/2024-02-01T00:00:00Z": nil,
+		"2024-01-01T00:00:00Z/P1Y2M3W4DT5H6M7.5S":   nil,
+		"PT36H/2024-01-01T00:00:00Z":                nil,
+		"2024-02-01T00:00:00Z/2024-01-01T00:00:00Z": ErrOutOfRange,
+		"P1D/P2D":                   ErrBadFormat,
+		"2024-01-01T00:00:00Z":      ErrBadFormat,
+		"2024-01-01/P1D":            ErrBadFormat,
+		"2024-01-01T00:00:00Z/P":    ErrBadFormat,
+		"2024-01-01T00:00:00Z/P1DT": ErrBadFormat,
+		"2024-01-01T00:00:00Z/P1X":  ErrBadFormat,
+		nil:                         ErrWrongType,
+	})
+}
+
+func TestRate(t *testing.T) {
+	testFormat(t, "Rate", Rate, map[interface{}]error{
+		"100/1m":  nil,
+		"5/s":     nil,
+		"1/1h30m": nil,
+		"0/1m":    ErrOutOfRange,
+		"10/-1s":  ErrOutOfRange,
+		"10":      ErrBadFormat,
+		"ten/1m":  ErrBadFormat,
+		"10/fort": ErrBadFormat,
+		10:        ErrWrongType,
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	testFormat(t, "Backoff", Backoff, map[interface{}]error{
+		"initial=100ms": nil,
+		"initial=100ms max=30s factor=2 jitter=0.1 retries=5": nil,
+		"max=30s":               ErrRequired,
+		"initial=1s max=100ms":  ErrOutOfRange,
+		"initial=0s":            ErrOutOfRange,
+		"initial=1s factor=0.5": ErrOutOfRange,
+		"initial=1s jitter=2":   ErrOutOfRange,
+		"initial=1s retries=-1": ErrOutOfRange,
+		"initial=1s initial=2s": ErrBadFormat,
+		"initial=1s color=blue": ErrBadFormat,
+		"initial=soon":          ErrBadFormat,
+		"initial":               ErrBadFormat,
+		struct{}{}:              ErrWrongType,
+	})
+}
@@ -0,0 +1,101 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Snapshot renders the rules that Validate would apply to values of
+// sample's type as text, one field per line, in a canonical form intended
+// to be committed as a golden file. When a type's tags change, the
+// difference in its snapshot shows reviewers how its validation was
+// loosened or tightened:
+//
+//	validate snapshot of main.Config
+//	Name: nonzero
+//	Server: struct
+//	Server.Port: port
+//	Token: sensitive, token (undefined)
+//
+// Fields are listed in the order they are declared, by their Go names,
+// with the fields of types reached through "struct" rules listed after
+// the field naming them, including the elements of slices reached
+// through "each", whose paths are written as by Describe, like
+// "Tags[].Name". Aliases are shown as the rules they stand for. Rules
+// that name no validator in v are marked undefined. A type that contains
+// itself is rendered once, and its recurrences refer back to it.
+// Snapshot does not look beyond types, so the fields of the values held
+// by interface fields are not listed.
+func (v V) Snapshot(sample interface{}) string {
+	t := reflect.TypeOf(sample)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Sprintf("validate snapshot of %v: not a struct\n", t)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "validate snapshot of %v\n", t)
+	w := walker{v: v}
+	w.snapshot(&b, t, "", map[reflect.Type]string{t: "the top"})
+	return b.String()
+}
+
+// snapshot writes the rules of the fields of t, found at path,
+// to b. Types being rendered are in outer, by where they start.
+func (w *walker) snapshot(b *strings.Builder, t reflect.Type, path string, outer map[reflect.Type]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("validate")
+		if tag == "" || tag == "-" || !f.IsExported() {
+			continue
+		}
+		name := path + f.Name
+		rules, err := ParseTag(tag)
+		if err == nil {
+			rules, err = w.expand(rules, nil)
+		}
+		if err != nil {
+			fmt.Fprintf(b, "%s: invalid tag %q: %v\n", name, tag, err)
+			continue
+		}
+
+		descend := false
+		var parts []string
+		for _, r := range rules {
+			s := ruleString(r)
+			if vf, _ := w.lookup(r.Name); vf == nil && !Reserved(r.Name) {
+				s += " (undefined)"
+			}
+			descend = descend || r.Name == "struct"
+			if r.Or {
+				parts[len(parts)-1] += " | " + s
+			} else {
+				parts = append(parts, s)
+			}
+		}
+		fmt.Fprintf(b, "%s: %s\n", name, strings.Join(parts, ", "))
+
+		if !descend {
+			continue
+		}
+		ft := structType(f.Type)
+		if ft == nil {
+			continue
+		}
+		if elemKind(f.Type) {
+			name += "[]"
+		}
+		if at, ok := outer[ft]; ok {
+			fmt.Fprintf(b, "%s.*: rules of %v, as from %s\n", name, ft, at)
+			continue
+		}
+		outer[ft] = name
+		w.snapshot(b, ft, name+".", outer)
+		delete(outer, ft)
+	}
+}
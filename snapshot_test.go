@@ -0,0 +1,54 @@
+package validate
+
+import "testing"
+
+type testNode struct {
+	Value int       `validate:"odd"`
+	Next  *testNode `validate:"struct"`
+}
+
+func TestV_Snapshot(t *testing.T) {
+	type Server struct {
+		Port int `validate:"port,method=Check"`
+		Host string
+	}
+	type Config struct {
+		Name   string     `validate:"nonzero"`
+		Server Server     `validate:"struct"`
+		Token  string     `validate:"sensitive,token"`
+		Skip   int        `validate:"-"`
+		List   testNode   `validate:"struct"`
+		Expr   int        `validate:"expr:a, b"`
+		Color  string     `validate:"nonzero|port"`
+		Nodes  []testNode `validate:"each,struct"`
+	}
+
+	vd := make(V)
+	vd["nonzero"] = func(interface{}) error { return nil }
+	vd["port"] = func(interface{}) error { return nil }
+	vd["odd"] = func(interface{}) error { return nil }
+	vd.RegisterField("expr", func(Field) error { return nil })
+
+	want := `validate snapshot of validate.Config
+Name: nonzero
+Server: struct
+Server.Port: port, method=Check
+Token: sensitive, token (undefined)
+List: struct
+List.Value: odd
+List.Next: struct
+List.Next.*: rules of validate.testNode, as from List
+Expr: expr:a, b
+Color: nonzero | port
+Nodes: each, struct
+Nodes[].Value: odd
+Nodes[].Next: struct
+Nodes[].Next.*: rules of validate.testNode, as from Nodes[]
+`
+	if got := vd.Snapshot(&Config{}); got != want {
+		t.Fatalf("wrong snapshot:\n%s\nwanted:\n%s", got, want)
+	}
+	if vd.Snapshot(Config{}) != want {
+		t.Fatal("snapshot of a value differs from that of a pointer")
+	}
+}
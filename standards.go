@@ -0,0 +1,168 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"strings"
+	"time"
+)
+
+// These validators check the codes for countries, languages, and time
+// zones found in configurations and user profiles. Each reports a value
+// whose kind is not string with an error wrapping ErrWrongType.
+
+// countries holds the officially assigned codes of ISO 3166-1 alpha-2.
+var countries = make(map[string]bool)
+
+func init() {
+	for _, c := range strings.Fields(`
+		AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG
+		BH BI BJ BL BM BN BO BQ BR BS BT BV BW BY BZ CA CC CD CF CG CH CI
+		CK CL CM CN CO CR CU CV CW CX CY CZ DE DJ DK DM DO DZ EC EE EG EH
+		ER ES ET FI FJ FK FM FO FR GA GB GD GE GF GG GH GI GL GM GN GP GQ
+		GR GS GT GU GW GY HK HM HN HR HT HU ID IE IL IM IN IO IQ IR IS IT
+		JE JM JO JP KE KG KH KI KM KN KP KR KW KY KZ LA LB LC LI LK LR LS
+		LT LU LV LY MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR MS MT MU
+		MV MW MX MY MZ NA NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF PG
+		PH PK PL PM PN PR PS PT PW PY QA RE RO RS RU RW SA SB SC SD SE SG
+		SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF TG TH TJ TK
+		TL TM TN TO TR TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI VN VU
+		WF WS YE YT ZA ZM ZW`) {
+		countries[c] = true
+	}
+}
+
+// Country validates an ISO 3166-1 alpha-2 country code, as in "NZ",
+// reporting codes that are not assigned with an error wrapping
+// ErrNotAllowed. The codes are uppercase.
+func Country(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a country code", i)
+	}
+	if !countries[s] {
+		return errorf(ErrNotAllowed, "%q is not an ISO 3166 country code", s)
+	}
+	return nil
+}
+
+// grandfathered holds the language tags registered before RFC 4646 that
+// are accepted whole, in lowercase.
+var grandfathered = map[string]bool{
+	"en-gb-oed": true, "i-ami": true, "i-bnn": true, "i-default": true,
+	"i-enochian": true, "i-hak": true, "i-klingon": true, "i-lux": true,
+	"i-mingo": true, "i-navajo": true, "i-pwn": true, "i-tao": true,
+	"i-tay": true, "i-tsu": true, "sgn-be-fr": true, "sgn-be-nl": true,
+	"sgn-ch-de": true, "art-lojban": true, "cel-gaulish": true,
+	"no-bok": true, "no-nyn": true, "zh-guoyu": true, "zh-hakka": true,
+	"zh-min": true, "zh-min-nan": true, "zh-xiang": true,
+}
+
+// LanguageTag validates a well-formed BCP 47 language tag, as defined by
+// RFC 5646, such as "en", "en-US", or "zh-Hant-TW", reporting others with
+// an error wrapping ErrBadFormat. Tags are compared without regard to
+// case, and their subtags are not checked against the IANA registry, so
+// "xx-QQ" is accepted, but repeated variants and extensions are not.
+func LanguageTag(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a language tag", i)
+	}
+	if !languageTag(strings.ToLower(s)) {
+		return errorf(ErrBadFormat, "%q is not a BCP 47 language tag", s)
+	}
+	return nil
+}
+
+// languageTag reports whether the lowercase tag is well formed.
+func languageTag(tag string) bool {
+	if grandfathered[tag] {
+		return true
+	}
+	sub := strings.Split(tag, "-")
+	for _, s := range sub {
+		if s == "" || len(s) > 8 || strings.Trim(s, "abcdefghijklmnopqrstuvwxyz0123456789") != "" {
+			return false
+		}
+	}
+	if sub[0] == "x" {
+		return len(sub) > 1
+	}
+
+	// language, with up to three extended language subtags
+	// after a language of two or three letters
+	n := len(sub[0])
+	if n < 2 || n > 8 || !alphas(sub[0]) {
+		return false
+	}
+	sub = sub[1:]
+	for j := 0; j < 3 && n <= 3 && len(sub) > 0 && len(sub[0]) == 3 && alphas(sub[0]); j++ {
+		sub = sub[1:]
+	}
+	// script
+	if len(sub) > 0 && len(sub[0]) == 4 && alphas(sub[0]) {
+		sub = sub[1:]
+	}
+	// region
+	if len(sub) > 0 && (len(sub[0]) == 2 && alphas(sub[0]) || len(sub[0]) == 3 && digits(sub[0])) {
+		sub = sub[1:]
+	}
+	// variants
+	seen := make(map[string]bool)
+	for len(sub) > 0 && (len(sub[0]) >= 5 || len(sub[0]) == 4 && digits(sub[0][:1])) {
+		if seen[sub[0]] {
+			return false
+		}
+		seen[sub[0]] = true
+		sub = sub[1:]
+	}
+	// extensions, each a singleton followed by subtags of two to eight
+	// characters
+	for len(sub) > 0 && len(sub[0]) == 1 && sub[0] != "x" {
+		if seen[sub[0]] {
+			return false
+		}
+		seen[sub[0]] = true
+		j := 1
+		for j < len(sub) && len(sub[j]) >= 2 {
+			j++
+		}
+		if j == 1 {
+			return false
+		}
+		sub = sub[j:]
+	}
+	// private use subtags, following an "x"
+	if len(sub) > 0 && sub[0] == "x" {
+		return len(sub) > 1
+	}
+	return len(sub) == 0
+}
+
+func alphas(s string) bool {
+	return strings.Trim(s, "abcdefghijklmnopqrstuvwxyz") == ""
+}
+
+func digits(s string) bool {
+	return strings.Trim(s, "0123456789") == ""
+}
+
+// TimeZone validates the name of a time zone in the IANA Time Zone
+// Database, as in "America/New_York" or "UTC", reporting those that
+// time.LoadLocation cannot load with an error wrapping ErrNotAllowed.
+// "Local", which names no particular zone, is not accepted. The names
+// depend on the database available, which programs can embed by
+// importing time/tzdata.
+func TimeZone(i interface{}) error {
+	s, ok := asString(i)
+	if !ok {
+		return errorf(ErrWrongType, "%T is not a time zone", i)
+	}
+	if s == "" || s == "Local" {
+		return errorf(ErrNotAllowed, "%q is not a time zone", s)
+	}
+	if _, err := time.LoadLocation(s); err != nil {
+		return errorf(ErrNotAllowed, "%q is not a time zone", s)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package validate
+
+import (
+	"testing"
+	_ "time/tzdata"
+)
+
+func TestCountry(t *testing.T) {
+	if len(countries) != 249 {
+		t.Errorf("%d countries, wanted 249", len(countries))
+	}
+	testFormat(t, "Country", Country, map[interface{}]error{
+		"NZ":  nil,
+		"US":  nil,
+		"nz":  ErrNotAllowed,
+		"UK":  ErrNotAllowed,
+		"NZL": ErrNotAllowed,
+		"":    ErrNotAllowed,
+		554:   ErrWrongType,
+	})
+}
+
+func TestLanguageTag(t *testing.T) {
+	testFormat(t, "LanguageTag", LanguageTag, map[interface{}]error{
+		"en":                       nil,
+		"en-US":                    nil,
+		"zh-Hant-TW":               nil,
+		"es-419":                   nil,
+		"zh-yue-HK":                nil,
+		"sl-rozaj-biske":           nil,
+		"de-CH-1901":               nil,
+		"en-US-u-ca-gregory-x-pvt": nil,
+		"x-whatever":               nil,
+		"i-klingon":                nil,
+		"EN-gb-OED":                nil,
+		"xx-QQ":                    nil,
+		"":                         ErrBadFormat,
+		"e":                        ErrBadFormat,
+		"en_US":                    ErrBadFormat,
+		"en-":                      ErrBadFormat,
+		"en--US":                   ErrBadFormat,
+		"en-US-u":                  ErrBadFormat,
+		"en-a-bbb-a-ccc":           ErrBadFormat,
+		"de-1901-1901":             ErrBadFormat,
+		"en-x":                     ErrBadFormat,
+		"abcdefghi":                ErrBadFormat,
+		"en-US-toolongsubtag":      ErrBadFormat,
+		"en-Latn-Latn":             ErrBadFormat,
+		"123":                      ErrBadFormat,
+		true:                       ErrWrongType,
+	})
+}
+
+func TestTimeZone(t *testing.T) {
+	testFormat(t, "TimeZone", TimeZone, map[interface{}]error{
+		"America/New_York": nil,
+		"Europe/Berlin":    nil,
+		"UTC":              nil,
+		"Local":            ErrNotAllowed,
+		"":                 ErrNotAllowed,
+		"Mars/Olympus":     ErrNotAllowed,
+		"../etc/passwd":    ErrNotAllowed,
+		0:                  ErrWrongType,
+	})
+}
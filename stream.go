@@ -0,0 +1,108 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"context"
+	"runtime"
+)
+
+// Result holds the errors found in an item by ValidateStream.
+type Result struct {
+	Seq  int         // the item's position in the stream, counting from 0
+	Item interface{} // the item itself
+	Errs []error     // the errors Validate returns for the item
+}
+
+// ValidateStream validates the items received from items as they arrive,
+// for pipelines validating more records than can be held at once. Each item
+// is validated as by ValidateOpts, given opts and the Context ctx, by one
+// of a pool of goroutines, whose size is set by the Workers option.
+//
+// A Result is sent for every item, valid or not, in the order the items
+// were received. The returned channel is closed once items is closed and
+// every Result has been sent, or once ctx is done, after which no more
+// items are received:
+//
+//	for r := range v.ValidateStream(ctx, records, validate.Workers(8)) {
+//		if r.Errs != nil {
+//			log.Printf("record %d: %v", r.Seq, r.Errs)
+//		}
+//	}
+//	if err := ctx.Err(); err != nil {
+//		…
+//	}
+func (v V) ValidateStream(ctx context.Context, items <-chan interface{}, opts ...Option) <-chan Result {
+	opts = append([]Option{Context(ctx)}, opts...)
+	w := walker{v: v}
+	for _, o := range opts {
+		o(&w)
+	}
+	n := w.workers
+	if n < 1 {
+		n = runtime.GOMAXPROCS(0)
+	}
+
+	// Each item's Result is sent on its own channel, which is queued in
+	// pending, so that the Results are sent in order however long each
+	// item takes. At most n items are validated or waiting to be sent.
+	type job struct {
+		seq  int
+		item interface{}
+		out  chan Result
+	}
+	jobs := make(chan job)
+	pending := make(chan chan Result, n)
+	results := make(chan Result)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			for j := range jobs {
+				j.out <- Result{j.seq, j.item, v.ValidateOpts(j.item, opts...)}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		defer close(pending)
+		for seq := 0; ; seq++ {
+			var item interface{}
+			select {
+			case <-ctx.Done():
+				return
+			case i, ok := <-items:
+				if !ok {
+					return
+				}
+				item = i
+			}
+			out := make(chan Result, 1)
+			select {
+			case <-ctx.Done():
+				return
+			case pending <- out:
+			}
+			jobs <- job{seq, item, out}
+		}
+	}()
+
+	go func() {
+		defer close(results)
+		for out := range pending {
+			var r Result
+			select {
+			case <-ctx.Done():
+				return
+			case r = <-out:
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case results <- r:
+			}
+		}
+	}()
+
+	return results
+}
@@ -0,0 +1,70 @@
+package validate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestV_ValidateStream(t *testing.T) {
+	type X struct {
+		N int `validate:"slow"`
+	}
+
+	vd := V{"slow": func(i interface{}) error {
+		n := i.(int)
+		time.Sleep(time.Duration(n%3) * time.Millisecond)
+		if n%2 != 0 {
+			return errors.New("odd")
+		}
+		return nil
+	}}
+
+	items := make(chan interface{})
+	go func() {
+		defer close(items)
+		for n := 0; n < 50; n++ {
+			items <- X{n}
+		}
+	}()
+	seq := 0
+	for r := range vd.ValidateStream(context.Background(), items, Workers(4), Root("item")) {
+		if r.Seq != seq || r.Item.(X).N != seq {
+			t.Fatalf("result %d out of order: %+v", seq, r)
+		}
+		if odd := seq%2 != 0; odd != (len(r.Errs) == 1) {
+			t.Fatalf("wrong errors for %d: %v", seq, r.Errs)
+		}
+		if r.Errs != nil && r.Errs[0].Error() != "field item.N is invalid: odd" {
+			t.Fatal("wrong error:", r.Errs[0])
+		}
+		seq++
+	}
+	if seq != 50 {
+		t.Fatalf("got %d results, wanted 50", seq)
+	}
+}
+
+func TestV_ValidateStream_cancel(t *testing.T) {
+	type X struct {
+		N int `validate:"nonzero"`
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	items := make(chan interface{})
+	results := Builtin().ValidateStream(ctx, items)
+	items <- X{}
+	if r := <-results; r.Seq != 0 || len(r.Errs) != 1 {
+		t.Fatalf("wrong result: %+v", r)
+	}
+	cancel()
+	select {
+	case _, ok := <-results:
+		if ok {
+			t.Fatal("received a result after canceling")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("results not closed after canceling")
+	}
+}
@@ -0,0 +1,134 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"encoding"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// ValidateStringMap validates values, such as a url.Values, as though they
+// had been decoded into a struct of the same type as sample, returning
+// the errors Validate would return for that struct. The caller needn't
+// construct the struct, which suits quick checks of query strings and
+// form posts before they are handled properly.
+//
+// Each key names a field as it would appear in a BadField, so opts such as
+// NameTags("json") match keys to fields by their tags. Its values are
+// converted to the field's type, which may be a string, bool, number,
+// time.Duration, or encoding.TextUnmarshaler, a pointer to one of those,
+// or a slice of them, which receives every value of the key. Other fields
+// take the first value. Keys naming no field are ignored, and fields with
+// no key are validated as zero. A value that cannot be converted is
+// reported with an error wrapping ErrWrongType, in place of the field's
+// rules.
+func (v V) ValidateStringMap(values map[string][]string, sample interface{}, opts ...Option) []error {
+	t := reflect.TypeOf(sample)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	w := walker{v: v}
+	for _, o := range opts {
+		o(&w)
+	}
+
+	s := reflect.New(t).Elem()
+	var errs []error
+	var bad map[string]bool
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fv := s.Field(i)
+		name := w.fieldName(f)
+		vals := values[name]
+		if len(vals) == 0 || !fv.CanSet() {
+			continue
+		}
+		if err := setString(fv, vals); err != nil {
+			errs = w.fail(errs, t, []string{name}, BadField{Err: err, Value: vals[0]})
+			if bad == nil {
+				bad = make(map[string]bool)
+			}
+			bad[name] = true
+		}
+	}
+
+	if bad != nil && w.mask == nil {
+		w.mask = make(map[string]bool, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			if name := w.fieldName(t.Field(i)); !bad[name] {
+				w.mask[name] = true
+			}
+		}
+	}
+	return append(errs, w.validate(s, nil)...)
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// setString sets v to the value represented by vals.
+func setString(v reflect.Value, vals []string) error {
+	if reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+		if err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(vals[0])); err != nil {
+			return errorf(ErrWrongType, "%q is not a valid %v: %v", vals[0], v.Type(), err)
+		}
+		return nil
+	}
+
+	s := vals[0]
+	var err error
+	switch v.Kind() {
+	case reflect.Ptr:
+		p := reflect.New(v.Type().Elem())
+		if err := setString(p.Elem(), vals); err != nil {
+			return err
+		}
+		v.Set(p)
+		return nil
+	case reflect.Slice:
+		sv := reflect.MakeSlice(v.Type(), len(vals), len(vals))
+		for i := range vals {
+			if err := setString(sv.Index(i), vals[i:i+1]); err != nil {
+				return err
+			}
+		}
+		v.Set(sv)
+		return nil
+	case reflect.String:
+		v.SetString(s)
+		return nil
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(s)
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Type() == reflect.TypeOf(time.Duration(0)) {
+			var d time.Duration
+			d, err = time.ParseDuration(s)
+			v.SetInt(int64(d))
+			break
+		}
+		var n int64
+		n, err = strconv.ParseInt(s, 0, v.Type().Bits())
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var n uint64
+		n, err = strconv.ParseUint(s, 0, v.Type().Bits())
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(s, v.Type().Bits())
+		v.SetFloat(f)
+	default:
+		return errorf(ErrWrongType, "cannot convert %q to %v", s, v.Type())
+	}
+	if err != nil {
+		return errorf(ErrWrongType, "%q is not a valid %v", s, v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestV_ValidateStringMap(t *testing.T) {
+	type Query struct {
+		Name    string        `json:"name" validate:"nonzero"`
+		Limit   int           `json:"limit" validate:"positive"`
+		Tags    []string      `json:"tag" validate:"few"`
+		Timeout time.Duration `json:"timeout" validate:"positive"`
+		Since   *time.Time    `json:"since"`
+	}
+
+	vd := make(V)
+	vd["nonzero"] = func(i interface{}) error {
+		if i.(string) == "" {
+			return fmt.Errorf("should be nonzero")
+		}
+		return nil
+	}
+	vd["positive"] = func(i interface{}) error {
+		if fmt.Sprint(i)[0] == '-' {
+			return fmt.Errorf("should be positive")
+		}
+		return nil
+	}
+	vd["few"] = func(i interface{}) error {
+		if len(i.([]string)) > 2 {
+			return fmt.Errorf("too many")
+		}
+		return nil
+	}
+
+	q, _ := url.ParseQuery("name=a&limit=5&tag=x&tag=y&timeout=1s&since=2024-01-02T00:00:00Z")
+	if errs := vd.ValidateStringMap(q, Query{}, NameTags("json")); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	q, _ = url.ParseQuery("limit=-1&tag=x&tag=y&tag=z&timeout=soon&since=today")
+	errs := vd.ValidateStringMap(q, &Query{}, NameTags("json"))
+	want := "[field name is invalid: should be nonzero " +
+		"field limit is invalid: should be positive " +
+		"field tag is invalid: too many]"
+	if len(errs) != 5 {
+		t.Fatalf("wrong number of errors: %v", errs)
+	}
+	if fmt.Sprint(errs[2:]) != want {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+	for _, err := range errs[:2] {
+		if !errors.Is(err.(BadField).Err, ErrWrongType) {
+			t.Fatalf("wrong error for an unconvertible value: %v", err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RegisterStruct adds fn to v as the validator for structs of the type of
+// sample, which may be a struct or a pointer to one, replacing any already
+// present. Validate passes fn each struct of the type that it validates,
+// after checking the struct's fields, so that fn can check invariants
+// spanning several of them:
+//
+//	vd.RegisterStruct(Contact{}, func(i interface{}) error {
+//		c := i.(Contact)
+//		if c.Email == "" && c.Phone == "" {
+//			return errors.New("needs an email address or a phone number")
+//		}
+//		return nil
+//	})
+//
+// The error fn returns is reported with the path to the struct, which is
+// empty for the value passed to Validate, and no Rule. The validator is
+// kept alongside the validators in v, not among them.
+func (v V) RegisterStruct(sample interface{}, fn func(interface{}) error) {
+	t := reflect.TypeOf(sample)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return
+	}
+	m := v.editMeta()
+	if m.structs == nil {
+		m.structs = make(map[reflect.Type]func(interface{}) error)
+	}
+	m.structs[t] = fn
+}
+
+// StructValidator returns the validator added to v by RegisterStruct for
+// structs of type t, or nil if there is none. It is for code, such as that
+// written by validategen, that validates structs without Validate.
+func (v V) StructValidator(t reflect.Type) func(interface{}) error {
+	for _, l := range v.layers() {
+		if l.m != nil && l.m.structs[t] != nil {
+			return l.m.structs[t]
+		}
+	}
+	return nil
+}
+
+// checkStruct passes the struct in val, found at path, to the validator
+// for its type, if there is one, and appends the error it reports to errs.
+// The struct must be selected by the walker's mask.
+func (w *walker) checkStruct(errs []error, val reflect.Value, path []string) (result []error) {
+	t := val.Type()
+	var fn func(interface{}) error
+	ls, _ := w.layers()
+	for _, l := range ls {
+		if l.m != nil && fn == nil {
+			fn = l.m.structs[t]
+		}
+	}
+	if fn == nil || w.done || w.planning || !val.CanInterface() {
+		return errs
+	}
+
+	x := val.Interface()
+	if !w.noRecover {
+		defer func() {
+			if p := recover(); p != nil {
+				err := fmt.Errorf("%w: %v: %v", ErrValidatorPanicked, t, p)
+				result = w.fail(errs, t, path, BadField{Err: err, Value: x})
+			}
+		}()
+	}
+	if err := fn(x); err != nil {
+		errs = w.fail(errs, t, path, BadField{Err: err, Value: x})
+	}
+	return errs
+}
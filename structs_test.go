@@ -0,0 +1,72 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestV_RegisterStruct(t *testing.T) {
+	type Contact struct {
+		Email string `validate:"nonzero"`
+		Phone string
+	}
+	type X struct {
+		Home Contact  `validate:"struct"`
+		Work *Contact `validate:"struct"`
+	}
+
+	vd := V{"nonzero": nonzero}
+	vd.RegisterStruct(&Contact{}, func(i interface{}) error {
+		if c := i.(Contact); c.Email == "" && c.Phone == "" {
+			return errors.New("needs an email address or a phone number")
+		}
+		return nil
+	})
+	vd.RegisterStruct(X{}, func(interface{}) error { panic("oops") })
+
+	errs := vd.Validate(X{Work: &Contact{Phone: "555"}})
+	if len(errs) != 4 {
+		t.Fatalf("wrong number of errors: %v", errs)
+	}
+	if errs[0].Error() != "field Home.Email is invalid: should be nonzero" {
+		t.Fatal("field rules should be checked first:", errs[0])
+	}
+	if bf := errs[1].(BadField); bf.Error() != "field Home is invalid: needs an email address or a phone number" || bf.Rule != "" || bf.Value != (Contact{}) {
+		t.Fatalf("wrong error for Home: %#v", bf)
+	}
+	if errs[2].Error() != "field Work.Email is invalid: should be nonzero" {
+		t.Fatal("wrong error for Work:", errs[2])
+	}
+	if !errors.Is(errs[3], ErrValidatorPanicked) || errs[3].(BadField).Field != "" {
+		t.Fatal("wrong error for the panic:", errs[3])
+	}
+
+	if errs := vd.Validate(Contact{}); len(errs) != 2 || errs[1].Error() != "struct is invalid: needs an email address or a phone number" {
+		t.Fatalf("wrong errors for the value itself: %v", errs)
+	}
+	if errs := vd.ValidateMasked(X{}, []string{"Work"}); len(errs) != 0 {
+		t.Fatalf("checked a struct not selected: %v", errs)
+	}
+}
+
+func ExampleV_RegisterStruct() {
+	type Contact struct {
+		Email, Phone string
+	}
+
+	vd := make(V)
+	vd.RegisterStruct(Contact{}, func(i interface{}) error {
+		c := i.(Contact)
+		if c.Email == "" && c.Phone == "" {
+			return errors.New("needs an email address or a phone number")
+		}
+		return nil
+	})
+
+	fmt.Println(vd.Validate(Contact{}))
+	fmt.Println(vd.Validate(Contact{Phone: "555-0100"}))
+
+	// Output: [struct is invalid: needs an email address or a phone number]
+	// []
+}
@@ -0,0 +1,97 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule is a single validator named in a validate tag,
+// with the parameter given to it, if any.
+// In a tag, a parameter follows its rule's name and an equals sign,
+// as in "method=Check".
+//
+// A rule whose name is followed by a colon instead, as in
+// "expr:a < b, b < c", takes the whole remainder of the tag as its
+// parameter, commas and all, so that validators can accept parameters
+// in a language of their own without fighting the tag syntax.
+// Such a rule must be the last in its tag.
+//
+// Rules separated by "|" rather than by commas are alternatives, of which
+// only one need pass, as in "hexcolor|rgbcolor". The parameters of such
+// rules cannot contain "|", except in the colon form.
+type Rule struct {
+	Name  string
+	Param string
+
+	// Or reports whether the rule is an alternative to the rule
+	// before it, rather than an additional rule.
+	Or bool
+}
+
+// Params returns the space-separated parts of r's parameter.
+// It returns nil if r has no parameter.
+func (r Rule) Params() []string {
+	if r.Param == "" {
+		return nil
+	}
+	return strings.Fields(r.Param)
+}
+
+// ParseTag splits the value of a validate tag into the rules it names,
+// in the order they appear. The rules are interpreted exactly as Validate
+// interprets them, so tools that inspect tags can use ParseTag rather than
+// reimplementing the syntax.
+//
+// An empty tag has no rules. A tag naming an empty rule, such as "a,,b"
+// or "=x", is an error.
+func ParseTag(tag string) ([]Rule, error) {
+	if tag == "" {
+		return nil, nil
+	}
+
+	var rules []Rule
+	for rest := tag; ; {
+		part, more, found := strings.Cut(rest, ",")
+		for off, or := 0, false; ; or = true {
+			alt, _, isOr := strings.Cut(part[off:], "|")
+			name, param, _ := strings.Cut(alt, "=")
+			if i := strings.IndexByte(alt, ':'); i >= 0 && i < len(name) {
+				name, param, found, isOr = alt[:i], rest[off+i+1:], false, false
+			}
+			if name == "" {
+				return nil, fmt.Errorf("empty rule at position %d in tag %q", len(rules)+1, tag)
+			}
+			rules = append(rules, Rule{Name: name, Param: param, Or: or})
+			if !isOr {
+				break
+			}
+			off += len(alt) + 1
+		}
+		if !found {
+			return rules, nil
+		}
+		rest = more
+	}
+}
+
+// Reserved reports whether the rule name is interpreted by Validate itself,
+// as described in the package documentation, rather than naming
+// a validator.
+func Reserved(name string) bool {
+	switch name {
+	case "struct", "sensitive", "method", "each", "keys", "values", "required", "omitempty", "on", "msg",
+		"required_if", "required_with", "required_without", "present":
+		return true
+	}
+	return false
+}
+
+// notReserved panics if name is that of a reserved rule, under which a
+// validator would never be called.
+func notReserved(name string) {
+	if Reserved(name) {
+		panic(fmt.Sprintf("validate: %q is a reserved rule and cannot name a validator", name))
+	}
+}
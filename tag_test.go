@@ -0,0 +1,149 @@
+package validate
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		tag   string
+		rules []Rule
+	}{
+		{"", nil},
+		{"long", []Rule{{Name: "long"}}},
+		{"struct,odd", []Rule{{Name: "struct"}, {Name: "odd"}}},
+		{"min=5,oneof=a b", []Rule{{Name: "min", Param: "5"}, {Name: "oneof", Param: "a b"}}},
+		{"x=", []Rule{{Name: "x"}}},
+		{"odd,expr:a < b, b = c", []Rule{{Name: "odd"}, {Name: "expr", Param: "a < b, b = c"}}},
+		{"x=a:b,y", []Rule{{Name: "x", Param: "a:b"}, {Name: "y"}}},
+		{"a|b=1|c,d", []Rule{{Name: "a"}, {Name: "b", Param: "1", Or: true}, {Name: "c", Or: true}, {Name: "d"}}},
+		{"a|re:x|y,z", []Rule{{Name: "a"}, {Name: "re", Param: "x|y,z", Or: true}}},
+	}
+
+	for _, test := range tests {
+		rules, err := ParseTag(test.tag)
+		if err != nil {
+			t.Fatalf("unexpected error parsing %q: %v", test.tag, err)
+		}
+		if !reflect.DeepEqual(rules, test.rules) {
+			t.Fatalf("wrong rules for %q: %v", test.tag, rules)
+		}
+	}
+}
+
+func TestParseTag_empty(t *testing.T) {
+	for _, tag := range []string{",", "a,", ",a", "a,,b", "=a", ":a", "a,:b", "a|", "|a", "a||b"} {
+		if _, err := ParseTag(tag); err == nil {
+			t.Fatalf("no error for empty rule in %q", tag)
+		}
+	}
+}
+
+func TestV_Validate_badtag(t *testing.T) {
+	type X struct {
+		A int `validate:"odd,,odd"`
+	}
+
+	vd := make(V)
+	vd["odd"] = func(i interface{}) error {
+		return nil
+	}
+
+	errs := vd.Validate(X{})
+	if len(errs) != 1 {
+		t.Fatalf("wrong number of errors for a malformed tag: %v", errs)
+	}
+	if errs[0].(BadField).Field != "A" {
+		t.Fatalf("wrong field for a malformed tag: %v", errs[0])
+	}
+}
+
+func TestRule_Params(t *testing.T) {
+	if p := (Rule{Name: "a"}).Params(); p != nil {
+		t.Fatalf("wrong params for a rule without a parameter: %q", p)
+	}
+	if p := (Rule{Name: "oneof", Param: "red  green blue"}).Params(); !reflect.DeepEqual(p, []string{"red", "green", "blue"}) {
+		t.Fatalf("wrong params: %q", p)
+	}
+}
+
+func TestV_Validate_raw(t *testing.T) {
+	type X struct {
+		A int `validate:"nonzero,expr:a, b, c"`
+	}
+
+	vd := make(V)
+	vd["nonzero"] = func(i interface{}) error {
+		return nil
+	}
+	var param string
+	vd.RegisterField("expr", func(f Field) error {
+		param = f.Rule.Param
+		return nil
+	})
+
+	if errs := vd.Validate(X{1}); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if param != "a, b, c" {
+		t.Fatalf("wrong parameter for a raw rule: %q", param)
+	}
+}
+
+func TestV_Validate_or(t *testing.T) {
+	type X struct {
+		A string `validate:"hex|rgb"`
+		B string `validate:"hex|rgb,long"`
+		C string `validate:"hex|rgb|nope"`
+		D string `validate:"struct|hex"`
+		E string `validate:"sensitive,hex|rgb"`
+	}
+
+	errHex := errors.New("not hex")
+	vd := V{
+		"long": long,
+		"hex": func(i interface{}) error {
+			if !strings.HasPrefix(i.(string), "#") {
+				return errHex
+			}
+			return nil
+		},
+		"rgb": func(i interface{}) error {
+			if !strings.HasPrefix(i.(string), "rgb(") {
+				return errors.New("not rgb")
+			}
+			return nil
+		},
+	}
+
+	if errs := vd.Validate(X{"#fff", "rgb(1,2,3)", "#000", "", "#abc"}); len(errs) != 1 || errs[0].(BadField).Field != "D" {
+		t.Fatalf("wrong errors for passing alternatives: %v", errs)
+	}
+
+	errs := vd.Validate(X{"red", "x", "red", "", "s3cret"})
+	want := []string{
+		"field A is invalid: not hex, or not rgb",
+		"field B is invalid: not hex, or not rgb",
+		"field B is invalid: too short",
+		`field C is invalid: undefined validator: "nope"`,
+		"field C is invalid: not hex, or not rgb",
+		`field D is invalid: reserved rule "struct" cannot have alternatives`,
+	}
+	if len(errs) != len(want)+1 {
+		t.Fatalf("wrong number of errors: %v", errs)
+	}
+	for i, w := range want {
+		if errs[i].Error() != w {
+			t.Fatalf("wrong error %d: %v, wanted %s", i, errs[i], w)
+		}
+	}
+	if bf := errs[0].(BadField); bf.Rule != "hex|rgb" || !errors.Is(bf.Err, errHex) {
+		t.Fatalf("wrong details for failed alternatives: %+v", bf)
+	}
+	if s := errs[len(want)].Error(); strings.Contains(s, "s3cret") || errors.Is(errs[len(want)].(BadField).Err, errHex) {
+		t.Fatalf("wrong error for sensitive alternatives: %s", s)
+	}
+}
@@ -0,0 +1,105 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A Message is an error whose text can be translated by a Catalog.
+// Validators can return Messages, or errors wrapping them,
+// to have their errors localized:
+//
+//	return validate.Message{"must be at least %d characters", []interface{}{n}}
+//
+// The errors of the validators provided by this package are Messages,
+// or wrap them, as are the errors of the "required" rules.
+type Message struct {
+	// Key is the format of the message in the source language,
+	// as for fmt.Sprintf, and identifies it in catalogs.
+	Key string
+
+	// Args are the values formatted by Key.
+	Args []interface{}
+}
+
+func (m Message) Error() string {
+	if len(m.Args) == 0 {
+		return m.Key
+	}
+	return fmt.Sprintf(m.Key, m.Args...)
+}
+
+// A Catalog holds translations of messages, keyed by locale and then by
+// the messages' keys. A translation is a format for the message's Args,
+// which can be reordered with explicit argument indexes:
+//
+//	validate.Catalog{
+//		"de": {
+//			"must be at least %d characters": "muss mindestens %d Zeichen lang sein",
+//		},
+//	}
+//
+// Users can plug in catalogs from elsewhere, such as translation files,
+// by loading them into a Catalog.
+type Catalog map[string]map[string]string
+
+// Lookup returns the translation of key into locale, and whether there is
+// one. Failing a translation for the locale itself, such as "fr-CH",
+// Lookup tries its primary language, "fr".
+func (c Catalog) Lookup(locale, key string) (string, bool) {
+	if f, ok := c[locale][key]; ok {
+		return f, true
+	}
+	base, _, _ := strings.Cut(locale, "-")
+	f, ok := c[base][key]
+	return f, ok
+}
+
+// Translate returns a copy of errs, as returned by Validate, with the
+// messages of the errors in BadFields translated into locale. Errors
+// without a translation are unchanged, and translated errors wrap
+// the originals, so errors.Is and errors.As still examine them.
+//
+// The messages given by "msg" rules are keys too, which can be
+// translated, but the messages of errors that merely wrap a Message,
+// such as those of Each, are not.
+//
+// Errors found by validating with a context carrying a Catalog, as set
+// by WithCatalog or Catalog.Localize, are translated into the context's
+// locale without calling Translate.
+func (c Catalog) Translate(errs []error, locale string) []error {
+	out := make([]error, len(errs))
+	for i, err := range errs {
+		out[i] = c.translate(err, locale)
+	}
+	return out
+}
+
+func (c Catalog) translate(err error, locale string) error {
+	var m Message
+	switch e := err.(type) {
+	case BadField:
+		e.Err = c.translate(e.Err, locale)
+		return e
+	case Errors:
+		return Errors(c.Translate(e, locale))
+	case alternatives:
+		return alternatives(c.Translate(e, locale))
+	case Message:
+		m = e
+	case kindError:
+		m = e.msg
+	case message:
+		m = Message{Key: e.text}
+	default:
+		return err
+	}
+
+	f, ok := c.Lookup(locale, m.Key)
+	if !ok {
+		return err
+	}
+	return message{Message{f, m.Args}.Error(), err}
+}
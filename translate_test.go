@@ -0,0 +1,61 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCatalog_Translate(t *testing.T) {
+	type X struct {
+		Name  string `validate:"min3"`
+		Email string `validate:"email"`
+		Code  string `validate:"msg=invalid code,alpha"`
+		Color string `validate:"alpha|numeric"`
+		Extra string `validate:"odd"`
+	}
+
+	vd := Builtin()
+	vd["min3"] = func(i interface{}) error {
+		return Message{"must be at least %d characters, not %d", []interface{}{3, len(i.(string))}}
+	}
+	vd["odd"] = func(interface{}) error {
+		return errors.New("is even")
+	}
+	cat := Catalog{
+		"de": {
+			"must be at least %d characters, not %d": "darf nicht %[2]d, sondern muss mindestens %[1]d Zeichen lang sein",
+			"%q is not a valid email address":        "%q ist keine gültige E-Mail-Adresse",
+			"invalid code":                           "ungültiger Code",
+			"empty string is not alphabetic":         "leere Zeichenkette ist nicht alphabetisch",
+		},
+	}
+
+	errs := vd.Validate(X{Email: "x", Code: "1", Color: ""})
+	want := []string{
+		"field Name is invalid: darf nicht 0, sondern muss mindestens 3 Zeichen lang sein",
+		`field Email is invalid: "x" ist keine gültige E-Mail-Adresse`,
+		"field Code is invalid: ungültiger Code",
+		`field Color is invalid: leere Zeichenkette ist nicht alphabetisch, or "" is not numeric`,
+		"field Extra is invalid: is even",
+	}
+	for _, locale := range []string{"de", "de-AT"} {
+		got := cat.Translate(errs, locale)
+		if len(got) != len(want) {
+			t.Fatalf("wrong number of errors in %s: %v", locale, got)
+		}
+		for i, err := range got {
+			if err.Error() != want[i] {
+				t.Fatalf("wrong translation into %s: %q, wanted %q", locale, err, want[i])
+			}
+		}
+		if !errors.Is(got[1], ErrBadFormat) || got[1].(BadField).Rule != "email" {
+			t.Fatalf("translation lost the original error: %#v", got[1])
+		}
+	}
+
+	for i, err := range cat.Translate(errs, "fr") {
+		if err.Error() != errs[i].Error() {
+			t.Fatalf("error translated into a missing locale: %v", err)
+		}
+	}
+}
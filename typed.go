@@ -0,0 +1,187 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import "reflect"
+
+// Register adds fn to v as the validator named name, replacing any
+// already present. The validator passes fn the values of type T, and
+// reports values of other types with an error wrapping ErrWrongType,
+// so fn needs no type assertion of its own:
+//
+//	validate.Register(vd, "port", func(n int) error { … })
+//
+// If T is an interface type, fn is passed every value implementing it.
+// Check reports rules naming the validator for fields of other types,
+// which it learns from the type kept alongside the validator in v.
+func Register[T any](v V, name string, fn func(T) error) {
+	notReserved(name)
+	delete(v, name+"=")
+	v.setAccepts(name, accepts{types: []reflect.Type{reflect.TypeOf((*T)(nil)).Elem()}})
+	v[name] = func(i interface{}) error {
+		x, ok := i.(T)
+		if !ok {
+			return errorf(ErrWrongType, "validator %q does not accept %T", name, i)
+		}
+		return fn(x)
+	}
+}
+
+// RegisterFor adds fn to v as the implementation of the rule name for
+// values of type T, keeping any implementations already registered for
+// other types, including those of a V that v overlays. This lets a rule
+// with a general name behave sensibly for each type it is used with:
+//
+//	validate.RegisterFor(vd, "nonzero", func(s string) error { … })
+//	validate.RegisterFor(vd, "nonzero", func(t time.Time) error { … })
+//
+// If T is an interface type, fn is used for every value implementing it.
+// Implementations registered later take precedence. A value for which no
+// implementation is registered is reported with an error wrapping
+// ErrWrongType, and Check reports rules naming the validator for fields
+// of such types, unless it was first added by other means, such as by
+// assigning to v, that accept anything.
+func RegisterFor[T any](v V, name string, fn func(T) error) {
+	notReserved(name)
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	// The implementations for other types may be those of a V that v
+	// overlays, which are found as Validate finds them.
+	var next, nextField func(interface{}) error
+	var m *meta
+	for _, l := range v.layers() {
+		next, nextField, m = l.v[name], l.v[name+"="], l.m
+		if next != nil || nextField != nil {
+			break
+		}
+	}
+	if nextField != nil && next == nil {
+		if m != nil && m.params[name] != nil {
+			check := m.params[name]
+			v.editMeta().params[name] = func(t reflect.Type, param string) error {
+				if t != nil && t.AssignableTo(typ) {
+					return nil
+				}
+				return check(t, param)
+			}
+		}
+		v[name+"="] = func(i interface{}) error {
+			f := i.(Field)
+			if f.Value.CanInterface() {
+				if x, ok := f.Value.Interface().(T); ok {
+					return fn(x)
+				}
+			}
+			return nextField(f)
+		}
+		return
+	}
+
+	if next == nil {
+		v.setAccepts(name, accepts{types: []reflect.Type{typ}})
+	} else {
+		v.extendAccepts(name, typ, m)
+	}
+	v[name] = func(i interface{}) error {
+		if x, ok := i.(T); ok {
+			return fn(x)
+		}
+		if next == nil {
+			return errorf(ErrWrongType, "validator %q does not accept %T", name, i)
+		}
+		return next(i)
+	}
+}
+
+// RegisterTyped adds fn to v as the validator named name, replacing any
+// already present. The validator passes fn the values whose kind is kind,
+// and reports other values with an error wrapping ErrWrongType, so that
+// fn can make type assertions without fear of panicking:
+//
+//	vd.RegisterTyped("odd", reflect.Int, func(i interface{}) error {
+//		if i.(int)%2 == 0 {
+//			…
+//		}
+//		…
+//	})
+//
+// Check reports rules naming the validator for fields of other kinds,
+// which it learns from the kind kept alongside the validator in v.
+// Values of named types, such as time.Duration for reflect.Int64, are
+// passed to fn as they are, so it should use reflection to accept them.
+func (v V) RegisterTyped(name string, kind reflect.Kind, fn func(interface{}) error) {
+	notReserved(name)
+	delete(v, name+"=")
+	v.setAccepts(name, accepts{kind: kind})
+	v[name] = func(i interface{}) error {
+		if reflect.ValueOf(i).Kind() != kind {
+			return errorf(ErrWrongType, "validator %q does not accept %T", name, i)
+		}
+		return fn(i)
+	}
+}
+
+// accepts describes the values a validator accepts: those of kind,
+// if it is valid, and those assignable to any of types.
+type accepts struct {
+	kind  reflect.Kind
+	types []reflect.Type
+}
+
+// setAccepts records that the validator for the rule name accepts only
+// the values described by a.
+func (v V) setAccepts(name string, a accepts) {
+	m := v.editMeta()
+	if m.accepts == nil {
+		m.accepts = make(map[string]accepts)
+	}
+	m.accepts[name] = a
+}
+
+// extendAccepts records that the validator for the rule name accepts the
+// values of type t as well as those it accepts according to from, the
+// meta of the V defining it, if it accepts only some values.
+func (v V) extendAccepts(name string, t reflect.Type, from *meta) {
+	if from == nil {
+		return
+	}
+	if a, ok := from.accepts[name]; ok {
+		a.types = append(a.types[:len(a.types):len(a.types)], t)
+		v.setAccepts(name, a)
+	}
+}
+
+// clearAccepts forgets what the validator for the rule name accepts,
+// so that it is taken to accept anything.
+func (v V) clearAccepts(name string) {
+	if m := v.meta(); m != nil {
+		if _, ok := m.accepts[name]; ok {
+			delete(v.editMeta().accepts, name)
+		}
+	}
+}
+
+// accepts reports whether the validator for the rule name accepts values
+// of type t, which it does unless it was added by Register or RegisterTyped
+// for other values.
+func (w *walker) accepts(name string, t reflect.Type) bool {
+	var a accepts
+	ok := false
+	ls, _ := w.layers()
+	for _, l := range ls {
+		if l.m != nil {
+			a, ok = l.m.accepts[name]
+		}
+		if ok || l.v[name] != nil {
+			break
+		}
+	}
+	if !ok || a.kind != reflect.Invalid && t.Kind() == a.kind {
+		return true
+	}
+	for _, at := range a.types {
+		if t.AssignableTo(at) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,211 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegister(t *testing.T) {
+	type X struct {
+		A int    `validate:"port"`
+		B string `validate:"port"`
+	}
+
+	vd := make(V)
+	vd.RegisterField("port", func(Field) error { return nil })
+	Register(vd, "port", func(n int) error {
+		if n < 1 || n > 65535 {
+			return fmt.Errorf("%d is not a port", n)
+		}
+		return nil
+	})
+
+	errs := vd.Validate(X{0, "80"})
+	if len(errs) != 2 || errs[0].Error() != "field A is invalid: 0 is not a port" {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+	if err := errs[1].(BadField).Err; !errors.Is(err, ErrWrongType) || err.Error() != `validator "port" does not accept string` {
+		t.Fatalf("wrong error for a mismatched type: %v", err)
+	}
+}
+
+func TestRegisterFor(t *testing.T) {
+	type X struct {
+		A string        `validate:"nonzero"`
+		B int           `validate:"nonzero"`
+		C time.Duration `validate:"nonzero"`
+		D float64       `validate:"nonzero"`
+	}
+
+	vd := make(V)
+	RegisterFor(vd, "nonzero", func(s string) error {
+		if s == "" {
+			return fmt.Errorf("empty string")
+		}
+		return nil
+	})
+	RegisterFor(vd, "nonzero", func(n int) error {
+		if n == 0 {
+			return fmt.Errorf("zero int")
+		}
+		return nil
+	})
+	RegisterFor(vd, "nonzero", func(s fmt.Stringer) error {
+		return fmt.Errorf("zero %s", s)
+	})
+
+	errs := vd.Validate(X{})
+	if len(errs) != 4 {
+		t.Fatalf("wrong number of errors: %v", errs)
+	}
+	for i, msg := range []string{"empty string", "zero int", "zero 0s"} {
+		if errs[i].(BadField).Err.Error() != msg {
+			t.Fatalf("wrong error %d: %v", i, errs[i])
+		}
+	}
+	if !errors.Is(errs[3].(BadField).Err, ErrWrongType) {
+		t.Fatalf("wrong error for unregistered type: %v", errs[3])
+	}
+}
+
+func TestRegisterFor_extended(t *testing.T) {
+	type X struct {
+		A string `validate:"check"`
+		B int    `validate:"check"`
+	}
+
+	vd := make(V)
+	vd.RegisterField("check", func(f Field) error {
+		return fmt.Errorf("extended %v", f.Value)
+	})
+	RegisterFor(vd, "check", func(s string) error {
+		return fmt.Errorf("string %s", s)
+	})
+
+	errs := vd.Validate(X{"a", 1})
+	if fmt.Sprint(errs) != "[field A is invalid: string a field B is invalid: extended 1]" {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+}
+
+func TestRegisterFor_overlay(t *testing.T) {
+	type X struct {
+		A string `validate:"check"`
+		B int    `validate:"check"`
+		C bool   `validate:"check"`
+		D string `validate:"field"`
+		E int    `validate:"field"`
+	}
+
+	base := make(V)
+	RegisterFor(base, "check", func(n int) error {
+		return fmt.Errorf("int %d", n)
+	})
+	base.RegisterField("field", func(f Field) error {
+		return fmt.Errorf("extended %v", f.Value)
+	})
+	vd := base.WithOverlay(nil)
+	RegisterFor(vd, "check", func(s string) error {
+		return fmt.Errorf("string %s", s)
+	})
+	RegisterFor(vd, "field", func(s string) error {
+		return fmt.Errorf("string %s", s)
+	})
+
+	errs := vd.Validate(X{"a", 1, false, "d", 2})
+	if len(errs) != 5 {
+		t.Fatalf("wrong number of errors: %v", errs)
+	}
+	for i, msg := range []string{"string a", "int 1", "", "string d", "extended 2"} {
+		if i == 2 {
+			if !errors.Is(errs[i], ErrWrongType) {
+				t.Errorf("wrong error for unregistered type: %v", errs[i])
+			}
+			continue
+		}
+		if errs[i].(BadField).Err.Error() != msg {
+			t.Errorf("wrong error %d: %v", i, errs[i])
+		}
+	}
+	if errs := vd.Check(X{}); len(errs) != 1 || !errors.Is(errs[0], ErrWrongType) || !strings.Contains(errs[0].Error(), "X.C:") {
+		t.Errorf("wrong check errors: %v", errs)
+	}
+	if errs := base.Validate(X{A: "a"}); !errors.Is(errs[0], ErrWrongType) {
+		t.Errorf("registering in the overlay changed the base: %v", errs)
+	}
+}
+
+func TestV_RegisterTyped(t *testing.T) {
+	type X struct {
+		A int    `validate:"odd"`
+		B string `validate:"odd"`
+		C *int   `validate:"odd"`
+	}
+
+	vd := make(V)
+	vd.RegisterTyped("odd", reflect.Int, func(i interface{}) error {
+		if i.(int)%2 == 0 {
+			return errors.New("should be odd")
+		}
+		return nil
+	})
+
+	two := 2
+	errs := vd.Validate(X{1, "1", &two})
+	if len(errs) != 2 {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+	if err := errs[0].(BadField).Err; !errors.Is(err, ErrWrongType) || err.Error() != `validator "odd" does not accept string` {
+		t.Errorf("wrong error for a mismatched kind: %v", err)
+	}
+	if bf := errs[1].(BadField); bf.Field != "C" || bf.Err.Error() != "should be odd" {
+		t.Errorf("wrong error for a pointer: %v", bf)
+	}
+
+	errs = vd.Check(X{})
+	if len(errs) != 1 || !errors.Is(errs[0], ErrWrongType) || errs[0].Error() != `validate.X.B: validator "odd" does not accept string` {
+		t.Fatalf("wrong errors from Check: %v", errs)
+	}
+	if cov := (&Coverage{}).Validators(vd); len(cov) != 1 {
+		t.Errorf("wrong validators for coverage: %v", cov)
+	}
+}
+
+func TestRegisterFor_Check(t *testing.T) {
+	type X struct {
+		A string    `validate:"nz"`
+		B time.Time `validate:"nz"`
+		C int       `validate:"nz"`
+		D float64   `validate:"kind"`
+		E int       `validate:"kind"`
+		F bool      `validate:"any"`
+	}
+
+	vd := V{"any": nonzero}
+	Register(vd, "nz", func(s string) error { return nil })
+	RegisterFor(vd, "nz", func(t time.Time) error { return nil })
+	vd.RegisterTyped("kind", reflect.Float64, func(interface{}) error { return nil })
+	RegisterFor(vd, "kind", func(n int) error { return nil })
+	RegisterFor(vd, "any", func(n int) error { return nil })
+
+	errs := vd.Check(X{})
+	if len(errs) != 1 || !errors.Is(errs[0], ErrWrongType) || !strings.Contains(errs[0].Error(), "X.C:") {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+
+	fresh := make(V)
+	RegisterFor(fresh, "nz", func(s string) error { return nil })
+	wrong := 0
+	for _, err := range fresh.Check(X{}) {
+		if errors.Is(err, ErrWrongType) {
+			wrong++
+		}
+	}
+	if wrong != 2 {
+		t.Fatalf("%d fields of the wrong type for a fresh validator, wanted 2", wrong)
+	}
+}
@@ -0,0 +1,41 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Types returns a validator for interface fields that accepts only values
+// whose dynamic type is the type of one of samples, or nil. Combined with
+// "struct", it validates a discriminated union: the validator rejects
+// unexpected types, and "struct" applies the rules of the type it holds.
+//
+//	type Shape interface{ Area() float64 }
+//
+//	type Drawing struct {
+//		S Shape `validate:"shape,struct"`
+//	}
+//
+//	vd["shape"] = validate.Types(Circle{}, &Polygon{})
+//
+// Types are matched exactly, so a pointer type and the type it points to
+// must be listed separately if both are expected.
+func Types(samples ...interface{}) func(interface{}) error {
+	types := make(map[reflect.Type]bool, len(samples))
+	names := make([]string, len(samples))
+	for i, s := range samples {
+		t := reflect.TypeOf(s)
+		types[t] = true
+		names[i] = t.String()
+	}
+	expected := strings.Join(names, ", ")
+
+	return func(i interface{}) error {
+		if i == nil || types[reflect.TypeOf(i)] {
+			return nil
+		}
+		return errorf(ErrWrongType, "unexpected type %T, expected one of %s", i, expected)
+	}
+}
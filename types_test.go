@@ -0,0 +1,47 @@
+package validate
+
+import (
+	"fmt"
+	"testing"
+)
+
+type circle struct {
+	R int `validate:"positive"`
+}
+
+type square struct {
+	S int `validate:"positive"`
+}
+
+func TestTypes(t *testing.T) {
+	type X struct {
+		Shape interface{} `validate:"shape,struct"`
+	}
+
+	vd := make(V)
+	vd["shape"] = Types(circle{}, &square{})
+	vd["positive"] = func(i interface{}) error {
+		if i.(int) <= 0 {
+			return fmt.Errorf("not positive")
+		}
+		return nil
+	}
+
+	tests := []struct {
+		shape interface{}
+		errs  string
+	}{
+		{nil, "[]"},
+		{circle{1}, "[]"},
+		{circle{0}, "[field Shape.R is invalid: not positive]"},
+		{&square{-1}, "[field Shape.S is invalid: not positive]"},
+		{"circle", "[field Shape is invalid: unexpected type string, expected one of validate.circle, *validate.square]"},
+	}
+
+	for _, test := range tests {
+		errs := vd.Validate(X{test.shape})
+		if fmt.Sprint(errs) != test.errs {
+			t.Fatalf("wrong errors for %#v: %v", test.shape, errs)
+		}
+	}
+}
@@ -14,6 +14,9 @@ For example:
 	}
 
 As shown, multiple validators can be named by separating each with a comma.
+Validators separated by "|" instead are alternatives, of which only one
+need pass, so that `validate:"hexcolor|rgbcolor"` accepts either kind of
+color. When every alternative fails, their errors are reported together.
 
 The validators are defined in a map like so:
 
@@ -28,11 +31,121 @@ The validators are defined in a map like so:
 
 Validate passes the values of the tagged fields to these functions,
 which should return an error when they decide a value is invalid.
+Programs that share validators across packages can instead register
+them with Default, and use the package-level Validate.
 
 There is a reserved tag, "struct",
 which can be used to automatically validate
-the fields of a named or embedded struct field.
+the fields of a named or embedded struct field,
+or of the struct held by an interface field.
 "struct" may be combined with user-defined validators.
+Validators for whole structs, which check invariants spanning their
+fields, are added with RegisterStruct, or written as methods of types
+implementing Validatable.
+A struct reached again through a pointer while it is being validated,
+as in a cyclic list, is not validated again.
+
+The reserved tag "method" calls a method of a field's value,
+which must take no arguments and return an error.
+It is named after an equals sign, so that with
+
+	type Range struct{ Start, End int }
+
+	func (r Range) Check() error {
+		…
+	}
+
+	type Y struct {
+		Range `validate:"method=Check"`
+	}
+
+the error returned by Check is reported for the field Range.
+
+Another reserved tag, "sensitive", marks a field whose value must not
+appear in errors, such as a password. The errors reported for such a field
+have no Value, and their messages are replaced with one that names only the
+failed rule. The validator's original error, which may quote the value, is
+dropped, even when the validator panicked; errors.Is still reports which of
+this package's errors, such as ErrRequired, it wrapped.
+
+The reserved tag "msg" replaces the messages of the errors reported when
+a field fails its rules, for text meant to be shown to users:
+
+	type Profile struct {
+		Name string `validate:"long,msg=name must be at least 3 characters"`
+		Bio  string `validate:"short,msg:keep it brief, please"`
+	}
+
+Written after a colon, the message may contain commas. The validators'
+original errors can still be reached with errors.Unwrap, and errors in
+the rules themselves, such as undefined validators, are not replaced.
+
+The reserved tag "each" applies rules to the elements of a slice or array.
+The rules following it in the tag apply to each element, so that
+
+	type Z struct {
+		Names []string `validate:"nonempty,each,long"`
+		Ys    []Y      `validate:"each,struct"`
+	}
+
+requires at least one name and checks that every name is long.
+To apply some rules to the elements and others to the slice itself,
+name the element rules after an equals sign, as in "each=long,nonempty",
+or after a colon, as in "each:long,proper", which takes the rest of the tag.
+Invalid elements are reported with their indexes, as in "Names[3]".
+
+The reserved tags "keys" and "values" apply rules to the keys and values
+of a map in the same way. Invalid entries are reported with their keys,
+as in "Headers[Content-Type]".
+
+The reserved tag "required" reports a field holding the zero value of
+its type, such as an empty string, a nil pointer or slice, or an Optional
+holding no value.
+
+The reserved tag "present" is weaker: it reports only a field that is
+absent, as fields are when they are missing from a decoded document:
+a nil pointer, interface, slice, or map, or an Optional holding no value.
+Fields of other kinds, which are never absent, may hold zero values.
+
+The conditional forms of "required" require a field only when others
+are set or not:
+"required_if=Type card" requires it when the field Type is "card", or
+another of the values following the field's name; "required_with=A B"
+requires it when either A or B is set, and "required_without=A B" when
+either is not set.
+
+The reserved tag "omitempty" skips the rules following it
+when the field holds the zero value of its type, for optional fields:
+
+	type W struct {
+		Nickname string `validate:"omitempty,long"`
+	}
+
+The reserved tag "on" scopes the rules following it to groups, named
+after an equals sign and separated by spaces, so that one struct can be
+validated differently in different situations:
+
+	type User struct {
+		ID   string `validate:"on=update patch,required"`
+		Name string `validate:"on=create,required,on,long"`
+	}
+
+The rules scoped to groups apply only when one of the groups is chosen
+by the Groups option, or by ValidateGroup. An "on" naming no groups
+ends the scope, so that Name is required on creation and always long.
+
+Reserved rules cannot be replaced: validators and aliases cannot be
+added under their names.
+
+Validators are passed the values that pointer fields point to, rather than
+the pointers. By default, a nil pointer's validators are skipped, so that
+optional fields can be pointers; the NilPointers option changes this.
+
+A field tagged "-" is never validated, even if rules are given for it
+by the Rules option.
+
+Fields holding wrappers such as Optional[T] are validated as the values
+they hold, if their types implement Optional.
 
 Reflection is used to access the tags and fields,
 so the usual caveats and limitations apply.
@@ -40,25 +153,105 @@ so the usual caveats and limitations apply.
 package validate
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // V is a map of tag names to validators.
+//
+// A name ending in "=" holds an extended validator, which is passed a Field
+// describing the field rather than the field's value. Extended validators
+// are added with RegisterField and are named in tags without the "=".
+//
+// The aliases added with Alias, the values accepted by validators added
+// with Register or RegisterTyped, the validators for structs added with
+// RegisterStruct, and the V overlaid by one made with WithOverlay are
+// held in the map under the key ",meta", which no tag can name. Copying
+// the map's entries copies them too, and a change to one copy is not seen
+// by the others. Code ranging over a V for its validators should skip
+// that key.
+//
+// The reserved rules, such as "required", are always interpreted by
+// Validate itself, so a validator of the same name would never be called.
+// The methods adding validators panic if given such a name, Alias
+// reports it as an error, and Check reports validators stored under
+// one directly.
 type V map[string]func(interface{}) error
 
+// WithOverlay returns a V that looks up rules in overlay first, falling
+// through to v for any rule overlay does not define, in any form.
+// This allows, for example, a tenant to replace a few shared validators
+// while inheriting the rest:
+//
+//	tv := base.WithOverlay(validate.V{"password": strictPassword})
+//
+// The result holds a copy of overlay, and only refers to v, so it is
+// cheap to make for each validation. Neither v nor overlay is modified;
+// later changes to v are seen through the result, and those to overlay
+// are not. Rules added to the result are added to its copy of overlay.
+// Indexing the result finds only the validators of overlay; Lookup finds
+// those of v too.
+func (v V) WithOverlay(overlay V) V {
+	o := overlay.flatten()
+	if v != nil {
+		o.editMeta().base = v
+	}
+	return o
+}
+
+// Lookup returns the validator for the rule name, as Validate finds it,
+// and whether it is an extended validator, which is passed a Field. It
+// looks through the Vs that v overlays, and returns v's fallback for a
+// rule v does not define. It returns nil if there is no validator.
+func (v V) Lookup(name string) (func(interface{}) error, bool) {
+	w := walker{v: v}
+	return w.lookup(name)
+}
+
 // BadField is an error type containing a field name and associated error.
 // This is the type returned from Validate.
 type BadField struct {
 	Field string
 	Err   error
+
+	// Rule is the name of the rule that failed,
+	// or "" if the field's tag could not be parsed
+	// or the error was reported by a struct's validator,
+	// and Params are the parameters given to it in the tag, if any.
+	Rule   string
+	Params []string
+
+	// Value is the value of the field, through any pointers,
+	// or nil if the field is sensitive.
+	Value interface{}
+
+	// Path holds the names along the path to the field, which Field
+	// joins into one string, so that callers can map failures back to
+	// their sources without parsing Field. It starts with the Root, if
+	// any. The elements of slices, arrays, and maps appear as their
+	// indexes or keys in brackets, such as "[3]" or "[Content-Type]".
+	Path Path
 }
 
 func (b BadField) Error() string {
+	if b.Field == "" {
+		return fmt.Sprintf("struct is invalid: %v", b.Err)
+	}
 	return fmt.Sprintf("field %s is invalid: %v", b.Field, b.Err)
 }
 
+// Unwrap returns b.Err, so that errors.Is and errors.As can examine
+// the reason the field is invalid.
+func (b BadField) Unwrap() error {
+	return b.Err
+}
+
 // Validate accepts a struct (or a pointer) and returns a list of errors for all
 // fields that are invalid. If all fields are valid, or s is not a struct type,
 // Validate returns nil.
@@ -80,69 +273,891 @@ func (v V) Validate(s interface{}) []error {
 //
 // The returned BadField will contain "height" instead of "Y" in Field.
 //
-// When nameTag == "", ValidateAndTag behaves identically to Validate.
-func (v V) ValidateAndTag(s interface{}, nameTag string) []error {
-	return v.validateAndTagPrefix(s, nameTag, "")
+// Several tags may be given, in order of preference, for structs whose
+// fields are not tagged consistently:
+//
+//	errs := v.ValidateAndTag(x, "json", "yaml")
+//
+// Names are extracted from the tags' values with TagName,
+// so options such as ",omitempty" are not reported.
+// The field name is used for fields that have none of the tags,
+// or whose tag value names no field, such as "-".
+// When no tags are given, or only "", ValidateAndTag behaves identically
+// to Validate.
+func (v V) ValidateAndTag(s interface{}, nameTags ...string) []error {
+	return v.ValidateOpts(s, NameTags(nameTags...))
 }
 
-func (v V) validateAndTagPrefix(s interface{}, nameTag string, prefix string) []error {
-	val := reflect.ValueOf(s)
+// ValidateGroup behaves like Validate, but also applies the rules scoped
+// to any of groups, as described in the package documentation.
+func (v V) ValidateGroup(s interface{}, groups ...string) []error {
+	return v.ValidateOpts(s, Groups(groups...))
+}
 
-	if val.Kind() == reflect.Ptr {
+// ValidateMasked behaves like Validate, but only validates the fields named
+// by mask, in the manner of a google.protobuf.FieldMask. Each path in mask is
+// a field name as it would appear in a BadField, such as "A" or "X.A".
+// A path naming a struct field selects every field within it,
+// and the fields of a struct are reached through its "struct" tag as usual.
+//
+// A nil mask validates every field, like Validate.
+// An empty, non-nil mask validates nothing.
+func (v V) ValidateMasked(s interface{}, mask []string) []error {
+	w := walker{v: v}
+	if mask != nil {
+		w.mask = make(map[string]bool, len(mask))
+		for _, p := range mask {
+			w.mask[p] = true
+		}
+	}
+	return w.validate(reflect.ValueOf(s), nil)
+}
+
+// ErrUndefinedValidator is wrapped by the error reported for a rule that
+// names no validator, which usually means a V was not set up as its tags
+// expect.
+var ErrUndefinedValidator = errors.New("undefined validator")
+
+// ErrValidatorPanicked is wrapped by the error reported for a rule whose
+// validator panicked, which describes the panic. A panicking validator is
+// reported like a rule naming no validator, so that one bad validator
+// cannot bring down a program validating values; the NoRecover option
+// lets the panic propagate instead.
+var ErrValidatorPanicked = errors.New("validator panicked")
+
+// ErrMaxDepth is wrapped by the error reported for a struct nested more
+// deeply than the MaxDepth option allows.
+var ErrMaxDepth = errors.New("exceeds maximum depth")
+
+// ErrValidationCanceled is wrapped by the error ValidateContext reports
+// when its context is done before validation finishes.
+var ErrValidationCanceled = errors.New("validation canceled")
+
+// ValidateContext behaves like Validate, but checks ctx between fields and
+// stops early once ctx is done. The errors found up to that point are
+// returned, followed by an error wrapping ErrValidationCanceled and
+// describing ctx.Err().
+func (v V) ValidateContext(ctx context.Context, s interface{}) []error {
+	w := walker{v: v, ctx: ctx}
+	return w.validate(reflect.ValueOf(s), nil)
+}
+
+// walker holds the settings for a single call to validate.
+type walker struct {
+	v V
+
+	// defaults holds the validators for rules v does not define.
+	defaults V
+
+	// groups are the groups whose rules apply, as chosen by Groups.
+	groups []string
+
+	// failFast makes the walker done after its first failure.
+	failFast bool
+
+	// deep makes the walker validate nested structs without "struct" rules.
+	deep bool
+
+	// visiting holds the structs being validated that were reached
+	// through pointers, so that cycles are followed only once.
+	visiting map[visit]bool
+
+	// depth is the number of structs being validated within the first,
+	// which cannot exceed maxDepth, if it is positive.
+	depth, maxDepth int
+
+	// nils decides how validators treat nil pointers.
+	nils NilPolicy
+
+	// schema, if not nil, holds the fields of struct types,
+	// so that their tags need not be parsed again.
+	schema map[reflect.Type][]fieldInfo
+
+	// nameTags are the tags holding field names, in order of preference,
+	// and tagName extracts a name from their values.
+	nameTags []string
+	tagName  func(string) string
+
+	// nameFunc, if not nil, names fields in preference to nameTags.
+	nameFunc func(reflect.StructField) string
+
+	// tagKey is the key of the tags holding rules, if not "validate",
+	// and playground says they are written for go-playground/validator.
+	tagKey     string
+	playground bool
+
+	// ctx, if not nil, is checked before each field.
+	// Once it is done, so is the walker.
+	ctx  context.Context
+	done bool
+
+	// yield, if not nil, receives failures instead of their being
+	// collected. Once it returns false, the walker is done.
+	yield func(BadField) bool
+
+	// root is prepended to every path, and format joins paths
+	// into the names reported in errors.
+	root   string
+	format func([]string) string
+
+	// rules holds rules for fields, by path, in addition to their tags.
+	rules map[string]string
+
+	// mask holds the paths selected by ValidateMasked.
+	// If it is nil, every field is selected.
+	mask map[string]bool
+
+	// audit, if not nil, receives a record of each failure.
+	audit AuditSink
+
+	// noRecover lets panics in validators propagate.
+	noRecover bool
+
+	// noValidatable skips the methods of Validatable values.
+	noValidatable bool
+
+	// workers is the number of goroutines validating for ValidateStream.
+	workers int
+
+	// parallel is the number of goroutines validating a struct's fields,
+	// if more than one.
+	parallel int
+
+	// When planning, validators are recorded in plan instead of called.
+	planning bool
+	plan     []PlannedCheck
+
+	// layerList holds the layers in which rules are looked up, once
+	// found, the first ownLayers of which are those of v.
+	layerList []layer
+	ownLayers int
+}
+
+// selected reports whether the field at path, or one of its ancestors,
+// was selected by the mask.
+func (w *walker) selected(path string) bool {
+	if w.mask == nil {
+		return true
+	}
+	for {
+		if w.mask[path] {
+			return true
+		}
+		i := strings.LastIndexAny(path, ".[")
+		if i < 0 {
+			return false
+		}
+		path = path[:i]
+	}
+}
+
+// leadsTo reports whether the mask selects a field nested within path.
+func (w *walker) leadsTo(path string) bool {
+	if w.selected(path) {
+		return true
+	}
+	for p := range w.mask {
+		if strings.HasPrefix(p, path+".") || strings.HasPrefix(p, path+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// validate validates the fields of the struct in val, which is found at
+// path within the value passed to the walker.
+func (w *walker) validate(val reflect.Value, path []string) []error {
+	if val.Kind() == reflect.Interface {
 		val = val.Elem()
 	}
+	var ptr reflect.Value
+	if val.Kind() == reflect.Ptr {
+		ptr, val = val, val.Elem()
+	}
 
-	t := val.Type()
-	if t == nil || t.Kind() != reflect.Struct {
+	if !val.IsValid() || val.Kind() != reflect.Struct {
 		return nil
 	}
+	t := val.Type()
 
 	var errs []error
 
-	for i := 0; i < t.NumField(); i++ {
-		f := t.Field(i)
-		fv := val.Field(i)
-		if !fv.CanInterface() {
-			continue
+	// A struct already being validated, reached again through a pointer,
+	// has been or will be checked where it was first reached.
+	if ptr.IsValid() {
+		v := visit{ptr.Pointer(), t}
+		if w.visiting[v] {
+			return nil
 		}
-		val := fv.Interface()
-		tag := f.Tag.Get("validate")
-		if tag == "" {
-			continue
+		if w.visiting == nil {
+			w.visiting = make(map[visit]bool)
+		}
+		w.visiting[v] = true
+		defer delete(w.visiting, v)
+	}
+	if w.maxDepth > 0 && len(path) > 0 {
+		if w.depth >= w.maxDepth {
+			return w.fail(errs, t, path, BadField{Err: fmt.Errorf("%w of %d", ErrMaxDepth, w.maxDepth)})
+		}
+		w.depth++
+		defer func() { w.depth-- }()
+	}
+
+	if w.parallel > 1 && !w.planning {
+		errs = w.parallelFields(errs, val, path)
+	} else {
+		for _, fi := range w.fields(t) {
+			if w.done {
+				break
+			}
+			if w.ctx != nil {
+				if err := w.ctx.Err(); err != nil {
+					w.done = true
+					errs = append(errs, fmt.Errorf("%w: %v", ErrValidationCanceled, err))
+					break
+				}
+			}
+
+			errs = w.field(errs, val, fi, path)
+		}
+	}
+
+	if w.mask == nil || len(path) > 0 && w.selected(DotPath(path)) {
+		errs = w.checkSelf(errs, t, val, path, false)
+		errs = w.checkStruct(errs, val, path)
+	}
+	return errs
+}
+
+// field validates the field fi of the struct in val, which is found at
+// path, and appends the errors it finds to errs.
+func (w *walker) field(errs []error, val reflect.Value, fi fieldInfo, path []string) []error {
+	t := val.Type()
+	f := fi.field
+	fv := val.Field(fi.index)
+	if !fv.CanInterface() {
+		return errs
+	}
+	fpath := append(path[:len(path):len(path)], w.fieldName(f))
+	mpath := DotPath(fpath)
+
+	rules, err := fi.rules, fi.err
+	if more := w.rules[mpath]; more != "" {
+		tag := fi.tag
+		if tag != "" {
+			tag += ","
+		}
+		rules, err = ParseTag(tag + more)
+	}
+	if err == nil {
+		rules, err = w.expand(rules, nil)
+	}
+	if len(rules) == 0 && err == nil && !w.deep {
+		return errs
+	}
+
+	if !w.leadsTo(mpath) {
+		return errs
+	}
+	selected := w.selected(mpath)
+
+	if err != nil {
+		errs = w.fail(errs, t, fpath, BadField{Err: err})
+		return errs
+	}
+
+	msg := ""
+	for _, r := range rules {
+		if r.Name == "msg" {
+			msg = r.Param
+		}
+	}
+
+	// An Optional holding no value is only checked for "required",
+	// its conditional forms, and "present".
+	uv := unwrap(fv)
+	if !uv.IsValid() {
+		for _, r := range rules {
+			if !requiredRule(r.Name) || !selected || w.planning {
+				continue
+			}
+			if err := required(val, r); err != nil {
+				if msg != "" {
+					err = message{msg, err}
+				}
+				errs = w.fail(errs, t, fpath, BadField{Err: err, Rule: r.Name, Params: r.Params()})
+				break
+			}
+		}
+		return errs
+	}
+	fv = uv
+
+	sensitive := false
+	for _, r := range rules {
+		sensitive = sensitive || r.Name == "sensitive"
+	}
+
+	// The rules following "each", "keys", or "values" apply to the
+	// elements of the field, which are queued to be checked after
+	// the field itself.
+	queue := []element{{fv, fpath, rules}}
+	for len(queue) > 0 && !w.done {
+		e := queue[0]
+		queue = queue[1:]
+		fv, fpath, rules := e.value, e.path, e.rules
+		name := w.pathName(fpath)
+
+		// Validators are passed the values of non-nil pointers.
+		cv := fv
+		for cv.Kind() == reflect.Ptr && !cv.IsNil() {
+			cv = cv.Elem()
+		}
+		isNil, nilReported := cv.Kind() == reflect.Ptr, false
+		value := cv.Interface()
+		if sensitive {
+			value = nil
 		}
-		vts := strings.Split(tag, ",")
 
-		for _, vt := range vts {
-			name := f.Name
-			if nameTag != "" {
-				name = f.Tag.Get(nameTag)
+		active := true
+		for k, r := range rules {
+			if w.done {
+				break
 			}
+			vt := r.Name
 
-			if len(prefix) > 0 {
-				name = prefix + "." + name
+			// Gather the alternatives to r, of which one must pass.
+			// Reserved rules have no alternatives.
+			if r.Or {
+				continue
+			}
+			n := 1
+			for k+n < len(rules) && rules[k+n].Or {
+				n++
+			}
+			alts := rules[k : k+n]
+			if n > 1 {
+				reservedAlt := ""
+				for _, a := range alts {
+					if Reserved(a.Name) {
+						reservedAlt = a.Name
+					}
+				}
+				if reservedAlt != "" {
+					errs = w.fail(errs, t, fpath, BadField{
+						Err:   fmt.Errorf("reserved rule %q cannot have alternatives", reservedAlt),
+						Rule:  vt,
+						Value: value,
+					})
+					continue
+				}
 			}
 
+			if vt == "sensitive" || vt == "msg" {
+				continue
+			}
+			if vt == "on" {
+				active = w.inGroups(r.Params())
+				continue
+			}
+			if !active {
+				continue
+			}
+			if vt == "omitempty" {
+				if fv.IsZero() && !w.planning {
+					break
+				}
+				continue
+			}
 			if vt == "struct" {
-				errs2 := v.validateAndTagPrefix(val, nameTag, name)
+				errs2 := w.validate(fv, fpath)
 				if len(errs2) > 0 {
 					errs = append(errs, errs2...)
 				}
 				continue
 			}
+			if vt == "each" || vt == "keys" || vt == "values" {
+				erules, perr := rules[k+1:], error(nil)
+				if r.Param != "" {
+					erules, perr = ParseTag(r.Param)
+					if perr == nil {
+						erules, perr = w.expand(erules, nil)
+					}
+				}
+				fail := func(err error) {
+					errs = w.fail(errs, t, fpath, BadField{Err: err, Rule: vt, Params: r.Params(), Value: value})
+				}
+				kind := cv.Kind()
+				switch {
+				case perr != nil:
+					fail(perr)
+				case isNil:
+				case vt == "each" && kind != reflect.Slice && kind != reflect.Array:
+					fail(errorf(ErrWrongType, "cannot check each element of %v", cv.Type()))
+				case vt != "each" && kind != reflect.Map:
+					fail(errorf(ErrWrongType, "cannot check the %s of %v", vt, cv.Type()))
+				case vt == "each":
+					for j := 0; j < cv.Len(); j++ {
+						if ev := unwrap(cv.Index(j)); ev.IsValid() {
+							epath := append(fpath[:len(fpath):len(fpath)], "["+strconv.Itoa(j)+"]")
+							queue = append(queue, element{ev, epath, erules})
+						}
+					}
+				default:
+					keys, _ := sortedKeys(cv.Interface())
+					for _, key := range keys {
+						ev := key
+						if vt == "values" {
+							ev = cv.MapIndex(key)
+						}
+						if ev = unwrap(ev); ev.IsValid() {
+							epath := append(fpath[:len(fpath):len(fpath)], "["+fmt.Sprint(key)+"]")
+							queue = append(queue, element{ev, epath, erules})
+						}
+					}
+				}
+				if r.Param == "" {
+					break
+				}
+				continue
+			}
+			if !selected {
+				continue
+			}
+
+			tg := target{name: name, value: fv, checked: cv, parent: val, isNil: isNil}
+			var failed []error
+			passed := false
+			for _, a := range alts {
+				out, err := w.call(a, tg)
+				switch out {
+				case skipped:
+					passed = true
+					continue
+				case nilled:
+					if w.nils == NilInvalid && !nilReported {
+						nilReported = true
+						errs = w.fail(errs, t, fpath, BadField{Err: err, Rule: a.Name, Params: a.Params()})
+					}
+					passed = true
+					continue
+				case misapplied:
+					if sensitive {
+						err = redact(a.Name, err)
+					}
+					errs = w.fail(errs, t, fpath, BadField{Err: err, Rule: a.Name, Params: a.Params(), Value: value})
+					continue
+				}
 
-			vf := v[vt]
-			if vf == nil {
-				errs = append(errs, BadField{
-					Field: name,
-					Err:   fmt.Errorf("undefined validator: %q", vt),
-				})
+				if c := coverage.Load(); c != nil {
+					c.record(FieldRule{t, f.Name, a.Name}, err != nil)
+				}
+				if err == nil {
+					passed = true
+					break
+				}
+				if sensitive {
+					err = redact(a.Name, err)
+				}
+				failed = append(failed, err)
+			}
+			if passed || len(failed) == 0 || w.done {
 				continue
 			}
-			if err := vf(val); err != nil {
-				errs = append(errs, BadField{name, err})
+			err := failed[0]
+			if n > 1 {
+				err = alternatives(failed)
+			}
+			if msg != "" {
+				err = message{msg, err}
 			}
+			if n == 1 {
+				errs = w.fail(errs, t, fpath, BadField{Err: err, Rule: vt, Params: r.Params(), Value: value})
+				continue
+			}
+			names := make([]string, n)
+			for j, a := range alts {
+				names[j] = a.Name
+			}
+			errs = w.fail(errs, t, fpath, BadField{
+				Err:   err,
+				Rule:  strings.Join(names, "|"),
+				Value: value,
+			})
 		}
 	}
 
+	// The values of fields not validated as structs may still
+	// validate themselves.
+	omitted := namesRule(rules, "omitempty") && fv.IsZero()
+	sv := fv
+	for (sv.Kind() == reflect.Ptr || sv.Kind() == reflect.Interface) && !sv.IsNil() {
+		sv = sv.Elem()
+	}
+	if len(rules) > 0 && selected && !w.done && !omitted && !namesRule(rules, "struct") &&
+		!(w.deep && sv.Kind() == reflect.Struct) && sv.Kind() != reflect.Ptr && sv.Kind() != reflect.Interface {
+		errs = w.checkSelf(errs, t, sv, fpath, sensitive)
+	}
+
+	if w.deep && !w.done && !namesRule(rules, "struct") && !omitted {
+		errs = append(errs, w.descend(fv, fpath)...)
+	}
 	return errs
 }
+
+// A visit identifies a struct reached through a pointer.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// descend validates the structs within val, at path, for the Deep option:
+// val itself, or the elements of a slice, array, or map.
+func (w *walker) descend(val reflect.Value, path []string) []error {
+	for (val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface) && !val.IsNil() {
+		val = val.Elem()
+	}
+
+	var errs []error
+	switch val.Kind() {
+	case reflect.Struct:
+		return w.validate(val, path)
+	case reflect.Slice, reflect.Array:
+		for j := 0; j < val.Len() && !w.done; j++ {
+			if ev := unwrap(val.Index(j)); ev.IsValid() {
+				epath := append(path[:len(path):len(path)], "["+strconv.Itoa(j)+"]")
+				errs = append(errs, w.descend(ev, epath)...)
+			}
+		}
+	case reflect.Map:
+		keys, _ := sortedKeys(val.Interface())
+		for _, key := range keys {
+			if w.done {
+				break
+			}
+			if ev := unwrap(val.MapIndex(key)); ev.IsValid() {
+				epath := append(path[:len(path):len(path)], "["+fmt.Sprint(key)+"]")
+				errs = append(errs, w.descend(ev, epath)...)
+			}
+		}
+	}
+	return errs
+}
+
+// namesRule reports whether rules, or the rules given as parameters to
+// "each", "keys", or "values", name the rule called name.
+func namesRule(rules []Rule, name string) bool {
+	for _, r := range rules {
+		if r.Name == name {
+			return true
+		}
+		if r.Name == "each" || r.Name == "keys" || r.Name == "values" {
+			if pr, err := ParseTag(r.Param); err == nil && namesRule(pr, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// A target is a value to which rules are applied.
+type target struct {
+	name    string        // the name reported in errors
+	value   reflect.Value // the value of the field or element
+	checked reflect.Value // value, through any non-nil pointers
+	parent  reflect.Value // the struct containing the field
+	isNil   bool          // whether checked is a nil pointer
+}
+
+// An outcome says what became of applying a rule to a target.
+type outcome int
+
+const (
+	checked    outcome = iota // the rule was checked, and failed if there is an error
+	skipped                   // nothing was checked
+	misapplied                // the rule cannot be applied, as the error says
+	nilled                    // the target is a nil pointer, as the error says
+)
+
+// call applies the rule r, which is not one handled by the rules loop
+// itself, to tg.
+func (w *walker) call(r Rule, tg target) (out outcome, err error) {
+	if !w.noRecover {
+		defer func() {
+			if p := recover(); p != nil {
+				out, err = misapplied, fmt.Errorf("%w: %q: %v", ErrValidatorPanicked, r.Name, p)
+			}
+		}()
+	}
+	vf, extended := w.lookup(r.Name)
+	switch {
+	case r.Name == "method":
+		if tg.value.Kind() == reflect.Ptr && tg.value.IsNil() {
+			return skipped, nil
+		}
+		m, ok := method(tg.value, r.Param)
+		if w.planning {
+			w.plan = append(w.plan, PlannedCheck{Field: tg.name, Rule: r, Defined: ok})
+			return skipped, nil
+		}
+		if !ok {
+			return checked, fmt.Errorf("%v has no method %s() error", tg.value.Type(), r.Param)
+		}
+		return checked, m()
+	case requiredRule(r.Name):
+		if w.planning {
+			w.plan = append(w.plan, PlannedCheck{Field: tg.name, Rule: r, Defined: true})
+			return skipped, nil
+		}
+		if r.Name == "present" {
+			if absent(tg.value) {
+				return checked, errRequired
+			}
+			return checked, nil
+		}
+		if tg.value.IsZero() {
+			return checked, required(tg.parent, r)
+		}
+		return checked, nil
+	case w.planning:
+		w.plan = append(w.plan, PlannedCheck{Field: tg.name, Rule: r, Defined: vf != nil})
+		return skipped, nil
+	case vf == nil:
+		return misapplied, fmt.Errorf("%w: %q", ErrUndefinedValidator, r.Name)
+	case tg.isNil && w.nils != NilPass:
+		return nilled, errorf(ErrRequired, "is nil")
+	case extended:
+		return checked, vf(Field{Name: tg.name, Value: tg.checked, Parent: tg.parent, Rule: r, Context: w.context()})
+	case r.Param != "":
+		return misapplied, fmt.Errorf("validator %q does not take a parameter", r.Name)
+	}
+	return checked, vf(tg.checked.Interface())
+}
+
+// alternatives is the error reported when every alternative rule fails.
+type alternatives []error
+
+func (e alternatives) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, ", or ")
+}
+
+func (e alternatives) Unwrap() []error {
+	return e
+}
+
+// inGroups reports whether rules scoped to groups apply to this validation.
+// Rules scoped to no groups always apply.
+func (w *walker) inGroups(groups []string) bool {
+	if len(groups) == 0 {
+		return true
+	}
+	for _, g := range groups {
+		for _, wg := range w.groups {
+			if g == wg {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// structType returns the struct type whose fields "struct" rules reach
+// in fields of type t, following pointers and, for rules applied by
+// "each" or "values", the elements of slices, arrays, and maps.
+// It returns nil if there is none.
+func structType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
+
+// fields returns what the walker needs to know about the fields of the
+// struct type t, from the schema it was given or from typeCache.
+func (w *walker) fields(t reflect.Type) []fieldInfo {
+	key := w.tagKey
+	if key == "" {
+		key = "validate"
+	}
+	if fs, ok := w.schema[t]; ok && key == "validate" && !w.playground {
+		return fs
+	}
+	tk := typeKey{t, key, w.playground}
+	if fs, ok := typeCache.Load(tk); ok {
+		return fs.([]fieldInfo)
+	}
+	fs := parseType(t, key, w.playground)
+	typeCache.Store(tk, fs)
+	return fs
+}
+
+// typeCache holds the fields of the struct types that have been validated,
+// by type and tag key, so that their tags are parsed only once.
+var typeCache sync.Map
+
+type typeKey struct {
+	t          reflect.Type
+	key        string
+	playground bool
+}
+
+// fieldInfo describes an exported field of a struct type to the walker.
+type fieldInfo struct {
+	field reflect.StructField
+	index int
+	tag   string // the field's tag holding its rules
+	rules []Rule // the rules parsed from tag
+	err   error  // the error from parsing tag, if any
+}
+
+// parseType returns the fields of the struct type t that may be validated,
+// with their rules in the tag key, in the syntax of go-playground/validator
+// if playground is true: those that are exported and not tagged "-".
+func parseType(t reflect.Type, key string, playground bool) []fieldInfo {
+	var fs []fieldInfo
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get(key)
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		parse := ParseTag
+		if playground {
+			parse = func(tag string) ([]Rule, error) {
+				return parsePlayground(tag, f.Type)
+			}
+		}
+		rules, err := parse(tag)
+		fs = append(fs, fieldInfo{f, i, tag, rules, err})
+	}
+	return fs
+}
+
+// An element is a value to be checked against rules,
+// such as a field or an element of one, at path.
+type element struct {
+	value reflect.Value
+	path  []string
+	rules []Rule
+}
+
+// fail appends bf, the failure of the field at path within a struct of
+// type t, to errs.
+func (w *walker) fail(errs []error, t reflect.Type, path []string, bf BadField) []error {
+	bf.Field = w.pathName(path)
+	if w.root != "" {
+		path = append([]string{w.root}, path...)
+	}
+	bf.Path = path
+	var rec AuditRecord
+	if w.audit != nil {
+		rec = AuditRecord{
+			Type:  t,
+			Field: bf.Field,
+			Rule:  bf.Rule,
+			Value: bf.Value,
+			Time:  time.Now(),
+		}
+	}
+	return w.report(errs, rec, bf)
+}
+
+// report appends bf, described by rec for the walker's audit, to errs,
+// or passes it to the walker's yield.
+func (w *walker) report(errs []error, rec AuditRecord, bf BadField) []error {
+	if w.audit != nil {
+		w.audit.Audit(rec)
+	}
+	if c := CatalogFrom(w.ctx); c != nil {
+		bf.Err = c.translate(bf.Err, LocaleFrom(w.ctx))
+	}
+	if w.yield != nil {
+		w.done = !w.yield(bf) || w.failFast
+		return errs
+	}
+	w.done = w.failFast
+	return append(errs, bf)
+}
+
+// method returns the method of v with the given name, if it takes no
+// arguments and returns an error. If v is addressable, the methods of its
+// address are included.
+func method(v reflect.Value, name string) (func() error, bool) {
+	m := v.MethodByName(name)
+	if !m.IsValid() && v.CanAddr() {
+		m = v.Addr().MethodByName(name)
+	}
+	if !m.IsValid() || !m.CanInterface() {
+		return nil, false
+	}
+	f, ok := m.Interface().(func() error)
+	return f, ok
+}
+
+// fieldName returns the name of f as reported in errors.
+func (w *walker) fieldName(f reflect.StructField) string {
+	if w.nameFunc != nil {
+		if n := w.nameFunc(f); n != "" {
+			return n
+		}
+	}
+	for _, tag := range w.nameTags {
+		if tag == "" {
+			continue
+		}
+		parse := w.tagName
+		if parse == nil {
+			parse = TagName
+		}
+		if n := parse(f.Tag.Get(tag)); n != "" {
+			return n
+		}
+	}
+	return f.Name
+}
+
+// pathName returns the name reported in errors for the field at path.
+func (w *walker) pathName(path []string) string {
+	if w.root != "" {
+		path = append([]string{w.root}, path...)
+	}
+	if w.format == nil {
+		return DotPath(path)
+	}
+	return w.format(path)
+}
+
+// context returns the walker's context, or context.Background if it has none.
+func (w *walker) context() context.Context {
+	if w.ctx == nil {
+		return context.Background()
+	}
+	return w.ctx
+}
+
+// lookup returns the validator for the rule name, and whether it is
+// an extended validator, which takes a Field rather than a value.
+func (w *walker) lookup(name string) (func(interface{}) error, bool) {
+	ls, own := w.layers()
+	for _, l := range ls {
+		if vf := l.v[name]; vf != nil {
+			return vf, false
+		}
+		if vf := l.v[name+"="]; vf != nil {
+			return vf, true
+		}
+	}
+	for _, l := range ls[:own] {
+		if vf := l.v["="]; vf != nil {
+			return vf, true
+		}
+	}
+	return nil, false
+}
@@ -1,7 +1,10 @@
 package validate
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -241,3 +244,566 @@ func TestV_ValidateAndTag(t *testing.T) {
 		t.Fatalf("wrong field name in BadField: %q", bf.Field)
 	}
 }
+
+func TestV_ValidateMasked(t *testing.T) {
+	type X struct {
+		A int `validate:"odd"`
+		B int `validate:"odd"`
+	}
+	type Y struct {
+		X X   `validate:"struct"`
+		C int `validate:"odd"`
+	}
+
+	vd := make(V)
+	vd["odd"] = func(i interface{}) error {
+		n := i.(int)
+		if n&1 == 0 {
+			return fmt.Errorf("%d is not odd", n)
+		}
+		return nil
+	}
+
+	tests := []struct {
+		mask   []string
+		fields []string
+	}{
+		{nil, []string{"X.A", "X.B", "C"}},
+		{[]string{}, nil},
+		{[]string{"C"}, []string{"C"}},
+		{[]string{"X.B"}, []string{"X.B"}},
+		{[]string{"X", "C"}, []string{"X.A", "X.B", "C"}},
+		{[]string{"X.C"}, nil},
+	}
+
+	for _, test := range tests {
+		var fields []string
+		for _, err := range vd.ValidateMasked(Y{}, test.mask) {
+			fields = append(fields, err.(BadField).Field)
+		}
+		if fmt.Sprint(fields) != fmt.Sprint(test.fields) {
+			t.Fatalf("wrong fields validated for mask %q: %q", test.mask, fields)
+		}
+	}
+}
+
+func TestV_WithOverlay(t *testing.T) {
+	type X struct {
+		A string `validate:"short"`
+		B string `validate:"nonempty"`
+	}
+
+	base := make(V)
+	base["short"] = func(i interface{}) error {
+		if len(i.(string)) > 10 {
+			return fmt.Errorf("longer than 10")
+		}
+		return nil
+	}
+	base["nonempty"] = func(i interface{}) error {
+		if i.(string) == "" {
+			return fmt.Errorf("empty")
+		}
+		return nil
+	}
+
+	tenant := base.WithOverlay(V{
+		"short": func(i interface{}) error {
+			if len(i.(string)) > 3 {
+				return fmt.Errorf("longer than 3")
+			}
+			return nil
+		},
+	})
+
+	x := X{A: "abcdef"}
+	errs := tenant.Validate(x)
+	if len(errs) != 2 {
+		t.Fatalf("wrong number of errors from overlay: %v", errs)
+	}
+	if errs[0].(BadField).Err.Error() != "longer than 3" {
+		t.Fatalf("overlay validator was not used: %v", errs[0])
+	}
+	if errs[1].(BadField).Field != "B" {
+		t.Fatalf("base validator was not used: %v", errs[1])
+	}
+
+	if errs := base.Validate(x); len(errs) != 1 {
+		t.Fatalf("base was modified by overlay: %v", errs)
+	}
+	if len(tenant) != 2 { // its validator and its metadata
+		t.Fatalf("overlay copied the base: %d validators", len(tenant))
+	}
+
+	// Later changes to the base are seen through the overlay.
+	base.Alias("word", "nonempty")
+	type Y struct {
+		C string `validate:"word"`
+	}
+	if errs := tenant.Validate(Y{}); len(errs) != 1 || errs[0].(BadField).Err.Error() != "empty" {
+		t.Fatalf("base's alias not seen through the overlay: %v", errs)
+	}
+	if vf, extended := tenant.Lookup("nonempty"); vf == nil || extended {
+		t.Fatal("Lookup did not find the base's validator")
+	}
+
+	// A rule the overlay defines in any form hides the base's.
+	ext := base.WithOverlay(V{})
+	ext.RegisterField("short", func(Field) error { return nil })
+	ext.RegisterField("word", func(Field) error { return nil })
+	type Z struct {
+		A string `validate:"short"`
+		B string `validate:"nonempty"`
+		C string `validate:"word"`
+	}
+	if errs := ext.Validate(Z{A: "abcdefghijkl"}); len(errs) != 1 || errs[0].(BadField).Field != "B" {
+		t.Fatalf("overlay's extended validators did not hide the base's rules: %v", errs)
+	}
+}
+
+func TestV_ValidateContext(t *testing.T) {
+	type X struct {
+		A int `validate:"slow"`
+		B int `validate:"slow"`
+	}
+	type Y struct {
+		X X   `validate:"struct"`
+		C int `validate:"slow"`
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	vd := make(V)
+	vd["slow"] = func(i interface{}) error {
+		calls++
+		cancel()
+		return fmt.Errorf("call %d", calls)
+	}
+
+	errs := vd.ValidateContext(ctx, Y{})
+	if calls != 1 {
+		t.Fatalf("validation continued after cancellation: %d calls", calls)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("wrong number of errors after cancellation: %v", errs)
+	}
+	if !errors.Is(errs[1], ErrValidationCanceled) {
+		t.Fatalf("last error does not report cancellation: %v", errs[1])
+	}
+}
+
+func TestV_ValidateAndTag_fallback(t *testing.T) {
+	type X struct {
+		A int `validate:"odd" json:"a" yaml:"ya"`
+		B int `validate:"odd" yaml:"yb"`
+		C int `validate:"odd" json:"-"`
+		D int `validate:"odd"`
+	}
+
+	vd := make(V)
+	vd["odd"] = func(i interface{}) error {
+		return fmt.Errorf("not odd")
+	}
+
+	var fields []string
+	for _, err := range vd.ValidateAndTag(X{}, "json", "yaml") {
+		fields = append(fields, err.(BadField).Field)
+	}
+	if fmt.Sprint(fields) != "[a yb C D]" {
+		t.Fatalf("wrong field names: %q", fields)
+	}
+}
+
+func TestV_Validate_details(t *testing.T) {
+	type X struct {
+		A int `validate:"nonzero,odd"`
+	}
+
+	vd := make(V)
+	vd["odd"] = func(i interface{}) error {
+		return fmt.Errorf("not odd")
+	}
+
+	errs := vd.Validate(X{A: 2})
+	if len(errs) != 2 {
+		t.Fatalf("wrong number of errors: %v", errs)
+	}
+	for i, rule := range []string{"nonzero", "odd"} {
+		bf := errs[i].(BadField)
+		if bf.Rule != rule || bf.Value != 2 || bf.Params != nil {
+			t.Fatalf("wrong details for %s: %+v", rule, bf)
+		}
+	}
+}
+
+type Span struct {
+	Start, End int
+}
+
+func (r Span) Check() error {
+	if r.End < r.Start {
+		return fmt.Errorf("ends before it starts")
+	}
+	return nil
+}
+
+func (r *Span) PtrCheck() error {
+	return r.Check()
+}
+
+func TestV_Validate_method(t *testing.T) {
+	type X struct {
+		Span `validate:"method=Check"`
+		P    *Span `validate:"method=PtrCheck"`
+		Q    Span  `validate:"method=PtrCheck"`
+		R    Span  `validate:"method=Nope"`
+	}
+
+	vd := make(V)
+	x := X{Span: Span{2, 1}, P: &Span{2, 1}, Q: Span{2, 1}}
+
+	errs := vd.Validate(&x)
+	want := "[field Span is invalid: ends before it starts" +
+		" field P is invalid: ends before it starts" +
+		" field Q is invalid: ends before it starts" +
+		" field R is invalid: validate.Span has no method Nope() error]"
+	if fmt.Sprint(errs) != want {
+		t.Fatalf("wrong errors from methods: %v", errs)
+	}
+	if bf := errs[0].(BadField); bf.Rule != "method" || fmt.Sprint(bf.Params) != "[Check]" {
+		t.Fatalf("wrong rule details: %+v", bf)
+	}
+
+	if errs := vd.Validate(X{}); len(errs) != 2 {
+		t.Fatalf("wrong errors for unaddressable struct and nil pointer: %v", errs)
+	}
+}
+
+func TestV_Validate_param(t *testing.T) {
+	type X struct {
+		A int `validate:"odd=3"`
+	}
+
+	vd := make(V)
+	vd["odd"] = func(i interface{}) error {
+		return nil
+	}
+
+	errs := vd.Validate(X{})
+	if len(errs) != 1 || errs[0].Error() != `field A is invalid: validator "odd" does not take a parameter` {
+		t.Fatalf("wrong errors for a parameter to a plain validator: %v", errs)
+	}
+}
+
+func TestV_Validate_each(t *testing.T) {
+	type Y struct {
+		A int `validate:"odd"`
+	}
+	type X struct {
+		Names []string    `validate:"nonempty,each,long"`
+		Ys    []*Y        `validate:"each,struct"`
+		Pairs [2][]string `validate:"each=nonempty,each:each,long"`
+		N     int         `validate:"each=long"`
+		Empty []string    `validate:"nonempty,each,long"`
+	}
+
+	vd := V{
+		"long": long,
+		"odd": func(i interface{}) error {
+			if i.(int)%2 == 0 {
+				return fmt.Errorf("%d should be odd", i)
+			}
+			return nil
+		},
+		"nonempty": func(i interface{}) error {
+			if reflect.ValueOf(i).Len() == 0 {
+				return fmt.Errorf("should not be empty")
+			}
+			return nil
+		},
+	}
+
+	x := X{
+		Names: []string{"long", "no"},
+		Ys:    []*Y{{1}, nil, {2}},
+		Pairs: [2][]string{{"abc", "x"}, nil},
+	}
+	var fields []string
+	for _, err := range vd.Validate(x) {
+		fields = append(fields, err.(BadField).Field)
+	}
+	want := "[Names[1] Ys[2].A Pairs[1] Pairs[0][1] N Empty]"
+	if fmt.Sprint(fields) != want {
+		t.Fatalf("wrong invalid fields %v, wanted %v", fields, want)
+	}
+
+	errs := vd.ValidateOpts(x, PathFormat(SlashPath))
+	if errs[0].(BadField).Field != "Names/1" {
+		t.Fatalf("wrong path for an element: %v", errs[0])
+	}
+	errs = vd.ValidateMasked(x, []string{"Ys"})
+	if len(errs) != 1 || errs[0].(BadField).Field != "Ys[2].A" {
+		t.Fatalf("wrong errors for a masked element: %v", errs)
+	}
+}
+
+func TestV_Validate_keysValues(t *testing.T) {
+	type Y struct {
+		A int `validate:"odd"`
+	}
+	type X struct {
+		Headers map[string]string `validate:"keys=long,values=long"`
+		Ys      map[int]Y         `validate:"values,struct"`
+		N       []string          `validate:"keys=long"`
+	}
+
+	vd := V{
+		"long": long,
+		"odd": func(i interface{}) error {
+			if i.(int)%2 == 0 {
+				return fmt.Errorf("%d should be odd", i)
+			}
+			return nil
+		},
+	}
+
+	x := X{
+		Headers: map[string]string{"Content-Type": "no", "X": "text"},
+		Ys:      map[int]Y{1: {1}, 2: {2}},
+	}
+	var fields []string
+	for _, err := range vd.Validate(x) {
+		fields = append(fields, err.(BadField).Field)
+	}
+	want := "[Headers[X] Headers[Content-Type] Ys[2].A N]"
+	if fmt.Sprint(fields) != want {
+		t.Fatalf("wrong invalid fields %v, wanted %v", fields, want)
+	}
+}
+
+func TestV_Validate_required(t *testing.T) {
+	type X struct {
+		A string            `validate:"required"`
+		B *int              `validate:"required"`
+		C []int             `validate:"required"`
+		D testOptional[int] `validate:"required,odd"`
+		E testOptional[int] `validate:"required"`
+		F int               `validate:"required"`
+		G []string          `validate:"each,required"`
+	}
+
+	var vd V
+	x := X{E: testOptional[int]{0, true}, F: 1, G: []string{"a", ""}}
+	var fields []string
+	for _, err := range vd.Validate(x) {
+		if !errors.Is(err.(BadField).Err, ErrRequired) {
+			t.Fatalf("wrong error for a required field: %v", err)
+		}
+		fields = append(fields, err.(BadField).Field)
+	}
+	want := "[A B C D E G[1]]"
+	if fmt.Sprint(fields) != want {
+		t.Fatalf("wrong invalid fields %v, wanted %v", fields, want)
+	}
+}
+
+func TestV_Validate_omitempty(t *testing.T) {
+	type X struct {
+		A string   `validate:"omitempty,long"`
+		B string   `validate:"omitempty,long"`
+		C *int     `validate:"omitempty,odd"`
+		D []string `validate:"each,omitempty,long"`
+		E string   `validate:"long,omitempty"`
+	}
+
+	vd := V{
+		"long": long,
+		"odd": func(i interface{}) error {
+			panic("odd called for an empty field")
+		},
+	}
+	var fields []string
+	for _, err := range vd.Validate(X{B: "no", D: []string{"", "x", "long"}}) {
+		fields = append(fields, err.(BadField).Field)
+	}
+	want := "[B D[1] E]"
+	if fmt.Sprint(fields) != want {
+		t.Fatalf("wrong invalid fields %v, wanted %v", fields, want)
+	}
+}
+
+type benchInner struct {
+	A string `validate:"nonzero"`
+	B int    `validate:"nonzero"`
+}
+
+type benchStruct struct {
+	A string     `validate:"nonzero"`
+	B string     `validate:"nonzero,email"`
+	C int        `validate:"nonzero"`
+	D []string   `validate:"each,nonzero"`
+	E benchInner `validate:"struct"`
+	F string
+	G string `json:"g"`
+}
+
+var benchValue = benchStruct{
+	A: "a",
+	B: "a@example.com",
+	C: 1,
+	D: []string{"x", "y"},
+	E: benchInner{"b", 2},
+}
+
+func BenchmarkV_Validate(b *testing.B) {
+	vd := Builtin()
+	for i := 0; i < b.N; i++ {
+		vd.Validate(benchValue)
+	}
+}
+
+func BenchmarkV_Validate_uncached(b *testing.B) {
+	vd := Builtin()
+	for i := 0; i < b.N; i++ {
+		typeCache.Clear()
+		vd.Validate(benchValue)
+	}
+}
+
+func BenchmarkSchema_Validate(b *testing.B) {
+	s, err := Builtin().Compile(reflect.TypeOf(benchValue))
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < b.N; i++ {
+		s.Validate(benchValue)
+	}
+}
+
+func TestV_ValidateGroup(t *testing.T) {
+	type User struct {
+		ID   string   `validate:"on=update patch,required"`
+		Name string   `validate:"on=create,required,on,long"`
+		Tags []string `validate:"on=patch,each,long"`
+	}
+
+	vd := V{"long": long}
+	u := User{Name: "", Tags: []string{"x"}}
+	tests := []struct {
+		groups []string
+		want   string
+	}{
+		{nil, "[Name]"},
+		{[]string{"create"}, "[Name Name]"},
+		{[]string{"update"}, "[ID Name]"},
+		{[]string{"patch", "create"}, "[ID Name Name Tags[0]]"},
+	}
+	for _, test := range tests {
+		var fields []string
+		for _, err := range vd.ValidateGroup(u, test.groups...) {
+			fields = append(fields, err.(BadField).Field)
+		}
+		if fmt.Sprint(fields) != test.want {
+			t.Fatalf("wrong invalid fields for groups %v: %v, wanted %s", test.groups, fields, test.want)
+		}
+	}
+}
+
+func TestV_Validate_msg(t *testing.T) {
+	type X struct {
+		Name string   `validate:"long,msg=name must be longer"`
+		Bio  string   `validate:"sensitive,long|nonempty,msg:be brief, or else"`
+		Tags []string `validate:"each,long,msg=tags must be longer"`
+		Bad  string   `validate:"missing,msg=unseen"`
+	}
+
+	vd := V{"long": long, "nonempty": func(interface{}) error { return errors.New("is empty") }}
+	errs := vd.Validate(X{Tags: []string{"a"}})
+	want := []string{
+		"field Name is invalid: name must be longer",
+		"field Bio is invalid: be brief, or else",
+		"field Tags[0] is invalid: tags must be longer",
+		`field Bad is invalid: undefined validator: "missing"`,
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+	for i, err := range errs {
+		if err.Error() != want[i] {
+			t.Fatalf("wrong error %d: %q, wanted %q", i, err, want[i])
+		}
+	}
+	var r redacted
+	if !errors.As(errs[1], &r) || errors.Unwrap(errors.Unwrap(errs[0])).Error() != long("").Error() {
+		t.Fatalf("original errors are not reachable: %v", errs)
+	}
+}
+
+func TestBadField_Path(t *testing.T) {
+	type Y struct {
+		A int `json:"a" validate:"odd"`
+	}
+	type X struct {
+		Ys map[string][]Y `json:"ys" validate:"values,each,struct"`
+	}
+
+	vd := V{
+		"odd": func(i interface{}) error {
+			if i.(int)%2 == 0 {
+				return fmt.Errorf("%d should be odd", i)
+			}
+			return nil
+		},
+	}
+
+	x := X{map[string][]Y{"k": {{1}, {2}}}}
+	errs := vd.ValidateOpts(x, NameTags("json"), Root("body"), PathFormat(SlashPath))
+	if len(errs) != 1 {
+		t.Fatalf("wrong number of errors: %v", errs)
+	}
+	bf := errs[0].(BadField)
+	if bf.Field != "body/ys/k/1/a" || !reflect.DeepEqual(bf.Path, Path{"body", "ys", "[k]", "[1]", "a"}) {
+		t.Fatalf("wrong path: %q %q", bf.Field, bf.Path)
+	}
+	if bf.Rule != "odd" || bf.Value != 2 {
+		t.Fatalf("wrong details: %+v", bf)
+	}
+}
+
+func TestV_Validate_panic(t *testing.T) {
+	type X struct {
+		A string `validate:"odd,nonzero"`
+		B int    `validate:"odd"`
+		C string `validate:"odd|nonzero"`
+	}
+	vd := V{
+		"odd": func(i interface{}) error {
+			if i.(int)%2 == 0 {
+				return errors.New("should be odd")
+			}
+			return nil
+		},
+		"nonzero": nonzero,
+	}
+
+	errs := vd.Validate(X{B: 3})
+	if len(errs) != 4 {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+	bf := errs[0].(BadField)
+	if bf.Field != "A" || bf.Rule != "odd" || !errors.Is(bf, ErrValidatorPanicked) ||
+		bf.Err.Error() != `validator panicked: "odd": interface conversion: interface {} is string, not int` {
+		t.Errorf("wrong error for a panic: %#v", bf)
+	}
+	if bf := errs[1].(BadField); bf.Field != "A" || bf.Rule != "nonzero" {
+		t.Errorf("rules after a panic were not checked: %v", errs[1])
+	}
+	if bf := errs[2].(BadField); bf.Field != "C" || bf.Rule != "odd" || !errors.Is(bf, ErrValidatorPanicked) {
+		t.Errorf("wrong error for a panicking alternative: %v", errs[2])
+	}
+	if bf := errs[3].(BadField); bf.Field != "C" || bf.Rule != "odd|nonzero" {
+		t.Errorf("wrong error for the other alternative: %v", errs[3])
+	}
+}
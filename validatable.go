@@ -0,0 +1,162 @@
+// © 2013 Steve McCoy under the MIT license.
+
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// Validatable is implemented by types that check their own invariants.
+// Validate calls the Validate method of each struct it validates, after
+// checking the struct's fields, and of the value of each tagged field
+// that it does not validate as a struct, reporting the error with the
+// path to the value.
+//
+// A method is not called while it is already running on the same
+// goroutine, so that one passing its receiver to Validate, as in
+//
+//	func (x X) Validate() error {
+//		return errors.Join(v.Validate(x)...)
+//	}
+//
+// is not called again by that Validate. The NoValidatable option skips
+// the methods altogether.
+type Validatable interface {
+	Validate() error
+}
+
+// FieldsValidatable is implemented by types that check their own fields,
+// and is preferred to Validatable by Validate, which calls ValidateFields
+// where it would call the Validate method. The paths of the BadFields it
+// returns, or their Fields if they have no Path, are relative to the value,
+// and are reported within the path to the value; other errors are reported
+// for the value itself.
+type FieldsValidatable interface {
+	ValidateFields() []error
+}
+
+var (
+	validatableType       = reflect.TypeOf((*Validatable)(nil)).Elem()
+	fieldsValidatableType = reflect.TypeOf((*FieldsValidatable)(nil)).Elem()
+)
+
+// checkSelf calls the Validate or ValidateFields method of the value in
+// val, found at path within a struct of type t, if it has either, and
+// appends the errors they report to errs.
+func (w *walker) checkSelf(errs []error, t reflect.Type, val reflect.Value, path []string, sensitive bool) (result []error) {
+	if w.noValidatable || w.done || w.planning || !val.IsValid() || !val.CanInterface() {
+		return errs
+	}
+	pt := reflect.PointerTo(val.Type())
+	method := ""
+	switch {
+	case pt.Implements(fieldsValidatableType):
+		method = "ValidateFields"
+	case pt.Implements(validatableType):
+		method = "Validate"
+	default:
+		return errs
+	}
+	if running(val.Type(), method) {
+		return errs
+	}
+	value := val.Interface()
+	if sensitive {
+		value = nil
+	}
+
+	// The methods of a value's address are included, as by "method",
+	// for values that cannot be addressed too.
+	if !val.CanAddr() {
+		c := reflect.New(val.Type()).Elem()
+		c.Set(val)
+		val = c
+	}
+
+	if !w.noRecover {
+		defer func() {
+			if p := recover(); p != nil {
+				var err error = fmt.Errorf("%w: %v: %v", ErrValidatorPanicked, val.Type(), p)
+				if sensitive {
+					err = redact(method, err)
+				}
+				result = w.fail(errs, t, path, BadField{Err: err, Value: value})
+			}
+		}()
+	}
+	switch x := val.Addr().Interface().(type) {
+	case FieldsValidatable:
+		for _, err := range x.ValidateFields() {
+			if w.done {
+				break
+			}
+			bf, ok := err.(BadField)
+			if !ok {
+				if sensitive {
+					err = redact(method, err)
+				}
+				errs = w.fail(errs, t, path, BadField{Err: err, Value: value})
+				continue
+			}
+			rel := bf.Path
+			if len(rel) == 0 && bf.Field != "" {
+				rel = Path{bf.Field}
+			}
+			if sensitive {
+				rule := bf.Rule
+				if rule == "" {
+					rule = method
+				}
+				bf.Err, bf.Value = redact(rule, bf.Err), nil
+			}
+			errs = w.fail(errs, t, append(path[:len(path):len(path)], rel...), bf)
+		}
+	case Validatable:
+		if err := x.Validate(); err != nil {
+			if sensitive {
+				err = redact(method, err)
+			}
+			errs = w.fail(errs, t, path, BadField{Err: err, Value: value})
+		}
+	}
+	return errs
+}
+
+// running reports whether the method called name of type t, with a value
+// or pointer receiver, is running on the current goroutine.
+func running(t reflect.Type, name string) bool {
+	if t.Name() == "" {
+		return false
+	}
+	// Frames name the methods of generic types with their type
+	// arguments elided.
+	tn := t.Name()
+	if i := strings.IndexByte(tn, '['); i >= 0 {
+		tn = tn[:i] + "[...]"
+	}
+	byValue := t.PkgPath() + "." + tn + "." + name
+	byPointer := t.PkgPath() + ".(*" + tn + ")." + name
+
+	pcs := make([]uintptr, 64)
+	for {
+		n := runtime.Callers(3, pcs)
+		if n < len(pcs) {
+			pcs = pcs[:n]
+			break
+		}
+		pcs = make([]uintptr, 2*len(pcs))
+	}
+	frames := runtime.CallersFrames(pcs)
+	for {
+		f, more := frames.Next()
+		if f.Function == byValue || f.Function == byPointer {
+			return true
+		}
+		if !more {
+			return false
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+type testEmail string
+
+func (e testEmail) Validate() error {
+	if e == "" {
+		return errors.New("should not be empty")
+	}
+	return nil
+}
+
+type testWindow struct {
+	Open  int `validate:"nonzero"`
+	Close int
+}
+
+func (w *testWindow) ValidateFields() []error {
+	var errs []error
+	if w.Close < w.Open {
+		errs = append(errs, BadField{Field: "Close", Err: errors.New("should not be before Open"), Rule: "order", Value: w.Close})
+	}
+	if w.Close-w.Open > 12 {
+		errs = append(errs, errors.New("should be at most 12 hours"))
+	}
+	return errs
+}
+
+type testPanicky struct{}
+
+func (testPanicky) Validate() error { panic("oops") }
+
+func TestValidatable(t *testing.T) {
+	type X struct {
+		Email  testEmail    `validate:"nonzero"`
+		Backup *testEmail   `validate:"omitempty"`
+		Hours  testWindow   `validate:"struct"`
+		Shifts []testWindow `validate:"each,struct"`
+		Plain  testEmail
+		Other  testPanicky `validate:"nonzero"`
+	}
+
+	vd := V{"nonzero": func(interface{}) error { return nil }}
+	errs := vd.Validate(X{
+		Hours:  testWindow{9, 30},
+		Shifts: []testWindow{{Open: 5, Close: 1}},
+	})
+	want := []string{
+		"field Email is invalid: should not be empty",
+		"field Hours is invalid: should be at most 12 hours",
+		"field Shifts[0].Close is invalid: should not be before Open",
+		"field Other is invalid: validator panicked: validate.testPanicky: oops",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+	for i, err := range errs {
+		if err.Error() != want[i] {
+			t.Errorf("error %d is %q, wanted %q", i, err, want[i])
+		}
+	}
+	if bf := errs[2].(BadField); bf.Rule != "order" || bf.Value != 1 || len(bf.Path) != 3 || bf.Path[2] != "Close" {
+		t.Errorf("wrong details for a BadField from ValidateFields: %#v", bf)
+	}
+
+	// A value passed by value still has its pointer methods called.
+	if errs := vd.Validate(testWindow{Open: 2, Close: 1}); len(errs) != 1 || errs[0].Error() != "field Close is invalid: should not be before Open" {
+		t.Fatalf("wrong errors for the value itself: %v", errs)
+	}
+
+	if errs := vd.ValidateOpts(X{}, NoValidatable()); len(errs) != 0 {
+		t.Fatalf("called methods despite NoValidatable: %v", errs)
+	}
+}
+
+type testSecret string
+
+func (s testSecret) Validate() error {
+	if len(s) < 8 {
+		return fmt.Errorf("secret %q is too short", string(s))
+	}
+	return nil
+}
+
+type testPolicy struct {
+	Name string `validate:"nonzero"`
+}
+
+func (p testPolicy) ValidateFields() []error {
+	return []error{BadField{Field: "Name", Err: fmt.Errorf("%q is taken", p.Name)}, fmt.Errorf("policy %q is bad", p.Name)}
+}
+
+type testPanickySecret string
+
+func (s testPanickySecret) Validate() error {
+	panic("bad secret " + string(s))
+}
+
+func TestValidatable_sensitive(t *testing.T) {
+	type X struct {
+		Pw     testSecret        `validate:"sensitive,nonempty"`
+		Policy testPolicy        `validate:"sensitive,nonzero"`
+		Key    testPanickySecret `validate:"sensitive"`
+	}
+
+	errs := Builtin().Validate(X{Pw: "hunter2", Policy: testPolicy{"hunter2"}, Key: "hunter2"})
+	if len(errs) != 4 {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+	for _, err := range errs {
+		if bf := err.(BadField); leaks(err, "hunter2") || bf.Value != nil {
+			t.Errorf("sensitive value in error: %v, %#v", err, bf.Value)
+		}
+	}
+	if !errors.Is(errs[3], ErrValidatorPanicked) {
+		t.Errorf("panic is not reported: %v", errs[3])
+	}
+}
+
+var testReentrantV = V{"nonzero": nonzero}
+
+type testReentrant struct {
+	N    int `validate:"nonzero"`
+	Next *testReentrant
+}
+
+func (r testReentrant) Validate() error {
+	return errors.Join(testReentrantV.Validate(r)...)
+}
+
+func TestValidatable_reentrant(t *testing.T) {
+	type X struct {
+		R testReentrant `validate:"nonzero"`
+	}
+
+	x := X{R: testReentrant{Next: &testReentrant{N: 1}}}
+	errs := testReentrantV.Validate(x)
+	if len(errs) != 1 || errs[0].Error() != "field R is invalid: field N is invalid: should be nonzero" {
+		t.Fatalf("wrong errors: %v", errs)
+	}
+
+	// The method is called on other goroutines while it runs on one.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if errs := testReentrantV.Validate(x); len(errs) != 1 {
+				t.Errorf("wrong errors: %v", errs)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func ExampleFieldsValidatable() {
+	for _, err := range Builtin().Validate(testWindow{Open: 0, Close: 13}) {
+		fmt.Println(err)
+	}
+
+	// Output: field Open is invalid: should be nonzero
+	// struct is invalid: should be at most 12 hours
+}